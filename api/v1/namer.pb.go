@@ -0,0 +1,1198 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: api/v1/namer.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{0}
+}
+
+type Status struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Version   string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Whether the Docker events stream is established.
+	DockerConnected bool `protobuf:"varint,3,opt,name=docker_connected,json=dockerConnected,proto3" json:"docker_connected,omitempty"`
+	// Number of consecutive Docker API ping failures.
+	DockerPingFailures int32 `protobuf:"varint,4,opt,name=docker_ping_failures,json=dockerPingFailures,proto3" json:"docker_ping_failures,omitempty"`
+	Paused             bool  `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"`
+	TrackedContainers  int32 `protobuf:"varint,6,opt,name=tracked_containers,json=trackedContainers,proto3" json:"tracked_containers,omitempty"`
+	TrackedLinks       int32 `protobuf:"varint,7,opt,name=tracked_links,json=trackedLinks,proto3" json:"tracked_links,omitempty"`
+	PendingTasks       int32 `protobuf:"varint,8,opt,name=pending_tasks,json=pendingTasks,proto3" json:"pending_tasks,omitempty"`
+	// Number of the containers with pending tasks waiting for a free worker.
+	QueuedContainers int32                  `protobuf:"varint,9,opt,name=queued_containers,json=queuedContainers,proto3" json:"queued_containers,omitempty"`
+	PendingRetries   int64                  `protobuf:"varint,10,opt,name=pending_retries,json=pendingRetries,proto3" json:"pending_retries,omitempty"`
+	LastEventTime    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_event_time,json=lastEventTime,proto3" json:"last_event_time,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_api_v1_namer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Status) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Status) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Status) GetDockerConnected() bool {
+	if x != nil {
+		return x.DockerConnected
+	}
+	return false
+}
+
+func (x *Status) GetDockerPingFailures() int32 {
+	if x != nil {
+		return x.DockerPingFailures
+	}
+	return 0
+}
+
+func (x *Status) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Status) GetTrackedContainers() int32 {
+	if x != nil {
+		return x.TrackedContainers
+	}
+	return 0
+}
+
+func (x *Status) GetTrackedLinks() int32 {
+	if x != nil {
+		return x.TrackedLinks
+	}
+	return 0
+}
+
+func (x *Status) GetPendingTasks() int32 {
+	if x != nil {
+		return x.PendingTasks
+	}
+	return 0
+}
+
+func (x *Status) GetQueuedContainers() int32 {
+	if x != nil {
+		return x.QueuedContainers
+	}
+	return 0
+}
+
+func (x *Status) GetPendingRetries() int64 {
+	if x != nil {
+		return x.PendingRetries
+	}
+	return 0
+}
+
+func (x *Status) GetLastEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastEventTime
+	}
+	return nil
+}
+
+type ListMappingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMappingsRequest) Reset() {
+	*x = ListMappingsRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMappingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMappingsRequest) ProtoMessage() {}
+
+func (x *ListMappingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMappingsRequest.ProtoReflect.Descriptor instead.
+func (*ListMappingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{2}
+}
+
+type ListMappingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Containers    []*ContainerMapping    `protobuf:"bytes,1,rep,name=containers,proto3" json:"containers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMappingsResponse) Reset() {
+	*x = ListMappingsResponse{}
+	mi := &file_api_v1_namer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMappingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMappingsResponse) ProtoMessage() {}
+
+func (x *ListMappingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMappingsResponse.ProtoReflect.Descriptor instead.
+func (*ListMappingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListMappingsResponse) GetContainers() []*ContainerMapping {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+// Container and its renamed host links.
+type ContainerMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Links         []*Link                `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerMapping) Reset() {
+	*x = ContainerMapping{}
+	mi := &file_api_v1_namer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerMapping) ProtoMessage() {}
+
+func (x *ContainerMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerMapping.ProtoReflect.Descriptor instead.
+func (*ContainerMapping) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ContainerMapping) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ContainerMapping) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerMapping) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+// Renamed host link.
+type Link struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Ifindex int32                  `protobuf:"varint,1,opt,name=ifindex,proto3" json:"ifindex,omitempty"`
+	// Name of the peer link within the container.
+	ContainerLink string `protobuf:"bytes,2,opt,name=container_link,json=containerLink,proto3" json:"container_link,omitempty"`
+	// Name of the host link before renaming.
+	OriginalName string `protobuf:"bytes,3,opt,name=original_name,json=originalName,proto3" json:"original_name,omitempty"`
+	// Name assigned to the host link.
+	Name          string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Image         string `protobuf:"bytes,5,opt,name=image,proto3" json:"image,omitempty"`
+	Network       string `protobuf:"bytes,6,opt,name=network,proto3" json:"network,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_api_v1_namer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Link) GetIfindex() int32 {
+	if x != nil {
+		return x.Ifindex
+	}
+	return 0
+}
+
+func (x *Link) GetContainerLink() string {
+	if x != nil {
+		return x.ContainerLink
+	}
+	return ""
+}
+
+func (x *Link) GetOriginalName() string {
+	if x != nil {
+		return x.OriginalName
+	}
+	return ""
+}
+
+func (x *Link) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Link) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Link) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+type ListHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Container name or ID, or its prefix. Empty selects all records.
+	Container string `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	// Number of the most recent records. Zero selects all records.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryRequest) Reset() {
+	*x = ListHistoryRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryRequest) ProtoMessage() {}
+
+func (x *ListHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListHistoryRequest) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*RenameRecord        `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryResponse) Reset() {
+	*x = ListHistoryResponse{}
+	mi := &file_api_v1_namer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryResponse) ProtoMessage() {}
+
+func (x *ListHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListHistoryResponse) GetRecords() []*RenameRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// Rename operation on the host link.
+type RenameRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Operation: rename, or restore.
+	Operation     string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	ContainerId   string `protobuf:"bytes,3,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	ContainerName string `protobuf:"bytes,4,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	ContainerLink string `protobuf:"bytes,5,opt,name=container_link,json=containerLink,proto3" json:"container_link,omitempty"`
+	Ifindex       int32  `protobuf:"varint,6,opt,name=ifindex,proto3" json:"ifindex,omitempty"`
+	OldName       string `protobuf:"bytes,7,opt,name=old_name,json=oldName,proto3" json:"old_name,omitempty"`
+	NewName       string `protobuf:"bytes,8,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	// Empty on success.
+	Error         string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	DryRun        bool   `protobuf:"varint,10,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameRecord) Reset() {
+	*x = RenameRecord{}
+	mi := &file_api_v1_namer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameRecord) ProtoMessage() {}
+
+func (x *RenameRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameRecord.ProtoReflect.Descriptor instead.
+func (*RenameRecord) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RenameRecord) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RenameRecord) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetContainerLink() string {
+	if x != nil {
+		return x.ContainerLink
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetIfindex() int32 {
+	if x != nil {
+		return x.Ifindex
+	}
+	return 0
+}
+
+func (x *RenameRecord) GetOldName() string {
+	if x != nil {
+		return x.OldName
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RenameRecord) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{9}
+}
+
+// Event of the daemon.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type: rename, rename_failed, restore, restore_failed, skip, mapping_added, mapping_removed, or link_flapping.
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	ContainerId   string                 `protobuf:"bytes,3,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	ContainerName string                 `protobuf:"bytes,4,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	ContainerLink string                 `protobuf:"bytes,5,opt,name=container_link,json=containerLink,proto3" json:"container_link,omitempty"`
+	Ifindex       int32                  `protobuf:"varint,6,opt,name=ifindex,proto3" json:"ifindex,omitempty"`
+	OldName       string                 `protobuf:"bytes,7,opt,name=old_name,json=oldName,proto3" json:"old_name,omitempty"`
+	NewName       string                 `protobuf:"bytes,8,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	// Reason of skipping.
+	Reason        string `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	DryRun        bool   `protobuf:"varint,11,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_api_v1_namer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *Event) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *Event) GetContainerLink() string {
+	if x != nil {
+		return x.ContainerLink
+	}
+	return ""
+}
+
+func (x *Event) GetIfindex() int32 {
+	if x != nil {
+		return x.Ifindex
+	}
+	return 0
+}
+
+func (x *Event) GetOldName() string {
+	if x != nil {
+		return x.OldName
+	}
+	return ""
+}
+
+func (x *Event) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *Event) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Event) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Event) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ResyncRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResyncRequest) Reset() {
+	*x = ResyncRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResyncRequest) ProtoMessage() {}
+
+func (x *ResyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResyncRequest.ProtoReflect.Descriptor instead.
+func (*ResyncRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{11}
+}
+
+type ResyncResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResyncResponse) Reset() {
+	*x = ResyncResponse{}
+	mi := &file_api_v1_namer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResyncResponse) ProtoMessage() {}
+
+func (x *ResyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResyncResponse.ProtoReflect.Descriptor instead.
+func (*ResyncResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{12}
+}
+
+type RevertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Container name or ID, or its prefix.
+	Container     string `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertRequest) Reset() {
+	*x = RevertRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertRequest) ProtoMessage() {}
+
+func (x *RevertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertRequest.ProtoReflect.Descriptor instead.
+func (*RevertRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevertRequest) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+type RevertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertResponse) Reset() {
+	*x = RevertResponse{}
+	mi := &file_api_v1_namer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertResponse) ProtoMessage() {}
+
+func (x *RevertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertResponse.ProtoReflect.Descriptor instead.
+func (*RevertResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{14}
+}
+
+type SetPausedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPausedRequest) Reset() {
+	*x = SetPausedRequest{}
+	mi := &file_api_v1_namer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPausedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPausedRequest) ProtoMessage() {}
+
+func (x *SetPausedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPausedRequest.ProtoReflect.Descriptor instead.
+func (*SetPausedRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetPausedRequest) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type SetPausedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPausedResponse) Reset() {
+	*x = SetPausedResponse{}
+	mi := &file_api_v1_namer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPausedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPausedResponse) ProtoMessage() {}
+
+func (x *SetPausedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_namer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPausedResponse.ProtoReflect.Descriptor instead.
+func (*SetPausedResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_namer_proto_rawDescGZIP(), []int{16}
+}
+
+var File_api_v1_namer_proto protoreflect.FileDescriptor
+
+const file_api_v1_namer_proto_rawDesc = "" +
+	"\n" +
+	"\x12api/v1/namer.proto\x12\x12dockervethnamer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10GetStatusRequest\"\xe5\x03\n" +
+	"\x06Status\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12)\n" +
+	"\x10docker_connected\x18\x03 \x01(\bR\x0fdockerConnected\x120\n" +
+	"\x14docker_ping_failures\x18\x04 \x01(\x05R\x12dockerPingFailures\x12\x16\n" +
+	"\x06paused\x18\x05 \x01(\bR\x06paused\x12-\n" +
+	"\x12tracked_containers\x18\x06 \x01(\x05R\x11trackedContainers\x12#\n" +
+	"\rtracked_links\x18\a \x01(\x05R\ftrackedLinks\x12#\n" +
+	"\rpending_tasks\x18\b \x01(\x05R\fpendingTasks\x12+\n" +
+	"\x11queued_containers\x18\t \x01(\x05R\x10queuedContainers\x12'\n" +
+	"\x0fpending_retries\x18\n" +
+	" \x01(\x03R\x0ependingRetries\x12B\n" +
+	"\x0flast_event_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rlastEventTime\"\x15\n" +
+	"\x13ListMappingsRequest\"\\\n" +
+	"\x14ListMappingsResponse\x12D\n" +
+	"\n" +
+	"containers\x18\x01 \x03(\v2$.dockervethnamer.v1.ContainerMappingR\n" +
+	"containers\"f\n" +
+	"\x10ContainerMapping\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x05links\x18\x03 \x03(\v2\x18.dockervethnamer.v1.LinkR\x05links\"\xb0\x01\n" +
+	"\x04Link\x12\x18\n" +
+	"\aifindex\x18\x01 \x01(\x05R\aifindex\x12%\n" +
+	"\x0econtainer_link\x18\x02 \x01(\tR\rcontainerLink\x12#\n" +
+	"\roriginal_name\x18\x03 \x01(\tR\foriginalName\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x05 \x01(\tR\x05image\x12\x18\n" +
+	"\anetwork\x18\x06 \x01(\tR\anetwork\"H\n" +
+	"\x12ListHistoryRequest\x12\x1c\n" +
+	"\tcontainer\x18\x01 \x01(\tR\tcontainer\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"Q\n" +
+	"\x13ListHistoryResponse\x12:\n" +
+	"\arecords\x18\x01 \x03(\v2 .dockervethnamer.v1.RenameRecordR\arecords\"\xcc\x02\n" +
+	"\fRenameRecord\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12!\n" +
+	"\fcontainer_id\x18\x03 \x01(\tR\vcontainerId\x12%\n" +
+	"\x0econtainer_name\x18\x04 \x01(\tR\rcontainerName\x12%\n" +
+	"\x0econtainer_link\x18\x05 \x01(\tR\rcontainerLink\x12\x18\n" +
+	"\aifindex\x18\x06 \x01(\x05R\aifindex\x12\x19\n" +
+	"\bold_name\x18\a \x01(\tR\aoldName\x12\x19\n" +
+	"\bnew_name\x18\b \x01(\tR\anewName\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x17\n" +
+	"\adry_run\x18\n" +
+	" \x01(\bR\x06dryRun\"\x14\n" +
+	"\x12WatchEventsRequest\"\xd3\x02\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12!\n" +
+	"\fcontainer_id\x18\x03 \x01(\tR\vcontainerId\x12%\n" +
+	"\x0econtainer_name\x18\x04 \x01(\tR\rcontainerName\x12%\n" +
+	"\x0econtainer_link\x18\x05 \x01(\tR\rcontainerLink\x12\x18\n" +
+	"\aifindex\x18\x06 \x01(\x05R\aifindex\x12\x19\n" +
+	"\bold_name\x18\a \x01(\tR\aoldName\x12\x19\n" +
+	"\bnew_name\x18\b \x01(\tR\anewName\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x17\n" +
+	"\adry_run\x18\v \x01(\bR\x06dryRun\"\x0f\n" +
+	"\rResyncRequest\"\x10\n" +
+	"\x0eResyncResponse\"-\n" +
+	"\rRevertRequest\x12\x1c\n" +
+	"\tcontainer\x18\x01 \x01(\tR\tcontainer\"\x10\n" +
+	"\x0eRevertResponse\"*\n" +
+	"\x10SetPausedRequest\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\"\x13\n" +
+	"\x11SetPausedResponse2\xe9\x04\n" +
+	"\x05Namer\x12M\n" +
+	"\tGetStatus\x12$.dockervethnamer.v1.GetStatusRequest\x1a\x1a.dockervethnamer.v1.Status\x12a\n" +
+	"\fListMappings\x12'.dockervethnamer.v1.ListMappingsRequest\x1a(.dockervethnamer.v1.ListMappingsResponse\x12^\n" +
+	"\vListHistory\x12&.dockervethnamer.v1.ListHistoryRequest\x1a'.dockervethnamer.v1.ListHistoryResponse\x12R\n" +
+	"\vWatchEvents\x12&.dockervethnamer.v1.WatchEventsRequest\x1a\x19.dockervethnamer.v1.Event0\x01\x12O\n" +
+	"\x06Resync\x12!.dockervethnamer.v1.ResyncRequest\x1a\".dockervethnamer.v1.ResyncResponse\x12O\n" +
+	"\x06Revert\x12!.dockervethnamer.v1.RevertRequest\x1a\".dockervethnamer.v1.RevertResponse\x12X\n" +
+	"\tSetPaused\x12$.dockervethnamer.v1.SetPausedRequest\x1a%.dockervethnamer.v1.SetPausedResponseB2Z0github.com/a-ilin/docker-veth-namer/api/v1;apiv1b\x06proto3"
+
+var (
+	file_api_v1_namer_proto_rawDescOnce sync.Once
+	file_api_v1_namer_proto_rawDescData []byte
+)
+
+func file_api_v1_namer_proto_rawDescGZIP() []byte {
+	file_api_v1_namer_proto_rawDescOnce.Do(func() {
+		file_api_v1_namer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_namer_proto_rawDesc), len(file_api_v1_namer_proto_rawDesc)))
+	})
+	return file_api_v1_namer_proto_rawDescData
+}
+
+var file_api_v1_namer_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_v1_namer_proto_goTypes = []any{
+	(*GetStatusRequest)(nil),      // 0: dockervethnamer.v1.GetStatusRequest
+	(*Status)(nil),                // 1: dockervethnamer.v1.Status
+	(*ListMappingsRequest)(nil),   // 2: dockervethnamer.v1.ListMappingsRequest
+	(*ListMappingsResponse)(nil),  // 3: dockervethnamer.v1.ListMappingsResponse
+	(*ContainerMapping)(nil),      // 4: dockervethnamer.v1.ContainerMapping
+	(*Link)(nil),                  // 5: dockervethnamer.v1.Link
+	(*ListHistoryRequest)(nil),    // 6: dockervethnamer.v1.ListHistoryRequest
+	(*ListHistoryResponse)(nil),   // 7: dockervethnamer.v1.ListHistoryResponse
+	(*RenameRecord)(nil),          // 8: dockervethnamer.v1.RenameRecord
+	(*WatchEventsRequest)(nil),    // 9: dockervethnamer.v1.WatchEventsRequest
+	(*Event)(nil),                 // 10: dockervethnamer.v1.Event
+	(*ResyncRequest)(nil),         // 11: dockervethnamer.v1.ResyncRequest
+	(*ResyncResponse)(nil),        // 12: dockervethnamer.v1.ResyncResponse
+	(*RevertRequest)(nil),         // 13: dockervethnamer.v1.RevertRequest
+	(*RevertResponse)(nil),        // 14: dockervethnamer.v1.RevertResponse
+	(*SetPausedRequest)(nil),      // 15: dockervethnamer.v1.SetPausedRequest
+	(*SetPausedResponse)(nil),     // 16: dockervethnamer.v1.SetPausedResponse
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_api_v1_namer_proto_depIdxs = []int32{
+	17, // 0: dockervethnamer.v1.Status.start_time:type_name -> google.protobuf.Timestamp
+	17, // 1: dockervethnamer.v1.Status.last_event_time:type_name -> google.protobuf.Timestamp
+	4,  // 2: dockervethnamer.v1.ListMappingsResponse.containers:type_name -> dockervethnamer.v1.ContainerMapping
+	5,  // 3: dockervethnamer.v1.ContainerMapping.links:type_name -> dockervethnamer.v1.Link
+	8,  // 4: dockervethnamer.v1.ListHistoryResponse.records:type_name -> dockervethnamer.v1.RenameRecord
+	17, // 5: dockervethnamer.v1.RenameRecord.time:type_name -> google.protobuf.Timestamp
+	17, // 6: dockervethnamer.v1.Event.time:type_name -> google.protobuf.Timestamp
+	0,  // 7: dockervethnamer.v1.Namer.GetStatus:input_type -> dockervethnamer.v1.GetStatusRequest
+	2,  // 8: dockervethnamer.v1.Namer.ListMappings:input_type -> dockervethnamer.v1.ListMappingsRequest
+	6,  // 9: dockervethnamer.v1.Namer.ListHistory:input_type -> dockervethnamer.v1.ListHistoryRequest
+	9,  // 10: dockervethnamer.v1.Namer.WatchEvents:input_type -> dockervethnamer.v1.WatchEventsRequest
+	11, // 11: dockervethnamer.v1.Namer.Resync:input_type -> dockervethnamer.v1.ResyncRequest
+	13, // 12: dockervethnamer.v1.Namer.Revert:input_type -> dockervethnamer.v1.RevertRequest
+	15, // 13: dockervethnamer.v1.Namer.SetPaused:input_type -> dockervethnamer.v1.SetPausedRequest
+	1,  // 14: dockervethnamer.v1.Namer.GetStatus:output_type -> dockervethnamer.v1.Status
+	3,  // 15: dockervethnamer.v1.Namer.ListMappings:output_type -> dockervethnamer.v1.ListMappingsResponse
+	7,  // 16: dockervethnamer.v1.Namer.ListHistory:output_type -> dockervethnamer.v1.ListHistoryResponse
+	10, // 17: dockervethnamer.v1.Namer.WatchEvents:output_type -> dockervethnamer.v1.Event
+	12, // 18: dockervethnamer.v1.Namer.Resync:output_type -> dockervethnamer.v1.ResyncResponse
+	14, // 19: dockervethnamer.v1.Namer.Revert:output_type -> dockervethnamer.v1.RevertResponse
+	16, // 20: dockervethnamer.v1.Namer.SetPaused:output_type -> dockervethnamer.v1.SetPausedResponse
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_namer_proto_init() }
+func file_api_v1_namer_proto_init() {
+	if File_api_v1_namer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_namer_proto_rawDesc), len(file_api_v1_namer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_namer_proto_goTypes,
+		DependencyIndexes: file_api_v1_namer_proto_depIdxs,
+		MessageInfos:      file_api_v1_namer_proto_msgTypes,
+	}.Build()
+	File_api_v1_namer_proto = out.File
+	file_api_v1_namer_proto_goTypes = nil
+	file_api_v1_namer_proto_depIdxs = nil
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Events counted by the alerting rules.
+const (
+	AlertEventRenameFailures   = "rename_failures"
+	AlertEventLinkFlaps        = "link_flaps"
+	AlertEventDockerReconnects = "docker_reconnects"
+)
+
+var alertEvents = []string{AlertEventRenameFailures, AlertEventLinkFlaps, AlertEventDockerReconnects}
+
+// Actions of the fired alerts.
+const (
+	AlertActionLog     = "log"
+	AlertActionWebhook = "webhook"
+	AlertActionExec    = "exec"
+)
+
+var alertActions = []string{AlertActionLog, AlertActionWebhook, AlertActionExec}
+
+// Timeout of the exec alert action.
+const alertExecTimeout = 30 * time.Second
+
+// Rule firing the alert when the number of events within the window reaches the threshold.
+type AlertRule struct {
+	Name string `yaml:"name"`
+	// Counted event: rename_failures, link_flaps, or docker_reconnects.
+	Event     string        `yaml:"event"`
+	Threshold int           `yaml:"threshold"`
+	Window    time.Duration `yaml:"window"`
+	// Action: log, webhook, or exec.
+	Action string `yaml:"action"`
+	// URL of the webhook action, or shell command of the exec action.
+	Target string `yaml:"target"`
+}
+
+// Alert fired by the rule.
+type Alert struct {
+	Name      string    `json:"name"`
+	Event     string    `json:"event"`
+	Count     int       `json:"count"`
+	Threshold int       `json:"threshold"`
+	Window    string    `json:"window"`
+	Time      time.Time `json:"time"`
+	Host      string    `json:"host"`
+}
+
+// Validates the alerting rules.
+func validateAlertRules(rules []AlertRule) []error {
+	var errs []error
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		if len(rule.Name) == 0 {
+			errs = append(errs, fmt.Errorf("alerts[%d] must have a name", i))
+		} else if names[rule.Name] {
+			errs = append(errs, fmt.Errorf("alerts[%d] name is duplicated: %s", i, rule.Name))
+		}
+		names[rule.Name] = true
+
+		if !slices.Contains(alertEvents, rule.Event) {
+			errs = append(errs, fmt.Errorf("alerts[%d] event is unknown: %q, expected one of %v", i, rule.Event, alertEvents))
+		}
+		if rule.Threshold < 1 {
+			errs = append(errs, fmt.Errorf("alerts[%d] threshold must be positive: %d", i, rule.Threshold))
+		}
+		if rule.Window <= 0 {
+			errs = append(errs, fmt.Errorf("alerts[%d] window must be positive: %s", i, rule.Window))
+		}
+
+		switch rule.Action {
+		case AlertActionLog:
+		case AlertActionWebhook:
+			if u, err := url.Parse(rule.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+				errs = append(errs, fmt.Errorf("alerts[%d] target must be an HTTP URL: %q", i, rule.Target))
+			}
+		case AlertActionExec:
+			if len(rule.Target) == 0 {
+				errs = append(errs, fmt.Errorf("alerts[%d] target must be a command", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("alerts[%d] action is unknown: %q, expected one of %v", i, rule.Action, alertActions))
+		}
+	}
+	return errs
+}
+
+// Counts the events within the windows of the alerting rules.
+type Alerter struct {
+	mu sync.Mutex
+	// Times of the recent events, by event.
+	events map[string][]time.Time
+	// Time of the last firing, by rule name.
+	fired map[string]time.Time
+}
+
+var alerter = newAlerter()
+
+func newAlerter() *Alerter {
+	return &Alerter{events: make(map[string][]time.Time), fired: make(map[string]time.Time)}
+}
+
+// Records the event, and returns the alerts of the rules breached.
+// A rule fires at most once per its window.
+func (a *Alerter) Record(event string, now time.Time, rules []AlertRule) []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var maxWindow time.Duration
+	for _, rule := range rules {
+		if rule.Event == event {
+			maxWindow = max(maxWindow, rule.Window)
+		}
+	}
+	if maxWindow == 0 {
+		return nil
+	}
+
+	times := slices.DeleteFunc(a.events[event], func(t time.Time) bool { return !t.After(now.Add(-maxWindow)) })
+	times = append(times, now)
+	a.events[event] = times
+
+	var alerts []Alert
+	for _, rule := range rules {
+		if rule.Event != event {
+			continue
+		}
+
+		windowStart := now.Add(-rule.Window)
+		count := len(times) - slices.IndexFunc(times, func(t time.Time) bool { return t.After(windowStart) })
+		if count < rule.Threshold {
+			continue
+		}
+		if fired, ok := a.fired[rule.Name]; ok && fired.After(windowStart) {
+			continue
+		}
+		a.fired[rule.Name] = now
+
+		alerts = append(alerts, Alert{
+			Name:      rule.Name,
+			Event:     event,
+			Count:     count,
+			Threshold: rule.Threshold,
+			Window:    rule.Window.String(),
+			Time:      now,
+		})
+	}
+	return alerts
+}
+
+// Drops the event times outside the windows of the rules, and the firings of the rules which are no longer configured
+// or are outside their windows. Returns the number of the dropped entries.
+func (a *Alerter) Prune(now time.Time, rules []AlertRule) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	windows := make(map[string]time.Duration)
+	eventWindows := make(map[string]time.Duration)
+	for _, rule := range rules {
+		windows[rule.Name] = rule.Window
+		eventWindows[rule.Event] = max(eventWindows[rule.Event], rule.Window)
+	}
+
+	dropped := 0
+	for event, times := range a.events {
+		count := len(times)
+		times = slices.DeleteFunc(times, func(t time.Time) bool { return !t.After(now.Add(-eventWindows[event])) })
+		dropped += count - len(times)
+		if len(times) == 0 {
+			delete(a.events, event)
+		} else {
+			a.events[event] = times
+		}
+	}
+
+	for name, fired := range a.fired {
+		if window, ok := windows[name]; !ok || !fired.After(now.Add(-window)) {
+			delete(a.fired, name)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Records the event, and fires the alerts of the configured rules breached.
+func recordAlertEvent(event string) {
+	rules := config.Alerts
+	for _, alert := range alerter.Record(event, time.Now(), rules) {
+		index := slices.IndexFunc(rules, func(r AlertRule) bool { return r.Name == alert.Name })
+		go fireAlert(rules[index], alert)
+	}
+}
+
+// Runs the action of the rule for the alert.
+func fireAlert(rule AlertRule, alert Alert) {
+	alert.Host, _ = os.Hostname()
+	logger := log.WithFields(log.Fields{"alert": alert.Name, "event": alert.Event, "count": alert.Count})
+
+	switch rule.Action {
+	case AlertActionLog:
+		logger.Errorf("Alert %s fired: %d %s within %s, threshold %d", alert.Name, alert.Count, alert.Event, alert.Window, alert.Threshold)
+
+	case AlertActionWebhook:
+		body, err := json.Marshal(alert)
+		if err != nil {
+			log.Errorf("json.Marshal to bytes failed: %s", err)
+			return
+		}
+
+		client := &http.Client{Timeout: webhookTimeout}
+		resp, err := client.Post(rule.Target, "application/json", bytes.NewReader(body))
+		if err != nil {
+			logger.Errorf("Alert webhook request failed: %s: %s", rule.Target, err)
+			return
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			logger.Errorf("Alert webhook request failed: %s: %s", rule.Target, resp.Status)
+		}
+
+	case AlertActionExec:
+		ctx, cancel := context.WithTimeout(context.Background(), alertExecTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", rule.Target)
+		cmd.Env = append(os.Environ(),
+			"DVN_ALERT_NAME="+alert.Name,
+			"DVN_ALERT_EVENT="+alert.Event,
+			"DVN_ALERT_COUNT="+strconv.Itoa(alert.Count),
+			"DVN_ALERT_THRESHOLD="+strconv.Itoa(alert.Threshold),
+			"DVN_ALERT_WINDOW="+alert.Window,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			logger.Errorf("Alert command failed: %s: %s: %s", rule.Target, err, bytes.TrimSpace(output))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlerterRecord(t *testing.T) {
+	rules := []AlertRule{
+		{Name: "failures", Event: AlertEventRenameFailures, Threshold: 3, Window: time.Minute, Action: AlertActionLog},
+		{Name: "failures-burst", Event: AlertEventRenameFailures, Threshold: 2, Window: time.Second, Action: AlertActionLog},
+		{Name: "reconnects", Event: AlertEventDockerReconnects, Threshold: 1, Window: time.Minute, Action: AlertActionLog},
+	}
+
+	a := newAlerter()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Empty(t, a.Record(AlertEventRenameFailures, base, rules))
+	assert.Empty(t, a.Record(AlertEventRenameFailures, base.Add(10*time.Second), rules))
+
+	alerts := a.Record(AlertEventRenameFailures, base.Add(20*time.Second), rules)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "failures", alerts[0].Name)
+		assert.Equal(t, 3, alerts[0].Count)
+	}
+
+	// Fired at most once per window.
+	alerts = a.Record(AlertEventRenameFailures, base.Add(20*time.Second+500*time.Millisecond), rules)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "failures-burst", alerts[0].Name)
+	}
+	assert.Empty(t, a.Record(AlertEventRenameFailures, base.Add(30*time.Second), rules))
+
+	// Events outside the window are not counted.
+	assert.Empty(t, a.Record(AlertEventRenameFailures, base.Add(5*time.Minute), rules))
+
+	assert.Empty(t, a.Record(AlertEventLinkFlaps, base, rules))
+	assert.Len(t, a.Record(AlertEventDockerReconnects, base, rules), 1)
+}
+
+func TestValidateAlertRules(t *testing.T) {
+	assert.Empty(t, validateAlertRules([]AlertRule{
+		{Name: "a", Event: AlertEventLinkFlaps, Threshold: 1, Window: time.Minute, Action: AlertActionExec, Target: "logger flapping"},
+		{Name: "b", Event: AlertEventLinkFlaps, Threshold: 1, Window: time.Minute, Action: AlertActionWebhook, Target: "http://localhost/hook"},
+	}))
+
+	errs := validateAlertRules([]AlertRule{
+		{Name: "a", Event: "unknown", Threshold: 0, Window: 0, Action: AlertActionWebhook, Target: "localhost"},
+		{Name: "a", Event: AlertEventLinkFlaps, Threshold: 1, Window: time.Minute, Action: "mail"},
+	})
+	assert.Len(t, errs, 6)
+}
+
+func TestAlerterPrune(t *testing.T) {
+	rules := []AlertRule{
+		{Name: "reconnects", Event: AlertEventDockerReconnects, Threshold: 1, Window: time.Minute, Action: AlertActionLog},
+	}
+
+	a := newAlerter()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Len(t, a.Record(AlertEventDockerReconnects, base, rules), 1)
+
+	// Within the window.
+	assert.Zero(t, a.Prune(base.Add(time.Second), rules))
+
+	// The event time and the firing are outside the window.
+	assert.Equal(t, 2, a.Prune(base.Add(time.Minute), rules))
+	assert.Empty(t, a.events)
+	assert.Empty(t, a.fired)
+
+	// The rule is no longer configured.
+	require.Len(t, a.Record(AlertEventDockerReconnects, base, rules), 1)
+	assert.Equal(t, 2, a.Prune(base, nil))
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Path prefix of the REST API endpoints.
+const apiPathPrefix = "/api/v1"
+
+// TCP address of the REST API, specified on the command line. Empty when the API is disabled.
+var apiListenAddress string
+
+// Serves the control requests as the REST API on a TCP address, for the host agents integrating with the daemon.
+type APIServer struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Starts serving the REST API at the TCP address. Requests are passed to the event loop.
+func newAPIServer(address string, l *EventLoop) (*APIServer, error) {
+	listener, err := listenTCP(address)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(apiPathPrefix+"/", apiAuthHandler(http.StripPrefix(apiPathPrefix, controlHandler(l))))
+
+	s := &APIServer{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: controlRequestTimeout},
+		listener: listener,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("API server failed: %s", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Stops serving. Nil server is ignored.
+func (s *APIServer) Close() {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlRequestTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Errorf("API server shutdown failed: %s", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	apiv1 "github.com/a-ilin/docker-veth-namer/api/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scopes of the API tokens.
+const (
+	// Queries only.
+	APIScopeRead = "read"
+	// Queries and actions.
+	APIScopeAdmin = "admin"
+)
+
+// Token authenticating the clients of the REST and gRPC APIs.
+type APIToken struct {
+	// Name of the client, logged on the denied requests.
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+	// Scope of the token: read, or admin.
+	Scope string `yaml:"scope"`
+}
+
+// gRPC methods requiring the admin scope. Other methods are queries.
+var grpcAdminMethods = map[string]bool{
+	apiv1.Namer_Resync_FullMethodName:    true,
+	apiv1.Namer_Revert_FullMethodName:    true,
+	apiv1.Namer_SetPaused_FullMethodName: true,
+}
+
+// Returns the token matching the secret, or nil. The secrets are compared in constant time.
+func findAPIToken(tokens []APIToken, secret string) *APIToken {
+	if len(secret) == 0 {
+		return nil
+	}
+
+	var found *APIToken
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(tokens[i].Token), []byte(secret)) == 1 {
+			found = &tokens[i]
+		}
+	}
+	return found
+}
+
+// Returns the secret of the "Authorization: Bearer" header value, or the "X-API-Key" header value.
+func apiSecret(authorization string, apiKey string) string {
+	if scheme, secret, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(secret)
+	}
+	return apiKey
+}
+
+// Checks the secret against the configured tokens. Access is granted to everyone when no tokens are configured.
+// Returns whether the client is authenticated, and whether it is authorized for the action.
+// The tokens are read under the configuration read lock, as the requests are served concurrently with the reload.
+func authorizeAPI(secret string, admin bool) (authenticated bool, authorized bool, name string) {
+	configMu.RLock()
+	tokens := config.APITokens
+	configMu.RUnlock()
+
+	if len(tokens) == 0 {
+		return true, true, ""
+	}
+
+	token := findAPIToken(tokens, secret)
+	if token == nil {
+		return false, false, ""
+	}
+	return true, !admin || token.Scope == APIScopeAdmin, token.Name
+}
+
+// Requires the API token for the requests when the tokens are configured.
+// Queries by GET require the read scope, other requests require the admin scope.
+func apiAuthHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		admin := r.Method != http.MethodGet && r.Method != http.MethodHead
+		authenticated, authorized, name := authorizeAPI(apiSecret(r.Header.Get("Authorization"), r.Header.Get("X-API-Key")), admin)
+		if !authenticated {
+			log.Warnf("API request denied, invalid token: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if !authorized {
+			log.Warnf("API request denied, admin scope required: %s %s by %s", r.Method, r.URL.Path, name)
+			http.Error(w, "admin scope required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Checks the API token of the gRPC call against the configured tokens.
+func authorizeGRPC(ctx context.Context, method string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	authenticated, authorized, name := authorizeAPI(apiSecret(first("authorization"), first("x-api-key")), grpcAdminMethods[method])
+	if !authenticated {
+		log.Warnf("gRPC call denied, invalid token: %s", method)
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if !authorized {
+		log.Warnf("gRPC call denied, admin scope required: %s by %s", method, name)
+		return status.Error(codes.PermissionDenied, "admin scope required")
+	}
+	return nil
+}
+
+// Requires the API token for the unary gRPC calls when the tokens are configured.
+func grpcAuthUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := authorizeGRPC(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Requires the API token for the streaming gRPC calls when the tokens are configured.
+func grpcAuthStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorizeGRPC(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	apiv1 "github.com/a-ilin/docker-veth-namer/api/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIAuthHandler(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config.APITokens = nil
+
+	handler := apiAuthHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	request := func(method string, header string, value string) int {
+		r := httptest.NewRequest(method, "/status", nil)
+		if len(header) > 0 {
+			r.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Not authenticated without tokens.
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "", ""))
+
+	config.APITokens = []APIToken{
+		{Name: "monitoring", Token: "reader", Scope: APIScopeRead},
+		{Name: "ops", Token: "writer", Scope: APIScopeAdmin},
+	}
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "", ""))
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "Authorization", "Bearer wrong"))
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "Authorization", "Bearer reader"))
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "X-API-Key", "reader"))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "Authorization", "Bearer reader"))
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "Authorization", "bearer writer"))
+}
+
+func TestAuthorizeGRPC(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config.APITokens = []APIToken{
+		{Name: "monitoring", Token: "reader", Scope: APIScopeRead},
+		{Name: "ops", Token: "writer", Scope: APIScopeAdmin},
+	}
+
+	call := func(method string, pairs ...string) codes.Code {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+		return status.Code(authorizeGRPC(ctx, method))
+	}
+
+	assert.Equal(t, codes.Unauthenticated, call(apiv1.Namer_GetStatus_FullMethodName))
+	assert.Equal(t, codes.OK, call(apiv1.Namer_GetStatus_FullMethodName, "authorization", "Bearer reader"))
+	assert.Equal(t, codes.OK, call(apiv1.Namer_WatchEvents_FullMethodName, "x-api-key", "reader"))
+	assert.Equal(t, codes.PermissionDenied, call(apiv1.Namer_Resync_FullMethodName, "authorization", "Bearer reader"))
+	assert.Equal(t, codes.OK, call(apiv1.Namer_Resync_FullMethodName, "authorization", "Bearer writer"))
+}
+
+func TestAuthorizeAPIDuringReload(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	tokens := []APIToken{{Name: "ops", Token: "writer", Scope: APIScopeAdmin}}
+	config.APITokens = tokens
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			configMu.Lock()
+			c := config
+			c.APITokens = tokens
+			config = c
+			configMu.Unlock()
+		}
+	}()
+
+	for range 100 {
+		authenticated, authorized, name := authorizeAPI("writer", true)
+		assert.True(t, authenticated)
+		assert.True(t, authorized)
+		assert.Equal(t, "ops", name)
+	}
+	wg.Wait()
+}
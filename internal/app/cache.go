@@ -0,0 +1,216 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/docker/docker/api/types/container"
+)
+
+// Names of the caches, used as the metric label.
+const (
+	CacheInspect = "inspect"
+	CacheLinks   = "links"
+	CacheSandbox = "sandbox"
+)
+
+type cachedInspect struct {
+	inspect container.InspectResponse
+	since   time.Time
+}
+
+type cachedLinks struct {
+	links []linkops.VEth
+	since time.Time
+}
+
+// Caches the container inspect results by the container ID, and the container links by the sandbox key.
+// Repeated events of the same container are served without inspecting it and enumerating its namespace again.
+// The entries are dropped when the container exits, or its networks change.
+//
+// The last inspect result of each running container is kept apart regardless of the TTL, until the container
+// exits, disconnects from a network, or is renamed. It serves the network connect events of the known containers,
+// as connecting to a network changes neither the identity of the container nor its sandbox.
+type InspectCache struct {
+	mu        sync.Mutex
+	inspects  map[string]cachedInspect
+	links     map[string]cachedLinks
+	sandboxes map[string]cachedInspect
+	// Incremented on every invalidation. The results requested before are not cached, as they may be stale.
+	epoch uint64
+}
+
+var inspectCache = newInspectCache()
+
+func newInspectCache() *InspectCache {
+	return &InspectCache{
+		inspects:  make(map[string]cachedInspect),
+		links:     make(map[string]cachedLinks),
+		sandboxes: make(map[string]cachedInspect),
+	}
+}
+
+// Returns the current epoch, to be passed along with the result requested after.
+func (c *InspectCache) Epoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.epoch
+}
+
+// Returns the inspect result of the container cached within the TTL. Zero TTL disables the cache.
+func (c *InspectCache) Inspect(containerID string, now time.Time, ttl time.Duration) (container.InspectResponse, bool) {
+	if ttl <= 0 {
+		return container.InspectResponse{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.inspects[containerID]
+	if !ok || now.Sub(entry.since) >= ttl {
+		cacheMisses.Inc(CacheInspect)
+		return container.InspectResponse{}, false
+	}
+	cacheHits.Inc(CacheInspect)
+	return entry.inspect, true
+}
+
+// Caches the inspect result of the container under the ID it was requested by,
+// unless the cache is invalidated since the epoch.
+func (c *InspectCache) StoreInspect(containerID string, inspect container.InspectResponse, epoch uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if epoch != c.epoch {
+		return
+	}
+	c.inspects[containerID] = cachedInspect{inspect: inspect, since: now}
+	if inspect.ContainerJSONBase != nil && inspect.State != nil && inspect.State.Running {
+		c.sandboxes[inspect.ID] = cachedInspect{inspect: inspect, since: now}
+	}
+}
+
+// Returns the last inspect result of the running container, kept until it exits, disconnects from a network, or is renamed.
+func (c *InspectCache) Sandbox(containerID string) (container.InspectResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.sandboxes[containerID]
+	if !ok {
+		cacheMisses.Inc(CacheSandbox)
+		return container.InspectResponse{}, false
+	}
+	cacheHits.Inc(CacheSandbox)
+	return entry.inspect, true
+}
+
+// Returns the links within the network namespace cached within the TTL. Zero TTL disables the cache.
+func (c *InspectCache) Links(sandboxKey string, now time.Time, ttl time.Duration) ([]linkops.VEth, bool) {
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.links[sandboxKey]
+	if !ok || now.Sub(entry.since) >= ttl {
+		cacheMisses.Inc(CacheLinks)
+		return nil, false
+	}
+	cacheHits.Inc(CacheLinks)
+	return entry.links, true
+}
+
+// Caches the links within the network namespace, unless the cache is invalidated since the epoch.
+// No links are not cached, as the links of a container being set up appear later.
+func (c *InspectCache) StoreLinks(sandboxKey string, links []linkops.VEth, epoch uint64, now time.Time) {
+	if len(links) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if epoch != c.epoch {
+		return
+	}
+	c.links[sandboxKey] = cachedLinks{links: links, since: now}
+}
+
+// Drops the cached inspect result of the container, and the cached links.
+// All links are dropped, as a sandbox may be shared by several containers.
+func (c *InspectCache) Invalidate(containerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	for id, entry := range c.inspects {
+		// The container may be cached under its name as well.
+		if id == containerID || (entry.inspect.ContainerJSONBase != nil && entry.inspect.ID == containerID) {
+			delete(c.inspects, id)
+		}
+	}
+	clear(c.links)
+}
+
+// Drops the cached entries of the container, including its last inspect result.
+func (c *InspectCache) Forget(containerID string) {
+	c.Invalidate(containerID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sandboxes, containerID)
+}
+
+// Drops the entries expired by the TTL, and the oldest entries beyond the maximum number of each kind.
+// The last inspect results of the running containers don't expire, but are limited likewise.
+// Returns the number of the dropped entries.
+func (c *InspectCache) Prune(now time.Time, ttl time.Duration, maxEntries int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := len(c.inspects) + len(c.links)
+	maps.DeleteFunc(c.inspects, func(_ string, entry cachedInspect) bool { return now.Sub(entry.since) >= ttl })
+	maps.DeleteFunc(c.links, func(_ string, entry cachedLinks) bool { return now.Sub(entry.since) >= ttl })
+	dropped -= len(c.inspects) + len(c.links)
+
+	dropped += evictOldest(c.inspects, maxEntries, func(entry cachedInspect) time.Time { return entry.since })
+	dropped += evictOldest(c.links, maxEntries, func(entry cachedLinks) time.Time { return entry.since })
+	dropped += evictOldest(c.sandboxes, maxEntries, func(entry cachedInspect) time.Time { return entry.since })
+	return dropped
+}
+
+// Drops all cached entries.
+func (c *InspectCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	clear(c.inspects)
+	clear(c.links)
+	clear(c.sandboxes)
+}
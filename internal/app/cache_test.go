@@ -0,0 +1,176 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectCache(t *testing.T) {
+	c := newInspectCache()
+	now := time.Unix(1700000000, 0)
+	inspect := container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", Name: "/web"}}
+
+	_, ok := c.Inspect("1234", now, time.Second)
+	assert.False(t, ok)
+
+	c.StoreInspect("1234", inspect, c.Epoch(), now)
+	cached, ok := c.Inspect("1234", now.Add(time.Second/2), time.Second)
+	require.True(t, ok)
+	assert.Equal(t, "/web", cached.Name)
+
+	// Expired by the TTL, or disabled.
+	_, ok = c.Inspect("1234", now.Add(time.Second), time.Second)
+	assert.False(t, ok)
+	_, ok = c.Inspect("1234", now, 0)
+	assert.False(t, ok)
+
+	// Cached under the name, and invalidated by the ID.
+	c.StoreInspect("web", inspect, c.Epoch(), now)
+	c.Invalidate("1234")
+	_, ok = c.Inspect("web", now, time.Second)
+	assert.False(t, ok)
+	_, ok = c.Inspect("1234", now, time.Second)
+	assert.False(t, ok)
+}
+
+func TestInspectCacheStaleStore(t *testing.T) {
+	c := newInspectCache()
+	now := time.Unix(1700000000, 0)
+	inspect := container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", Name: "/web"}}
+
+	// The result requested before the invalidation is not cached.
+	epoch := c.Epoch()
+	c.Invalidate("1234")
+	c.StoreInspect("1234", inspect, epoch, now)
+	c.StoreLinks("/var/run/docker/netns/1", []linkops.VEth{{Name: "eth0", ParentIndex: 10}}, epoch, now)
+
+	_, ok := c.Inspect("1234", now, time.Second)
+	assert.False(t, ok)
+	_, ok = c.Links("/var/run/docker/netns/1", now, time.Second)
+	assert.False(t, ok)
+}
+
+func TestInspectCacheLinks(t *testing.T) {
+	c := newInspectCache()
+	now := time.Unix(1700000000, 0)
+
+	// No links are not cached, they may appear later.
+	c.StoreLinks("/var/run/docker/netns/1", nil, c.Epoch(), now)
+	_, ok := c.Links("/var/run/docker/netns/1", now, time.Second)
+	assert.False(t, ok)
+
+	c.StoreLinks("/var/run/docker/netns/1", []linkops.VEth{{Name: "eth0", ParentIndex: 10}}, c.Epoch(), now)
+	links, ok := c.Links("/var/run/docker/netns/1", now, time.Second)
+	require.True(t, ok)
+	assert.Equal(t, "eth0", links[0].Name)
+
+	c.Clear()
+	_, ok = c.Links("/var/run/docker/netns/1", now, time.Second)
+	assert.False(t, ok)
+}
+
+func TestInspectCacheSandbox(t *testing.T) {
+	c := newInspectCache()
+	now := time.Unix(1700000000, 0)
+	inspect := container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{
+		ID: "1234", Name: "/web", State: &container.State{Running: true},
+	}}
+
+	// Kept regardless of the TTL, and of the invalidation on network connect.
+	c.StoreInspect("web", inspect, c.Epoch(), now)
+	c.Invalidate("1234")
+	cached, ok := c.Sandbox("1234")
+	require.True(t, ok)
+	assert.Equal(t, "/web", cached.Name)
+
+	c.Forget("1234")
+	_, ok = c.Sandbox("1234")
+	assert.False(t, ok)
+
+	// Stopped container is not kept.
+	inspect.State = &container.State{}
+	c.StoreInspect("1234", inspect, c.Epoch(), now)
+	_, ok = c.Sandbox("1234")
+	assert.False(t, ok)
+}
+
+func TestConnectedInspect(t *testing.T) {
+	prev := inspectCache
+	t.Cleanup(func() { inspectCache = prev })
+	inspectCache = newInspectCache()
+
+	_, ok := connectedInspect("1234", []string{"backend"})
+	assert.False(t, ok)
+
+	inspectCache.StoreInspect("1234", container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", Name: "/web", State: &container.State{Running: true}},
+		NetworkSettings: &container.NetworkSettings{
+			NetworkSettingsBase: container.NetworkSettingsBase{SandboxKey: "/var/run/docker/netns/1"},
+			Networks:            map[string]*network.EndpointSettings{"frontend": {MacAddress: "02:42:ac:11:00:02"}},
+		},
+	}, inspectCache.Epoch(), time.Now())
+
+	inspect, ok := connectedInspect("1234", []string{"backend"})
+	require.True(t, ok)
+	assert.Equal(t, "/web", inspect.Name)
+	assert.Equal(t, "/var/run/docker/netns/1", inspect.NetworkSettings.SandboxKey)
+	assert.Equal(t, "backend", dockerwatch.LinkNetwork(inspect, "02:42:ac:12:00:02"))
+	assert.Equal(t, "frontend", dockerwatch.LinkNetwork(inspect, "02:42:ac:11:00:02"))
+
+	// The cached result is not modified.
+	cached, _ := inspectCache.Sandbox("1234")
+	assert.Len(t, cached.NetworkSettings.Networks, 1)
+
+	// Several or known networks are resolved by inspecting the container.
+	_, ok = connectedInspect("1234", []string{"backend", "storage"})
+	assert.False(t, ok)
+	_, ok = connectedInspect("1234", []string{"frontend"})
+	assert.False(t, ok)
+}
+
+func TestInspectCachePrune(t *testing.T) {
+	c := newInspectCache()
+	now := time.Unix(1700000000, 0)
+	for i := range 4 {
+		id := fmt.Sprintf("%04d", i)
+		inspect := container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{
+			ID: id, Name: "/c" + id, State: &container.State{Running: true},
+		}}
+		c.StoreInspect(id, inspect, c.Epoch(), now.Add(time.Duration(i)*time.Second))
+	}
+
+	// Two inspect results are expired, and one sandbox is evicted as the oldest.
+	assert.Equal(t, 3, c.Prune(now.Add(4*time.Second), 3*time.Second, 3))
+	assert.Len(t, c.inspects, 2)
+	_, ok := c.Sandbox("0000")
+	assert.False(t, ok)
+	_, ok = c.Sandbox("0001")
+	assert.True(t, ok)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Assignment of the host link name to the container link within the time range, for attributing packet captures.
+type CaptureInterval struct {
+	Index         int       `json:"ifindex"`
+	Name          string    `json:"name"`
+	OriginalName  string    `json:"original_name"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	ContainerLink string    `json:"container_link"`
+	From          time.Time `json:"from"`
+	// Zero while the name is still assigned, or when the link was destroyed along with the container.
+	To time.Time `json:"to,omitzero"`
+}
+
+// Returns the name assignments of the audit log records, ordered by the start time.
+// The assignment lasts until the next successful operation on the same interface index,
+// i.e. restoring the original name, or renaming the reused index.
+func captureIntervals(records []RenameRecord) []CaptureInterval {
+	intervals := []CaptureInterval{}
+	// Position of the open interval by the interface index.
+	open := map[int]int{}
+
+	for _, record := range records {
+		if len(record.Error) > 0 || record.DryRun {
+			continue
+		}
+
+		if i, ok := open[record.Index]; ok {
+			intervals[i].To = record.Time
+			delete(open, record.Index)
+		}
+
+		if record.Operation != RenameApply {
+			continue
+		}
+
+		open[record.Index] = len(intervals)
+		intervals = append(intervals, CaptureInterval{
+			Index:         record.Index,
+			Name:          record.NewName,
+			OriginalName:  record.OldName,
+			ContainerID:   record.ContainerID,
+			ContainerName: strings.TrimPrefix(record.ContainerName, "/"),
+			ContainerLink: record.ContainerLink,
+			From:          record.Time,
+		})
+	}
+	return intervals
+}
+
+// Prints the name assignments of the host links from the audit log, keyed by the interface index and the time range.
+func printCaptureMap(w io.Writer) error {
+	if len(config.AuditLogFile) == 0 {
+		return errors.New("audit log is disabled in the configuration")
+	}
+
+	records, err := readAuditLog(config.AuditLogFile)
+	if err != nil {
+		return fmt.Errorf("cannot read audit log: %w", err)
+	}
+	intervals := captureIntervals(records)
+
+	return printOutput(w, intervals, func(w io.Writer) error {
+		formatTime := func(t time.Time) string {
+			if t.IsZero() {
+				return "-"
+			}
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "IFINDEX\tFROM\tTO\tNAME\tORIGINAL NAME\tCONTAINER\tCONTAINER ID\tCONTAINER LINK")
+		for _, i := range intervals {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%.12s\t%s\n",
+				i.Index, formatTime(i.From), formatTime(i.To), i.Name, i.OriginalName, i.ContainerName, i.ContainerID, i.ContainerLink)
+		}
+		return tw.Flush()
+	})
+}
+
+// Line of the "tcpdump -D" output: the number, the interface name, and the rest.
+var tcpdumpInterfaceLine = regexp.MustCompile(`^(\d+\.)?(\S+)(.*)$`)
+
+// Annotates the interface list, in the "tcpdump -D" format or one name per line,
+// with the containers owning the host links. Other lines are copied unchanged.
+func annotateInterfaces(r io.Reader, w io.Writer, mappings []ContainerMapping) error {
+	owners := map[string]string{}
+	for _, record := range mappingRecords(mappings) {
+		owners[record.Name] = fmt.Sprintf("container %s %s, ifindex %d", record.ContainerName, record.ContainerLink, record.Index)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := tcpdumpInterfaceLine.FindStringSubmatch(line); m != nil {
+			if owner, ok := owners[m[2]]; ok {
+				line += " <" + owner + ">"
+			}
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureIntervals(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2026, 1, 1, 12, minute, 0, 0, time.UTC)
+	}
+
+	records := []RenameRecord{
+		{Time: at(0), Operation: RenameApply, ContainerID: "1234", ContainerName: "/web", ContainerLink: "eth0", Index: 12, OldName: "veth1", NewName: "vweb0"},
+		{Time: at(1), Operation: RenameApply, ContainerID: "4567", ContainerName: "/db", ContainerLink: "eth0", Index: 14, OldName: "veth2", NewName: "vdb0", Error: "busy"},
+		{Time: at(2), Operation: RenameRestore, ContainerID: "1234", ContainerName: "/web", ContainerLink: "eth0", Index: 12, OldName: "vweb0", NewName: "veth1"},
+		{Time: at(3), Operation: RenameApply, ContainerID: "4567", ContainerName: "/db", ContainerLink: "eth0", Index: 14, OldName: "veth2", NewName: "vdb0"},
+		// The index is reused by another container.
+		{Time: at(4), Operation: RenameApply, ContainerID: "7890", ContainerName: "/app", ContainerLink: "eth0", Index: 14, OldName: "veth3", NewName: "vapp0"},
+	}
+
+	assert.Equal(t, []CaptureInterval{
+		{Index: 12, Name: "vweb0", OriginalName: "veth1", ContainerID: "1234", ContainerName: "web", ContainerLink: "eth0", From: at(0), To: at(2)},
+		{Index: 14, Name: "vdb0", OriginalName: "veth2", ContainerID: "4567", ContainerName: "db", ContainerLink: "eth0", From: at(3), To: at(4)},
+		{Index: 14, Name: "vapp0", OriginalName: "veth3", ContainerID: "7890", ContainerName: "app", ContainerLink: "eth0", From: at(4)},
+	}, captureIntervals(records))
+}
+
+func TestAnnotateInterfaces(t *testing.T) {
+	mappings := []ContainerMapping{
+		{ID: "1234", Name: "/web", Links: []LinkState{{Index: 12, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}}},
+	}
+
+	input := strings.Join([]string{
+		"1.eth0 [Up, Running, Connected]",
+		"2.vweb0 [Up, Running, Connected]",
+		"3.any (Pseudo-device that captures on all interfaces) [Up, Running]",
+		"vweb0",
+	}, "\n")
+
+	var buf bytes.Buffer
+	assert.NoError(t, annotateInterfaces(strings.NewReader(input), &buf, mappings))
+	assert.Equal(t, strings.Join([]string{
+		"1.eth0 [Up, Running, Connected]",
+		"2.vweb0 [Up, Running, Connected] <container web eth0, ifindex 12>",
+		"3.any (Pseudo-device that captures on all interfaces) [Up, Running]",
+		"vweb0 <container web eth0, ifindex 12>",
+	}, "\n")+"\n", buf.String())
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// Timeout of the collector HTTP request.
+	collectorTimeout = 10 * time.Second
+	// Delay of the first retry after a failed push, doubled on each consecutive failure up to the push interval.
+	collectorRetryDelay = time.Second
+)
+
+// Inventory of the host pushed to the central collector.
+type CollectorReport struct {
+	Host    string    `json:"host"`
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+	// Renamed host links with their counters, ordered by the link name.
+	Links        []CollectorLink              `json:"links"`
+	TrackedLinks int                          `json:"tracked_links"`
+	Durations    map[string]HistogramSnapshot `json:"durations"`
+	Skipped      map[string]uint64            `json:"skipped"`
+	Daemon       DaemonMetrics                `json:"daemon"`
+}
+
+// Renamed host link with its counters.
+type CollectorLink struct {
+	MappingRecord
+	// Nil if the link is gone.
+	Counters *LinkCounters `json:"counters,omitempty"`
+}
+
+// Counters of the host link.
+type LinkCounters struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	TxPackets uint64 `json:"tx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	TxErrors  uint64 `json:"tx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// Returns the report of the mappings, the metrics, and the counters provided by the function.
+func collectorReport(host string, snapshot MetricsSnapshot, linkStatistics func(index int) *netlink.LinkStatistics) CollectorReport {
+	report := CollectorReport{
+		Host:         host,
+		Version:      AppVersion,
+		Time:         time.Now(),
+		Links:        []CollectorLink{},
+		TrackedLinks: snapshot.TrackedLinks,
+		Durations:    snapshot.Durations,
+		Skipped:      snapshot.Skipped,
+	}
+	if snapshot.Daemon != nil {
+		report.Daemon = *snapshot.Daemon
+	}
+
+	for _, record := range mappingRecords(snapshot.Mappings) {
+		link := CollectorLink{MappingRecord: record}
+		if stats := linkStatistics(record.Index); stats != nil {
+			link.Counters = &LinkCounters{
+				RxBytes:   stats.RxBytes,
+				TxBytes:   stats.TxBytes,
+				RxPackets: stats.RxPackets,
+				TxPackets: stats.TxPackets,
+				RxErrors:  stats.RxErrors,
+				TxErrors:  stats.TxErrors,
+				RxDropped: stats.RxDropped,
+				TxDropped: stats.TxDropped,
+			}
+		}
+		report.Links = append(report.Links, link)
+	}
+	return report
+}
+
+// Pushes the inventory of the host to the central collector periodically, as JSON documents via HTTP POST.
+// Failed pushes are retried with exponential backoff.
+type CollectorPusher struct {
+	url      string
+	token    string
+	interval time.Duration
+	client   *http.Client
+	host     string
+	loop     *EventLoop
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newCollectorPusher(url string, token string, interval time.Duration, l *EventLoop) *CollectorPusher {
+	host, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &CollectorPusher{
+		url:      url,
+		token:    token,
+		interval: interval,
+		client:   &http.Client{Timeout: collectorTimeout},
+		host:     host,
+		loop:     l,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go p.run(ctx)
+	return p
+}
+
+// Stops pushing, waiting for the push in progress to be cancelled.
+func (p *CollectorPusher) Close() {
+	p.cancel()
+	<-p.done
+}
+
+// Pushes the report immediately, then on each interval, or sooner when retrying.
+func (p *CollectorPusher) run(ctx context.Context) {
+	defer reportPanic()
+	defer close(p.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	retryDelay := collectorRetryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		err := p.push(ctx)
+		if err == nil {
+			retryDelay = collectorRetryDelay
+			timer.Reset(p.interval)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Errorf("Cannot push report to collector: %s: %s, retrying in %s", p.url, err, retryDelay)
+		timer.Reset(retryDelay)
+		retryDelay = min(retryDelay*2, p.interval)
+	}
+}
+
+// Posts the current report.
+func (p *CollectorPusher) push(ctx context.Context) error {
+	snapshot := metricsSnapshot(state.Mappings())
+	daemon := p.loop.daemonMetrics(ctx)
+	snapshot.Daemon = &daemon
+
+	body, err := json.Marshal(collectorReport(p.host, snapshot, hostLinkStatistics))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(p.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded: %s", resp.Status)
+	}
+	return nil
+}
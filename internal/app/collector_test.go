@@ -0,0 +1,109 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestCollectorReport(t *testing.T) {
+	snapshot := MetricsSnapshot{
+		TrackedLinks: 2,
+		Skipped:      map[string]uint64{SkipPaused: 1},
+		Mappings: []ContainerMapping{
+			{ID: "4567", Name: "/db", Links: []LinkState{
+				{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0"},
+				{Index: 14, ContainerLink: "eth1", OriginalName: "veth3", Name: "vdb1"},
+			}},
+		},
+		Daemon: &DaemonMetrics{EventLoopAlive: true},
+	}
+
+	statistics := func(index int) *netlink.LinkStatistics {
+		if index != 12 {
+			return nil
+		}
+		return &netlink.LinkStatistics{RxBytes: 100, TxBytes: 200}
+	}
+
+	report := collectorReport("node1", snapshot, statistics)
+	assert.Equal(t, "node1", report.Host)
+	assert.Equal(t, 2, report.TrackedLinks)
+	assert.True(t, report.Daemon.EventLoopAlive)
+	require.Len(t, report.Links, 2)
+	assert.Equal(t, "vdb0", report.Links[0].Name)
+	assert.Equal(t, "db", report.Links[0].ContainerName)
+	assert.Equal(t, &LinkCounters{RxBytes: 100, TxBytes: 200}, report.Links[0].Counters)
+	assert.Nil(t, report.Links[1].Counters)
+}
+
+func TestCollectorPusher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, statusRequests: make(chan chan Status)}
+	go func() {
+		for {
+			select {
+			case reply := <-l.statusRequests:
+				reply <- Status{PendingTasks: 3}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	reports := make(chan CollectorReport, 2)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var report CollectorReport
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports <- report
+	}))
+	defer server.Close()
+
+	p := newCollectorPusher(server.URL, "secret", time.Hour, l)
+	defer p.Close()
+
+	// The failed push is retried.
+	select {
+	case report := <-reports:
+		assert.Equal(t, AppVersion, report.Version)
+		assert.Equal(t, 3, report.Daemon.PendingTasks)
+	case <-time.After(5 * collectorRetryDelay):
+		t.Fatal("report is not pushed")
+	}
+}
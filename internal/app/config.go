@@ -0,0 +1,688 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	config Config
+
+	// Path to the configuration file.
+	configFilePath string
+
+	// Guards the configuration against reload while it is used by event processing.
+	configMu sync.RWMutex
+
+	// Error of loading the configuration file, reported by the diagnostic commands.
+	configErr error
+
+	// Overrides the configuration keys by the command line options.
+	configFlags func(c *Config)
+)
+
+// Prefix of the environment variables overriding the configuration keys and the command line options.
+const configEnvPrefix = "DVN_"
+
+// Commands running with the default configuration when the configuration file is invalid or missing.
+var configTolerantCommands = []string{"doctor", "check", "generate-config"}
+
+type Config struct {
+	// Log level: trace, debug, info, warn, or error. The command line option takes precedence.
+	LogLevel string `yaml:"log_level"`
+	// Log format: text, or json. The command line option takes precedence.
+	LogFormat string `yaml:"log_format"`
+	// File receiving the log instead of stderr. The command line option takes precedence.
+	LogFile string `yaml:"log_file"`
+	// Size of the log file in megabytes, after which it is rotated. Zero disables the rotation by size.
+	LogFileMaxSize int `yaml:"log_file_max_size"`
+	// Age of the log file, after which it is rotated. Zero disables the rotation by age.
+	LogFileMaxAge time.Duration `yaml:"log_file_max_age"`
+	// Number of the rotated log files to keep.
+	LogFileMaxBackups int `yaml:"log_file_max_backups"`
+	// Send the log to journald natively, with the structured fields. The command line option takes precedence.
+	LogJournald bool `yaml:"log_journald"`
+	// Interval within which identical recurring errors are logged once, and then summarized with the count.
+	// Zero disables the suppression.
+	LogRepeatInterval time.Duration `yaml:"log_repeat_interval"`
+	// URL receiving the error log records and panics as JSON documents via HTTP POST,
+	// for the central error tracking. Disabled when empty.
+	ErrorReportURL string `yaml:"error_report_url"`
+	// Container link prefixes to be removed, e.g. "eth".
+	ContainerLinkPrefixes []string `yaml:"container_link_prefixes"`
+	// Remove duplicated symbols in the resulted name.
+	RemoveDuplicatedSymbols bool `yaml:"remove_duplicated_symbols"`
+	// Symbol replacements. The replacement order is according to the position in the list.
+	// Each replacement is processed non-recursively: when a substring of the container name matches a list item,
+	// the substitution will not be matched against other replacements.
+	Replacements []map[string]string `yaml:"replacements"`
+	// Separator to be added in front of the link index.
+	LinkIndexSeparator string `yaml:"link_index_separator"`
+	// Prefix of the host link names.
+	LinkNamePrefix string `yaml:"link_name_prefix"`
+	// Rename the host link back to its original name when the container disconnects from the network,
+	// in case the link still exists.
+	RestoreNameOnDisconnect bool `yaml:"restore_name_on_disconnect"`
+	// Mark the renamed host links by their alias, and skip making the name again for the links marked
+	// by the same naming configuration.
+	OwnershipMarker bool `yaml:"ownership_marker"`
+	// Maximum rate of renaming the host links, in renames per second. Zero means no limit.
+	MaxRenamesPerSecond float64 `yaml:"max_renames_per_second"`
+	// Watch host link events, and resync when a veth link appears without the corresponding Docker event.
+	WatchLinkEvents bool `yaml:"watch_link_events"`
+	// URLs receiving link mapping notifications as JSON documents via HTTP POST.
+	NotificationWebhooks []string `yaml:"notification_webhooks"`
+	// URL of the MQTT broker receiving link mapping notifications, in form "mqtt://[user:password@]host[:port]".
+	// Disabled when empty.
+	MQTTBroker string `yaml:"mqtt_broker"`
+	// Prefix of the MQTT topics the notifications are published to.
+	MQTTTopicPrefix string `yaml:"mqtt_topic_prefix"`
+	// URL of the NATS server receiving link mapping notifications, in form "nats://[user:password@|token@]host[:port]".
+	// Disabled when empty.
+	NATSURL string `yaml:"nats_url"`
+	// Prefix of the NATS subjects the notifications are published to.
+	NATSSubjectPrefix string `yaml:"nats_subject_prefix"`
+	// URL of ntopng receiving the container names as the aliases of the monitored host links, in form
+	// "http[s]://[user:password@]host[:port]". Disabled when empty.
+	NtopngURL string `yaml:"ntopng_url"`
+	// API token of the ntopng user, used instead of the user and the password of the URL when set.
+	NtopngToken string `yaml:"ntopng_token"`
+	// Publish the pods of the host links to the Kubernetes API server when running in a pod of the node,
+	// as the ConfigMap "docker-veth-namer-<node>" when "configmap", or as the node annotation when "annotation".
+	// Disabled when empty.
+	KubernetesPublish string `yaml:"kubernetes_publish"`
+	// Namespace of the ConfigMap.
+	KubernetesNamespace string `yaml:"kubernetes_namespace"`
+	// Name of the node. The host name is used when empty.
+	KubernetesNodeName string `yaml:"kubernetes_node_name"`
+	// Minimal interval between the updates published to the Kubernetes API server.
+	KubernetesPublishInterval time.Duration `yaml:"kubernetes_publish_interval"`
+	// Register on the system bus, emit link mapping notifications as D-Bus signals, and answer the mapping queries.
+	DBus bool `yaml:"dbus"`
+	// Time window to coalesce repeated events of the same container. Zero disables coalescing.
+	EventDebounce time.Duration `yaml:"event_debounce"`
+	// Number of workers processing events concurrently. Events of the same container are processed in order.
+	EventWorkers int `yaml:"event_workers"`
+	// Number of Docker events read from the subscription and awaiting the processing.
+	EventQueueSize int `yaml:"event_queue_size"`
+	// Handling of the Docker events when the queue is full: defer reading the subscription, or drop the events and resync.
+	EventQueuePolicy string `yaml:"event_queue_policy"`
+	// Number of running containers inspected and renamed concurrently on startup, and on the periodic processing.
+	StartupWorkers int `yaml:"startup_workers"`
+	// Rename running containers on startup in order of their names once all of them are inspected,
+	// instead of renaming each one as soon as it is inspected.
+	OrderedStartup bool `yaml:"ordered_startup"`
+	// Time the container inspect results and the container links are cached for. Zero disables the cache.
+	InspectCacheTTL time.Duration `yaml:"inspect_cache_ttl"`
+	// Maximum number of the entries of each internal cache. The oldest entries are evicted beyond it.
+	MaxCacheEntries int `yaml:"max_cache_entries"`
+	// Rename the host links back to their original names on graceful shutdown.
+	RevertOnExit bool `yaml:"revert_on_exit"`
+	// Shell command run before each rename. Non-zero exit status vetoes the rename. Disabled when empty.
+	PreRenameHook string `yaml:"pre_rename_hook"`
+	// Shell command run after each rename attempt. Disabled when empty.
+	PostRenameHook string `yaml:"post_rename_hook"`
+	// Time limit of the hook and naming commands, killed when exceeded.
+	HookTimeout time.Duration `yaml:"hook_timeout"`
+	// Strategy making the host link names: "morph" (by the rules below), "exec" (by the naming command),
+	// or "wasm" (by the naming module).
+	NamingStrategy string `yaml:"naming_strategy"`
+	// Shell command of the "exec" naming strategy. It receives the container link as JSON on stdin,
+	// and prints the host link name to stdout.
+	NamingCommand string `yaml:"naming_command"`
+	// WASI module of the "wasm" naming strategy, run in the sandbox. It receives the container link as JSON on stdin,
+	// and prints the host link name to stdout.
+	NamingModule string `yaml:"naming_module"`
+	// Memory limit of the naming module in megabytes.
+	NamingModuleMemoryLimit int `yaml:"naming_module_memory_limit"`
+	// Time limit of the naming module run, aborted when exceeded.
+	NamingModuleTimeout time.Duration `yaml:"naming_module_timeout"`
+	// Reload the configuration automatically when the configuration file changes.
+	AutoReload bool `yaml:"auto_reload"`
+	// Interval of checking the remote configuration for changes, when the configuration is fetched from the URL
+	// and auto_reload is enabled.
+	ConfigRefreshInterval time.Duration `yaml:"config_refresh_interval"`
+	// Docker events triggering the container processing, in form "<type> <action>".
+	// Only container and network events are supported.
+	EventTriggers []string `yaml:"event_triggers"`
+	// File preserving the state between the program runs. The state is not preserved when empty.
+	StateFile string `yaml:"state_file"`
+	// Endpoint of the container runtime interface (CRI), e.g. of containerd or CRI-O run by kubelet, in form
+	// "unix:///path". The pods are renamed instead of the Docker containers when set.
+	CRIEndpoint string `yaml:"cri_endpoint"`
+	// Maximum age of the Docker events missed while the program was not running, to be replayed on startup.
+	EventReplayWindow time.Duration `yaml:"event_replay_window"`
+	// Timeout of listing the containers via Docker API. Zero means no timeout.
+	DockerListTimeout time.Duration `yaml:"docker_list_timeout"`
+	// Timeout of inspecting a container via Docker API. Zero means no timeout.
+	DockerInspectTimeout time.Duration `yaml:"docker_inspect_timeout"`
+	// Timeout of connecting to Docker events stream. Zero means no timeout.
+	DockerEventsConnectTimeout time.Duration `yaml:"docker_events_connect_timeout"`
+	// Maximum rate of listing and inspecting the containers via Docker API, in requests per second. Zero means no limit.
+	DockerRateLimit float64 `yaml:"docker_rate_limit"`
+	// Number of requests allowed at once above the rate limit.
+	DockerRateBurst int `yaml:"docker_rate_burst"`
+	// Interval of the Docker API liveness check. Zero disables the check.
+	DockerPingInterval time.Duration `yaml:"docker_ping_interval"`
+	// Interval of logging the activity summary: events seen, renames done, failures, tracked containers.
+	// Zero disables the summary.
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+	// Number of consecutive liveness check failures, after which the Docker connection is rebuilt.
+	DockerPingFailures int `yaml:"docker_ping_failures"`
+	// File enabling the maintenance mode while it exists. Renaming is paused in the maintenance mode.
+	PauseFile string `yaml:"pause_file"`
+	// Number of renames of the same link within the flap window, after which the link is reported as flapping.
+	// Zero disables the flapping detection.
+	FlapThreshold int `yaml:"flap_threshold"`
+	// Time window of the flapping detection.
+	FlapWindow time.Duration `yaml:"flap_window"`
+	// Rules firing an action when the number of the events within the time window reaches the threshold.
+	Alerts []AlertRule `yaml:"alerts"`
+	// File receiving the JSON state dump on SIGUSR1. The dump is written to the log when empty.
+	StateDumpFile string `yaml:"state_dump_file"`
+	// File receiving the rename operations as JSON lines. The audit log is not written when empty.
+	AuditLogFile string `yaml:"audit_log_file"`
+	// SQLite database keeping the timeline of the host link name assignments. The timeline is not kept when empty.
+	TimelineDB string `yaml:"timeline_db"`
+	// Age of the ended assignments deleted from the timeline database. Zero keeps the assignments forever.
+	TimelineRetention time.Duration `yaml:"timeline_retention"`
+	// PEM files with the certificate and the private key serving the TCP listeners over TLS: the metrics address,
+	// and the REST and gRPC APIs. The files are reloaded when they change. TLS is disabled when empty.
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+	// PEM file with the CA certificates verifying the client certificates, required when set.
+	TLSClientCAFile string `yaml:"tls_client_ca_file"`
+	// Tokens authenticating the clients of the REST and gRPC APIs, with the read or admin scope.
+	// The APIs are not authenticated when empty.
+	APITokens []APIToken `yaml:"api_tokens"`
+	// Unix socket serving the requests of the command line tool to the running daemon. Empty value disables the socket.
+	ControlSocket string `yaml:"control_socket"`
+	// TCP address serving the metrics in the Prometheus text format at /metrics, and the health at /healthz,
+	// e.g. "127.0.0.1:9469". Empty value disables the listener.
+	MetricsAddress string `yaml:"metrics_address"`
+	// Serve the dashboard page at / on the metrics address. It reveals the container names to anyone reaching the address.
+	WebUI bool `yaml:"web_ui"`
+	// File receiving the Prometheus file_sd target groups, one per renamed host link. Not written when empty.
+	FileSDFile string `yaml:"file_sd_file"`
+	// File receiving the current host link mappings, as CSV when the extension is ".csv", or as JSON otherwise.
+	// Not written when empty.
+	MappingFile string `yaml:"mapping_file"`
+	// Key-value store receiving the current host link mappings, in form "consul://[:token@]host[:port]" or "etcd://host[:port]".
+	// Disabled when empty.
+	KVStore string `yaml:"kv_store"`
+	// Prefix of the keys, followed by the host name and the host link name.
+	KVPrefix string `yaml:"kv_prefix"`
+	// Time after which the keys of the host expire when the program is not running.
+	KVTTL time.Duration `yaml:"kv_ttl"`
+	// URL of the central collector receiving the inventory of the host as JSON documents via HTTP POST. Disabled when empty.
+	CollectorURL string `yaml:"collector_url"`
+	// Bearer token sent to the collector. Not sent when empty.
+	CollectorToken string `yaml:"collector_token"`
+	// Interval of pushing the inventory to the collector.
+	CollectorInterval time.Duration `yaml:"collector_interval"`
+}
+
+// Returns the name of the environment variable overriding the configuration key.
+func configEnvName(key string) string {
+	return configEnvPrefix + strings.ToUpper(key)
+}
+
+// Overrides the configuration keys by the environment variables, e.g. DVN_EVENT_DEBOUNCE for event_debounce.
+// The values other than strings are decoded as YAML, e.g. lists are specified in the flow style: [eth, wlan].
+func applyConfigEnv(c *Config, lookupEnv func(string) (string, bool)) error {
+	var errs []error
+
+	v := reflect.ValueOf(c).Elem()
+	for i := range v.NumField() {
+		key := v.Type().Field(i).Tag.Get("yaml")
+		value, ok := lookupEnv(configEnvName(key))
+		if !ok {
+			continue
+		}
+
+		// Strings are taken verbatim.
+		if v.Field(i).Kind() == reflect.String {
+			v.Field(i).SetString(value)
+			continue
+		}
+
+		// Decode into a new value, to replace lists and maps instead of merging.
+		field := reflect.New(v.Field(i).Type())
+		if err := yaml.Unmarshal([]byte(value), field.Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", configEnvName(key), err))
+			continue
+		}
+		v.Field(i).Set(field.Elem())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Returns the configuration used for the keys missing in the configuration file.
+func defaultConfig() Config {
+	return Config{
+		LinkNamePrefix:            naming.DefaultOptions().LinkNamePrefix,
+		LogLevel:                  "info",
+		LogFormat:                 LogFormatText,
+		LogFileMaxSize:            100,
+		LogFileMaxAge:             7 * 24 * time.Hour,
+		LogFileMaxBackups:         5,
+		LogRepeatInterval:         time.Hour,
+		EventDebounce:             500 * time.Millisecond,
+		EventWorkers:              4,
+		EventQueueSize:            4096,
+		EventQueuePolicy:          EventQueuePolicyDefer,
+		StartupWorkers:            8,
+		InspectCacheTTL:           5 * time.Second,
+		MaxCacheEntries:           10000,
+		AutoReload:                true,
+		ConfigRefreshInterval:     5 * time.Minute,
+		EventTriggers:             []string{"network connect", "container start"},
+		StateFile:                 "/var/lib/docker-veth-namer/state.json",
+		PauseFile:                 "/run/docker-veth-namer/paused",
+		ControlSocket:             "/run/docker-veth-namer/control.sock",
+		MQTTTopicPrefix:           "docker-veth-namer",
+		NATSSubjectPrefix:         "docker-veth-namer",
+		HookTimeout:               10 * time.Second,
+		KVPrefix:                  "docker-veth-namer",
+		KVTTL:                     time.Minute,
+		CollectorInterval:         time.Minute,
+		KubernetesNamespace:       "kube-system",
+		KubernetesPublishInterval: 30 * time.Second,
+		NamingStrategy:            NamingStrategyMorph,
+		NamingModuleMemoryLimit:   64,
+		NamingModuleTimeout:       time.Second,
+		EventReplayWindow:         time.Hour,
+
+		DockerListTimeout:          30 * time.Second,
+		DockerInspectTimeout:       10 * time.Second,
+		DockerRateBurst:            10,
+		DockerEventsConnectTimeout: 10 * time.Second,
+		DockerPingInterval:         30 * time.Second,
+		HeartbeatInterval:          time.Hour,
+		DockerPingFailures:         3,
+
+		FlapThreshold: 5,
+		FlapWindow:    10 * time.Minute,
+
+		TimelineRetention: 90 * 24 * time.Hour,
+	}
+}
+
+// Reads the configuration file over the default configuration, and applies the environment variables
+// and the command line options over it. The path may be an HTTP(S) URL of the remote configuration.
+// Empty path results in the default configuration.
+func loadConfig(path string) (Config, error) {
+	c := defaultConfig()
+	if len(path) > 0 {
+		var configReader io.Reader
+		if isRemoteConfig(path) {
+			data, _, err := loadRemoteConfig(path)
+			if err != nil {
+				return c, err
+			}
+			configReader = bytes.NewReader(data)
+		} else {
+			configFile, err := os.Open(path)
+			if err != nil {
+				return c, err
+			}
+			defer configFile.Close()
+			configReader = configFile
+		}
+
+		configDecoder := yaml.NewDecoder(configReader)
+		configDecoder.KnownFields(true)
+		if err := configDecoder.Decode(&c); err != nil {
+			return c, err
+		}
+	}
+
+	if err := applyConfigEnv(&c, os.LookupEnv); err != nil {
+		return c, err
+	}
+
+	if configFlags != nil {
+		configFlags(&c)
+	}
+
+	if err := validateConfig(c); err != nil {
+		return c, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Checks the configuration values.
+func validateConfig(c Config) error {
+	var errs []error
+
+	errs = append(errs, namingOptions(c).Validate()...)
+
+	if _, err := log.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level is invalid: %w", err))
+	}
+
+	if err := parseLogFormat(c.LogFormat); err != nil {
+		errs = append(errs, fmt.Errorf("log_format is invalid: %w", err))
+	}
+
+	if c.LogFileMaxSize < 0 || c.LogFileMaxAge < 0 || c.LogFileMaxBackups < 0 {
+		errs = append(errs, errors.New("log file rotation limits must not be negative"))
+	}
+
+	if c.LogRepeatInterval < 0 {
+		errs = append(errs, fmt.Errorf("log_repeat_interval must not be negative: %s", c.LogRepeatInterval))
+	}
+
+	if len(c.ErrorReportURL) > 0 {
+		if u, err := url.Parse(c.ErrorReportURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			errs = append(errs, fmt.Errorf("error_report_url must be an HTTP URL: %q", c.ErrorReportURL))
+		}
+	}
+
+	if len(c.MQTTBroker) > 0 {
+		if u, err := url.Parse(c.MQTTBroker); err != nil || (u.Scheme != "mqtt" && u.Scheme != "tcp") || len(u.Hostname()) == 0 {
+			errs = append(errs, fmt.Errorf("mqtt_broker must be an MQTT URL: %q", c.MQTTBroker))
+		}
+	}
+
+	if len(c.MQTTTopicPrefix) == 0 || strings.ContainsAny(c.MQTTTopicPrefix, "+#") {
+		errs = append(errs, fmt.Errorf("mqtt_topic_prefix must be a non-empty topic without wildcards: %q", c.MQTTTopicPrefix))
+	}
+
+	if len(c.NATSURL) > 0 {
+		if u, err := url.Parse(c.NATSURL); err != nil || (u.Scheme != "nats" && u.Scheme != "tcp") || len(u.Hostname()) == 0 {
+			errs = append(errs, fmt.Errorf("nats_url must be a NATS URL: %q", c.NATSURL))
+		}
+	}
+
+	if len(c.NATSSubjectPrefix) == 0 || strings.ContainsAny(c.NATSSubjectPrefix, "*> \t") {
+		errs = append(errs, fmt.Errorf("nats_subject_prefix must be a non-empty subject without wildcards: %q", c.NATSSubjectPrefix))
+	}
+
+	if len(c.NtopngURL) > 0 {
+		if u, err := url.Parse(c.NtopngURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			errs = append(errs, fmt.Errorf("ntopng_url must be an HTTP URL: %q", c.NtopngURL))
+		}
+	}
+
+	if len(c.KubernetesPublish) > 0 && !kubernetesPublishModes[c.KubernetesPublish] {
+		errs = append(errs, fmt.Errorf("kubernetes_publish must be empty, %s or %s: %q",
+			KubernetesPublishConfigMap, KubernetesPublishAnnotation, c.KubernetesPublish))
+	}
+
+	if c.KubernetesPublish == KubernetesPublishConfigMap && len(c.KubernetesNamespace) == 0 {
+		errs = append(errs, errors.New("kubernetes_namespace must be set for the configmap publishing"))
+	}
+
+	if c.KubernetesPublishInterval < time.Second {
+		errs = append(errs, fmt.Errorf("kubernetes_publish_interval must be at least 1s: %s", c.KubernetesPublishInterval))
+	}
+
+	if _, ok := namingStrategies[c.NamingStrategy]; !ok {
+		errs = append(errs, fmt.Errorf("naming_strategy must be one of %s: %q", strings.Join(namingStrategyNames(), ", "), c.NamingStrategy))
+	}
+
+	if c.NamingStrategy == NamingStrategyExec && len(c.NamingCommand) == 0 {
+		errs = append(errs, errors.New("naming_command must be set for the exec naming strategy"))
+	}
+
+	if c.NamingStrategy == NamingStrategyWasm && len(c.NamingModule) == 0 {
+		errs = append(errs, errors.New("naming_module must be set for the wasm naming strategy"))
+	}
+
+	if c.NamingModuleMemoryLimit < 1 || c.NamingModuleMemoryLimit > 4096 {
+		errs = append(errs, fmt.Errorf("naming_module_memory_limit must be between 1 and 4096: %d", c.NamingModuleMemoryLimit))
+	}
+
+	if c.NamingModuleTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("naming_module_timeout must be positive: %s", c.NamingModuleTimeout))
+	}
+
+	if len(c.CRIEndpoint) > 0 && !strings.HasPrefix(c.CRIEndpoint, "unix:///") {
+		errs = append(errs, fmt.Errorf("cri_endpoint must be in form unix:///path: %q", c.CRIEndpoint))
+	}
+
+	if len(c.KVStore) > 0 {
+		if u, err := url.Parse(c.KVStore); err != nil || (u.Scheme != "consul" && u.Scheme != "etcd") || len(u.Hostname()) == 0 {
+			errs = append(errs, fmt.Errorf("kv_store must be a consul:// or etcd:// URL: %q", c.KVStore))
+		}
+	}
+
+	if len(strings.Trim(c.KVPrefix, "/")) == 0 {
+		errs = append(errs, fmt.Errorf("kv_prefix must not be empty: %q", c.KVPrefix))
+	}
+
+	// Consul rejects session TTLs shorter than 10 seconds.
+	if c.KVTTL < 10*time.Second {
+		errs = append(errs, fmt.Errorf("kv_ttl must be at least 10s: %s", c.KVTTL))
+	}
+
+	if len(c.CollectorURL) > 0 {
+		if u, err := url.Parse(c.CollectorURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			errs = append(errs, fmt.Errorf("collector_url must be an HTTP URL: %q", c.CollectorURL))
+		}
+	}
+
+	if c.CollectorInterval < time.Second {
+		errs = append(errs, fmt.Errorf("collector_interval must be at least 1s: %s", c.CollectorInterval))
+	}
+
+	if c.ConfigRefreshInterval < time.Second {
+		errs = append(errs, fmt.Errorf("config_refresh_interval must be at least 1s: %s", c.ConfigRefreshInterval))
+	}
+
+	if (len(c.TLSCertFile) > 0) != (len(c.TLSKeyFile) > 0) {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if len(c.TLSClientCAFile) > 0 && len(c.TLSCertFile) == 0 {
+		errs = append(errs, errors.New("tls_client_ca_file requires tls_cert_file and tls_key_file"))
+	}
+
+	for i, token := range c.APITokens {
+		if len(token.Token) == 0 {
+			errs = append(errs, fmt.Errorf("api_tokens[%d].token must not be empty", i))
+		}
+		if token.Scope != APIScopeRead && token.Scope != APIScopeAdmin {
+			errs = append(errs, fmt.Errorf("api_tokens[%d].scope must be %s or %s: %q", i, APIScopeRead, APIScopeAdmin, token.Scope))
+		}
+	}
+
+	if c.HookTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("hook_timeout must be positive: %s", c.HookTimeout))
+	}
+
+	if c.EventDebounce < 0 {
+		errs = append(errs, fmt.Errorf("event_debounce must not be negative: %s", c.EventDebounce))
+	}
+
+	for _, trigger := range c.EventTriggers {
+		if _, err := dockerwatch.ParseTrigger(trigger); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.EventReplayWindow < 0 {
+		errs = append(errs, fmt.Errorf("event_replay_window must not be negative: %s", c.EventReplayWindow))
+	}
+
+	if c.DockerListTimeout < 0 || c.DockerInspectTimeout < 0 || c.DockerEventsConnectTimeout < 0 {
+		errs = append(errs, errors.New("docker timeouts must not be negative"))
+	}
+
+	if c.MaxRenamesPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("max_renames_per_second must not be negative: %g", c.MaxRenamesPerSecond))
+	}
+
+	if c.DockerRateLimit < 0 {
+		errs = append(errs, fmt.Errorf("docker_rate_limit must not be negative: %g", c.DockerRateLimit))
+	}
+
+	if c.DockerRateLimit > 0 && c.DockerRateBurst < 1 {
+		errs = append(errs, fmt.Errorf("docker_rate_burst must be positive: %d", c.DockerRateBurst))
+	}
+
+	if c.HeartbeatInterval < 0 {
+		errs = append(errs, fmt.Errorf("heartbeat_interval must not be negative: %s", c.HeartbeatInterval))
+	}
+
+	if c.DockerPingInterval < 0 {
+		errs = append(errs, fmt.Errorf("docker_ping_interval must not be negative: %s", c.DockerPingInterval))
+	}
+
+	if c.DockerPingFailures < 1 {
+		errs = append(errs, fmt.Errorf("docker_ping_failures must be positive: %d", c.DockerPingFailures))
+	}
+
+	if c.FlapThreshold < 0 {
+		errs = append(errs, fmt.Errorf("flap_threshold must not be negative: %d", c.FlapThreshold))
+	}
+
+	if c.FlapWindow < 0 {
+		errs = append(errs, fmt.Errorf("flap_window must not be negative: %s", c.FlapWindow))
+	}
+
+	errs = append(errs, validateAlertRules(c.Alerts)...)
+
+	if c.TimelineRetention < 0 {
+		errs = append(errs, fmt.Errorf("timeline_retention must not be negative: %s", c.TimelineRetention))
+	}
+
+	if c.EventWorkers < 1 {
+		errs = append(errs, fmt.Errorf("event_workers must be positive: %d", c.EventWorkers))
+	}
+
+	if c.EventQueueSize < 1 {
+		errs = append(errs, fmt.Errorf("event_queue_size must be positive: %d", c.EventQueueSize))
+	}
+
+	if c.EventQueuePolicy != EventQueuePolicyDefer && c.EventQueuePolicy != EventQueuePolicyDrop {
+		errs = append(errs, fmt.Errorf("event_queue_policy must be %s or %s: %q", EventQueuePolicyDefer, EventQueuePolicyDrop, c.EventQueuePolicy))
+	}
+
+	if c.StartupWorkers < 1 {
+		errs = append(errs, fmt.Errorf("startup_workers must be positive: %d", c.StartupWorkers))
+	}
+
+	if c.InspectCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("inspect_cache_ttl must not be negative: %s", c.InspectCacheTTL))
+	}
+
+	if c.MaxCacheEntries < 1 {
+		errs = append(errs, fmt.Errorf("max_cache_entries must be positive: %d", c.MaxCacheEntries))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Loads the configuration file, and applies it when valid.
+// Waits for the event processing tasks in progress to complete. Returns the previous configuration.
+func reloadConfig() (Config, error) {
+	c, err := loadConfig(configFilePath)
+	if err != nil {
+		return config, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	prev := config
+	config = c
+	applyLogLevel()
+	applyLogOutput()
+	applyLogFormat()
+	applyErrorReport()
+	applyDockerRateLimit()
+	applyRenameRateLimit()
+	// The configuration is reloaded by the listen command only.
+	setupNotificationSinks(true)
+
+	return prev, nil
+}
+
+// Watches the directory of the configuration file, since editors and configuration management tools
+// often replace the file instead of writing to it.
+type ConfigWatcher struct {
+	watcher *fsnotify.Watcher
+	path    string
+}
+
+func newConfigWatcher(path string) (*ConfigWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	return &ConfigWatcher{watcher: watcher, path: path}, nil
+}
+
+func (w *ConfigWatcher) Close() {
+	if w == nil {
+		return
+	}
+	w.watcher.Close()
+}
+
+// Returns the channel of file events. Nil watcher returns nil channel blocking forever.
+func (w *ConfigWatcher) Events() <-chan fsnotify.Event {
+	if w == nil {
+		return nil
+	}
+	return w.watcher.Events
+}
+
+// Returns the channel of watch errors. Nil watcher returns nil channel blocking forever.
+func (w *ConfigWatcher) Errors() <-chan error {
+	if w == nil {
+		return nil
+	}
+	return w.watcher.Errors
+}
+
+// Returns whether the event changes the configuration file.
+func (w *ConfigWatcher) IsConfigChanged(event fsnotify.Event) bool {
+	return filepath.Clean(event.Name) == w.path && event.Has(fsnotify.Create|fsnotify.Write|fsnotify.Rename)
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyConfigEnv(t *testing.T) {
+	env := map[string]string{
+		"DVN_EVENT_DEBOUNCE":             "2s",
+		"DVN_EVENT_WORKERS":              "8",
+		"DVN_RESTORE_NAME_ON_DISCONNECT": "true",
+		"DVN_CONTAINER_LINK_PREFIXES":    "[eth, wlan]",
+		"DVN_REPLACEMENTS":               "[{server: srv}]",
+		"DVN_LINK_INDEX_SEPARATOR":       "-",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	c := defaultConfig()
+	c.ContainerLinkPrefixes = []string{"veth"}
+	c.Replacements = []map[string]string{{"a": ""}, {"e": ""}}
+	assert.NoError(t, applyConfigEnv(&c, lookupEnv))
+
+	assert.Equal(t, 2*time.Second, c.EventDebounce)
+	assert.Equal(t, 8, c.EventWorkers)
+	assert.True(t, c.RestoreNameOnDisconnect)
+	assert.Equal(t, []string{"eth", "wlan"}, c.ContainerLinkPrefixes)
+	assert.Equal(t, []map[string]string{{"server": "srv"}}, c.Replacements)
+	assert.Equal(t, "-", c.LinkIndexSeparator)
+
+	// Untouched keys keep the values.
+	assert.Equal(t, defaultConfig().StateFile, c.StateFile)
+
+	env = map[string]string{"DVN_EVENT_WORKERS": "many"}
+	assert.ErrorContains(t, applyConfigEnv(&c, lookupEnv), "DVN_EVENT_WORKERS")
+}
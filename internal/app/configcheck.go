@@ -0,0 +1,122 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+)
+
+// Checks the replacement rules and the container link prefixes for the entries which can never apply.
+func (d *Doctor) checkRules(c Config) {
+	found := false
+
+	var needles []string
+	for i, pair := range c.Replacements {
+		needle, _ := mapKeyVal(pair)
+		switch {
+		case len(needle) == 0:
+			d.warn("Remove the rule", "replacements[%d] has empty needle, and never applies", i)
+			found = true
+			continue
+		case strings.Contains(needle, "/"):
+			d.warn("Remove the rule", "replacements[%d] %q contains slash, which is never a part of the container name", i, needle)
+			found = true
+			continue
+		}
+
+		// The earlier replacement consumes every occurrence of the needle.
+		for j, earlier := range needles {
+			if len(earlier) > 0 && strings.Contains(needle, earlier) {
+				d.warn(fmt.Sprintf("Move the rule before replacements[%d]", j),
+					"replacements[%d] %q is shadowed by replacements[%d] %q, and never applies", i, needle, j, earlier)
+				found = true
+				break
+			}
+		}
+		needles = append(needles, needle)
+	}
+
+	for i, prefix := range c.ContainerLinkPrefixes {
+		if len(prefix) == 0 {
+			d.warn("Remove the prefix", "container_link_prefixes[%d] is empty, and never applies", i)
+			found = true
+			continue
+		}
+
+		// Only the first matching prefix is removed.
+		for j, earlier := range c.ContainerLinkPrefixes[:i] {
+			if len(earlier) > 0 && strings.HasPrefix(prefix, earlier) {
+				d.warn(fmt.Sprintf("Move the prefix before container_link_prefixes[%d]", j),
+					"container_link_prefixes[%d] %q is shadowed by container_link_prefixes[%d] %q, and never applies", i, prefix, j, earlier)
+				found = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		d.ok("All replacement rules and container link prefixes may apply")
+	}
+}
+
+// Checks the host link names produced for the sample container names, in form <container-name>[:<link-name>].
+func (d *Doctor) checkSampleNames(samples []string) {
+	for _, sample := range samples {
+		containerName, containerLinkName, ok := strings.Cut(sample, ":")
+		if !ok {
+			containerLinkName = "eth0"
+		}
+
+		linkName := makeLinkName(containerName, containerLinkName)
+		switch {
+		case len(linkName) == 0:
+			d.fail("Shorten the container link name, or the link index separator", "Cannot make host link name: %s %s", containerName, containerLinkName)
+		case !naming.IsValidText(linkName):
+			d.fail("Fix the replacement rules", "Host link name is invalid: %s %s: %q", containerName, containerLinkName, linkName)
+		default:
+			d.ok("Host link name: %s %s: %s", containerName, containerLinkName, linkName)
+		}
+	}
+}
+
+// Validates the configuration, and prints the findings. Warnings are treated as failures in strict mode.
+func runCheck(configErr error, samples []string, strict bool) error {
+	d := &Doctor{}
+	d.checkConfig(configErr)
+	if configErr == nil {
+		d.checkRules(config)
+		d.checkSampleNames(samples)
+	}
+
+	if strict {
+		for i := range d.findings {
+			if d.findings[i].Level == FindingWarn {
+				d.findings[i].Level = FindingFail
+			}
+		}
+	}
+
+	return d.report(os.Stdout)
+}
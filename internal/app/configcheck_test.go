@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRules(t *testing.T) {
+	d := &Doctor{}
+	d.checkRules(Config{
+		ContainerLinkPrefixes: []string{"eth", "ethernet", ""},
+		Replacements: []map[string]string{
+			{"a": ""},
+			{"admin": "adm"},
+			{"": "x"},
+			{"db/": "d"},
+			{"server": "srv"},
+		},
+	})
+
+	var messages []string
+	for _, finding := range d.findings {
+		assert.Equal(t, FindingWarn, finding.Level)
+		messages = append(messages, finding.Message)
+	}
+	assert.Equal(t, []string{
+		`replacements[1] "admin" is shadowed by replacements[0] "a", and never applies`,
+		"replacements[2] has empty needle, and never applies",
+		`replacements[3] "db/" contains slash, which is never a part of the container name`,
+		`container_link_prefixes[1] "ethernet" is shadowed by container_link_prefixes[0] "eth", and never applies`,
+		"container_link_prefixes[2] is empty, and never applies",
+	}, messages)
+
+	d = &Doctor{}
+	d.checkRules(Config{Replacements: []map[string]string{{"admin": "adm"}, {"a": ""}}})
+	assert.Len(t, d.findings, 1)
+	assert.Equal(t, FindingOK, d.findings[0].Level)
+}
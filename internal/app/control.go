@@ -0,0 +1,482 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Timeout of the control socket requests.
+const controlRequestTimeout = 5 * time.Second
+
+// Status of the running daemon, reported via the control socket.
+type Status struct {
+	Version   string    `json:"version"`
+	StartTime time.Time `json:"start_time"`
+	Uptime    string    `json:"uptime"`
+	// Whether the Docker events stream is established.
+	DockerConnected bool `json:"docker_connected"`
+	// Number of consecutive Docker API ping failures.
+	DockerPingFailures int  `json:"docker_ping_failures"`
+	Paused             bool `json:"paused"`
+	TrackedContainers  int  `json:"tracked_containers"`
+	TrackedLinks       int  `json:"tracked_links"`
+	PendingTasks       int  `json:"pending_tasks"`
+	// Number of the containers with pending tasks waiting for a free worker.
+	QueuedContainers int       `json:"queued_containers"`
+	PendingRetries   int64     `json:"pending_retries"`
+	LastEventTime    time.Time `json:"last_event_time"`
+	// Number of the Docker events awaiting the processing, and the size of their queue.
+	EventQueueDepth    int `json:"event_queue_depth"`
+	EventQueueCapacity int `json:"event_queue_capacity"`
+	// Number of the Docker events dropped, and the number of times reading them was deferred, as the queue was full.
+	EventsDropped      uint64 `json:"events_dropped"`
+	EventReadsDeferred uint64 `json:"event_reads_deferred"`
+}
+
+// Serves the requests of the command line tool to the running daemon via the unix socket.
+type ControlServer struct {
+	server   *http.Server
+	listener net.Listener
+	path     string
+}
+
+// Starts serving the control socket. Requests are passed to the event loop.
+func newControlServer(path string, l *EventLoop) (*ControlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	// Remove the socket left by the previous run.
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// Only root may control the daemon. The socket is created in a private directory, and moved into place
+	// after its permissions are restricted, so others cannot connect in the meantime.
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".control-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	bindPath := filepath.Join(dir, filepath.Base(path))
+	listener, err := net.Listen("unix", bindPath)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is removed by Close from the path it is moved to.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err := os.Chmod(bindPath, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(bindPath, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	s := &ControlServer{
+		server:   &http.Server{Handler: controlHandler(l), ReadHeaderTimeout: controlRequestTimeout},
+		listener: listener,
+		path:     path,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Control socket failed: %s", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Returns the handler of the control requests, served on the control socket and by the REST API.
+func controlHandler(l *EventLoop) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		reply := make(chan Status, 1)
+		select {
+		case l.statusRequests <- reply:
+		case <-r.Context().Done():
+			return
+		case <-l.ctx.Done():
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		writeJSON(w, <-reply)
+	})
+
+	mux.HandleFunc("GET /healthz", healthHandler(l))
+	mux.HandleFunc("GET /events", eventStreamHandler(l))
+
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "json" {
+			snapshot := metricsSnapshot(state.Mappings())
+			daemon := l.daemonMetrics(r.Context())
+			snapshot.Daemon = &daemon
+			writeJSON(w, snapshot)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		daemon := l.daemonMetrics(r.Context())
+		if err := writeMetrics(w, state.Mappings(), &daemon); err != nil {
+			log.Debugf("Cannot write metrics: %s", err)
+		}
+	})
+
+	mux.HandleFunc("GET /mappings", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, state.Mappings())
+	})
+
+	mux.HandleFunc("GET /notifications", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, notificationHistory.Notifications())
+	})
+
+	mux.HandleFunc("GET /history", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		writeJSON(w, filterRenameRecords(renameHistory.Records(), r.URL.Query().Get("container"), limit))
+	})
+
+	mux.HandleFunc("POST /resync", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case l.resyncRequests <- struct{}{}:
+			writeJSON(w, struct{}{})
+		case <-r.Context().Done():
+		case <-l.ctx.Done():
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		}
+	})
+
+	mux.HandleFunc("POST /revert", revertHandler(l))
+	mux.HandleFunc("POST /revert/{container}", revertHandler(l))
+
+	mux.HandleFunc("POST /reload", func(w http.ResponseWriter, r *http.Request) {
+		reply := make(chan error, 1)
+		select {
+		case l.reloadRequests <- reply:
+		case <-r.Context().Done():
+			return
+		case <-l.ctx.Done():
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		if err := <-reply; err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeJSON(w, struct{}{})
+	})
+
+	mux.HandleFunc("POST /pause", pauseHandler(true))
+	mux.HandleFunc("POST /resume", pauseHandler(false))
+
+	return mux
+}
+
+// Returns the handler of the revert requests. All tracked containers are reverted, unless the container is specified.
+func revertHandler(l *EventLoop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := RevertRequest{Container: r.PathValue("container"), Reply: make(chan error, 1)}
+		select {
+		case l.revertRequests <- req:
+		case <-r.Context().Done():
+			return
+		case <-l.ctx.Done():
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		if err := <-req.Reply; err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, struct{}{})
+	}
+}
+
+// Enables or disables the maintenance mode. The event loop picks up the change on its next check.
+func pauseHandler(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		configMu.RLock()
+		err := setPaused(paused)
+		configMu.RUnlock()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, struct{}{})
+	}
+}
+
+// Stops serving, and removes the socket. Nil server is ignored.
+func (s *ControlServer) Close() {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlRequestTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Errorf("Control socket shutdown failed: %s", err)
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Errorf("Cannot remove control socket: %s: %s", s.path, err)
+	}
+}
+
+// Writes the value as the JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("json.Encode failed: %s", err)
+	}
+}
+
+// Request to revert the links of the container, specified by name or ID. Empty container means all tracked containers.
+type RevertRequest struct {
+	Container string
+	Reply     chan error
+}
+
+// Submits reverting of the tracked container links to the workers. Must be called from the loop goroutine.
+// All tracked containers are reverted when the name is empty.
+func (l *EventLoop) revertContainer(nameOrID string) error {
+	if len(nameOrID) == 0 {
+		log.Info("Revert requested for all containers")
+		for _, cs := range state.Containers() {
+			l.dispatcher.SubmitLocked(l.ctx, cs.ID, func(context.Context) {
+				revertContainerLinks(cs.ID)
+			})
+		}
+		return nil
+	}
+
+	index := slices.IndexFunc(state.Containers(), func(cs ContainerState) bool {
+		return matchContainer(cs, nameOrID)
+	})
+	if index == -1 {
+		return fmt.Errorf("container is not tracked: %s", nameOrID)
+	}
+
+	containerID := state.Containers()[index].ID
+	log.Infof("Revert requested for container: %s", containerID)
+	l.dispatcher.SubmitLocked(l.ctx, containerID, func(context.Context) {
+		revertContainerLinks(containerID)
+	})
+	return nil
+}
+
+// Makes the status of the daemon. Must be called from the loop goroutine.
+func (l *EventLoop) status() Status {
+	status := Status{
+		Version:            AppVersion,
+		StartTime:          l.startTime,
+		Uptime:             time.Since(l.startTime).Round(time.Second).String(),
+		DockerConnected:    l.errs != nil,
+		DockerPingFailures: l.pingFailures,
+		Paused:             l.paused,
+		PendingTasks:       l.dispatcher.Pending(),
+		QueuedContainers:   l.dispatcher.Queued(),
+		PendingRetries:     pendingRetries.Load(),
+		LastEventTime:      l.lastEvent.Time,
+	}
+
+	if q := l.eventQueue; q != nil {
+		status.EventQueueDepth = q.Depth()
+		status.EventQueueCapacity = q.Capacity()
+		status.EventsDropped = q.droppedCount.Load()
+		status.EventReadsDeferred = q.deferredCount.Load()
+	}
+
+	for _, cs := range state.Containers() {
+		status.TrackedContainers++
+		status.TrackedLinks += len(cs.Links)
+	}
+
+	return status
+}
+
+// Returns the HTTP client connecting to the control socket.
+func controlClient(path string) *http.Client {
+	return &http.Client{
+		Timeout: controlRequestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// Sends the request to the running daemon via the control socket, and decodes the JSON response.
+func controlRequest(path string, method string, endpoint string, v any) error {
+	req, err := http.NewRequest(method, "http://daemon"+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := controlClient(path).Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("daemon responded: %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Returned by the control requests when the daemon does not listen on the control socket.
+var errDaemonNotRunning = errors.New("daemon is not running")
+
+// Sends the administrative command to the running daemon via the control socket.
+// Returns errDaemonNotRunning when the socket is disabled or nobody listens on it, so the caller may act locally instead.
+func controlCommand(path string, endpoint string) error {
+	if len(path) == 0 {
+		return errDaemonNotRunning
+	}
+
+	var reply struct{}
+	err := controlRequest(path, http.MethodPost, endpoint, &reply)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+		return errDaemonNotRunning
+	}
+	return err
+}
+
+// Enables or disables the maintenance mode of the running daemon. The pause file is changed directly when the daemon is not running.
+func requestPause(paused bool) error {
+	endpoint := "/resume"
+	if paused {
+		endpoint = "/pause"
+	}
+
+	err := controlCommand(config.ControlSocket, endpoint)
+	if errors.Is(err, errDaemonNotRunning) {
+		return setPaused(paused)
+	}
+	return err
+}
+
+// Requests the running daemon to revert the links of the containers, or of all tracked containers when none are specified.
+// Returns errDaemonNotRunning when the daemon is not running, so the links may be reverted locally.
+func requestRevert(path string, containers []string) error {
+	if len(containers) == 0 {
+		return controlCommand(path, "/revert")
+	}
+
+	var failed []string
+	for _, nameOrID := range containers {
+		err := controlCommand(path, "/revert/"+url.PathEscape(nameOrID))
+		if errors.Is(err, errDaemonNotRunning) {
+			return err
+		}
+		if err != nil {
+			log.Errorf("Cannot revert container: %s: %s", nameOrID, err)
+			failed = append(failed, nameOrID)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot revert containers: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Sends the GET request to the running daemon via the control socket, and copies the response to the writer.
+func controlRequestText(path string, endpoint string, w io.Writer) error {
+	resp, err := controlClient(path).Get("http://daemon" + endpoint)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("daemon responded: %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Prints the status of the running daemon.
+func printStatus(path string) error {
+	if len(path) == 0 {
+		return errors.New("control socket is disabled in the configuration")
+	}
+
+	var status Status
+	if err := controlRequest(path, http.MethodGet, "/status", &status); err != nil {
+		return err
+	}
+
+	lastEvent := "never"
+	if !status.LastEventTime.IsZero() {
+		lastEvent = status.LastEventTime.Format(time.RFC3339)
+	}
+
+	return printOutput(os.Stdout, status, func(w io.Writer) error {
+		fmt.Fprintf(w, "Version:            %s\n", status.Version)
+		fmt.Fprintf(w, "Started:            %s (uptime %s)\n", status.StartTime.Format(time.RFC3339), status.Uptime)
+		fmt.Fprintf(w, "Docker connected:   %t\n", status.DockerConnected)
+		fmt.Fprintf(w, "Ping failures:      %d\n", status.DockerPingFailures)
+		fmt.Fprintf(w, "Paused:             %t\n", status.Paused)
+		fmt.Fprintf(w, "Tracked containers: %d\n", status.TrackedContainers)
+		fmt.Fprintf(w, "Tracked links:      %d\n", status.TrackedLinks)
+		fmt.Fprintf(w, "Pending tasks:      %d\n", status.PendingTasks)
+		fmt.Fprintf(w, "Queued containers:  %d\n", status.QueuedContainers)
+		fmt.Fprintf(w, "Pending retries:    %d\n", status.PendingRetries)
+		fmt.Fprintf(w, "Event queue:        %d/%d (%d dropped, %d deferred)\n",
+			status.EventQueueDepth, status.EventQueueCapacity, status.EventsDropped, status.EventReadsDeferred)
+		_, err := fmt.Fprintf(w, "Last event:         %s\n", lastEvent)
+		return err
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlServerStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, statusRequests: make(chan chan Status)}
+	go func() {
+		for {
+			select {
+			case reply := <-l.statusRequests:
+				reply <- Status{Version: "test", TrackedLinks: 3}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "control.sock")
+	s, err := newControlServer(path, l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+
+	var status Status
+	require.NoError(t, controlRequest(path, http.MethodGet, "/status", &status))
+	assert.Equal(t, "test", status.Version)
+	assert.Equal(t, 3, status.TrackedLinks)
+
+	assert.Error(t, controlRequest(path, http.MethodGet, "/unknown", &status))
+
+	// Neither the socket nor its private directory is left behind.
+	s.Close()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestControlServerMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, statusRequests: make(chan chan Status)}
+	go func() {
+		for {
+			select {
+			case reply := <-l.statusRequests:
+				reply <- Status{PendingTasks: 2}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "control.sock")
+	s, err := newControlServer(path, l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	var b strings.Builder
+	require.NoError(t, controlRequestText(path, "/metrics", &b))
+	assert.Contains(t, b.String(), "# TYPE dvn_tracked_links gauge\n")
+	assert.Contains(t, b.String(), "dvn_event_loop_up 1\n")
+	assert.Contains(t, b.String(), "dvn_pending_tasks 2\n")
+
+	var snapshot MetricsSnapshot
+	require.NoError(t, controlRequest(path, http.MethodGet, "/metrics?format=json", &snapshot))
+	assert.Contains(t, snapshot.Durations, "rename")
+	require.NotNil(t, snapshot.Daemon)
+	assert.Equal(t, 2, snapshot.Daemon.PendingTasks)
+	assert.Positive(t, snapshot.Daemon.Goroutines)
+
+	assert.Error(t, controlRequestText(path, "/unknown", &b))
+}
+
+func TestControlCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, reloadRequests: make(chan chan error), revertRequests: make(chan RevertRequest)}
+	var reverted []string
+	go func() {
+		for {
+			select {
+			case reply := <-l.reloadRequests:
+				reply <- errors.New("invalid configuration")
+			case req := <-l.revertRequests:
+				reverted = append(reverted, req.Container)
+				req.Reply <- nil
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "control.sock")
+	assert.ErrorIs(t, controlCommand(path, "/resync"), errDaemonNotRunning)
+	assert.ErrorIs(t, controlCommand("", "/resync"), errDaemonNotRunning)
+
+	s, err := newControlServer(path, l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	err = controlCommand(path, "/reload")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errDaemonNotRunning)
+	assert.Contains(t, err.Error(), "invalid configuration")
+
+	require.NoError(t, requestRevert(path, nil))
+	require.NoError(t, requestRevert(path, []string{"web", "db"}))
+	assert.Equal(t, []string{"", "web", "db"}, reverted)
+}
+
+func TestAPIServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, resyncRequests: make(chan struct{}, 1)}
+	s, err := newAPIServer("127.0.0.1:0", l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	base := "http://" + s.listener.Addr().String() + apiPathPrefix
+
+	resp, err := http.Post(base+"/resync", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, l.resyncRequests, 1)
+
+	resp, err = http.Get(base + "/mappings")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get("http://" + s.listener.Addr().String() + "/mappings")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
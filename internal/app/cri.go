@@ -0,0 +1,345 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Interval of listing the pod sandboxes to detect the started and stopped pods.
+// CRI has no event stream common to all runtimes.
+var criPollInterval = 2 * time.Second
+
+var errCRINotFound = errors.New("no such pod sandbox")
+
+// Container runtime interface (CRI) client, e.g. of containerd or CRI-O run by kubelet.
+// The pod sandboxes are presented as the containers: the sandbox holds the network namespace shared by the pod.
+type CRIRuntime struct {
+	conn   *grpc.ClientConn
+	client runtimeapi.RuntimeServiceClient
+}
+
+// Makes the CRI client of the endpoint in form "unix:///path". The connection is made on the first request.
+func newCRIRuntime(endpoint string) (*CRIRuntime, error) {
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &CRIRuntime{conn: conn, client: runtimeapi.NewRuntimeServiceClient(conn)}, nil
+}
+
+// Returns the name of the container presenting the pod sandbox.
+func criContainerName(metadata *runtimeapi.PodSandboxMetadata) string {
+	return "/" + metadata.GetName()
+}
+
+// Returns the labels of the pod sandbox, along with the pod labels set by kubelet, which are taken from the metadata.
+func criLabels(metadata *runtimeapi.PodSandboxMetadata, labels map[string]string) map[string]string {
+	labels = maps.Clone(labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[kubernetesPodNameLabel] = metadata.GetName()
+	labels[kubernetesPodNamespaceLabel] = metadata.GetNamespace()
+	return labels
+}
+
+// Returns whether the pod sandbox matches the Docker container list filters. Only the filters by the label,
+// the name, and the ID are supported.
+func criMatch(sandbox *runtimeapi.PodSandbox, filterArgs filters.Args) (bool, error) {
+	for _, key := range filterArgs.Keys() {
+		switch key {
+		case "label", "name", "id":
+		default:
+			return false, fmt.Errorf("filter is not supported by CRI runtime: %s", key)
+		}
+	}
+
+	labels := criLabels(sandbox.GetMetadata(), sandbox.GetLabels())
+	for _, label := range filterArgs.Get("label") {
+		key, value, hasValue := strings.Cut(label, "=")
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false, nil
+		}
+	}
+
+	name := strings.TrimPrefix(criContainerName(sandbox.GetMetadata()), "/")
+	if names := filterArgs.Get("name"); len(names) > 0 && !strings.Contains(name, names[0]) {
+		return false, nil
+	}
+	if ids := filterArgs.Get("id"); len(ids) > 0 && !strings.HasPrefix(sandbox.GetId(), ids[0]) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Returns the ready pod sandboxes, which are the running pods.
+func (r *CRIRuntime) readySandboxes(ctx context.Context) ([]*runtimeapi.PodSandbox, error) {
+	response, err := r.client.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{
+		Filter: &runtimeapi.PodSandboxFilter{
+			State: &runtimeapi.PodSandboxStateValue{State: runtimeapi.PodSandboxState_SANDBOX_READY},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.GetItems(), nil
+}
+
+func (r *CRIRuntime) ListContainers(ctx context.Context, filterArgs filters.Args) ([]container.Summary, error) {
+	sandboxes, err := r.readySandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []container.Summary
+	for _, sandbox := range sandboxes {
+		ok, err := criMatch(sandbox, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		containers = append(containers, container.Summary{
+			ID:      sandbox.GetId(),
+			Names:   []string{criContainerName(sandbox.GetMetadata())},
+			Labels:  criLabels(sandbox.GetMetadata(), sandbox.GetLabels()),
+			Created: time.Unix(0, sandbox.GetCreatedAt()).Unix(),
+			State:   container.StateRunning,
+		})
+	}
+	return containers, nil
+}
+
+// Returns the ID of the pod sandbox specified by the ID, its prefix, or the pod name.
+func (r *CRIRuntime) sandboxID(ctx context.Context, nameOrID string) (string, error) {
+	response, err := r.client.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+	if err != nil {
+		return "", err
+	}
+
+	var found []string
+	for _, sandbox := range response.GetItems() {
+		if sandbox.GetId() == nameOrID {
+			return nameOrID, nil
+		}
+		if strings.HasPrefix(sandbox.GetId(), nameOrID) || sandbox.GetMetadata().GetName() == strings.TrimPrefix(nameOrID, "/") {
+			found = append(found, sandbox.GetId())
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", errCRINotFound, nameOrID)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("pod sandbox is ambiguous: %s matches %d sandboxes", nameOrID, len(found))
+	}
+}
+
+// Part of the verbose pod sandbox status, reported alike by containerd and CRI-O.
+type criSandboxInfo struct {
+	Pid         int `json:"pid"`
+	RuntimeSpec struct {
+		Linux struct {
+			Namespaces []struct {
+				Type string `json:"type"`
+				Path string `json:"path"`
+			} `json:"namespaces"`
+		} `json:"linux"`
+	} `json:"runtimeSpec"`
+}
+
+// Returns the path of the network namespace of the pod sandbox from its verbose status,
+// or empty string if not reported.
+func criNetworkNamespace(info map[string]string) string {
+	var sandboxInfo criSandboxInfo
+	if err := json.Unmarshal([]byte(info["info"]), &sandboxInfo); err != nil {
+		return ""
+	}
+
+	for _, namespace := range sandboxInfo.RuntimeSpec.Linux.Namespaces {
+		if namespace.Type == "network" && len(namespace.Path) > 0 {
+			return namespace.Path
+		}
+	}
+	if sandboxInfo.Pid > 0 {
+		return "/proc/" + strconv.Itoa(sandboxInfo.Pid) + "/ns/net"
+	}
+	return ""
+}
+
+func (r *CRIRuntime) Inspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	id, err := r.sandboxID(ctx, containerID)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	response, err := r.client.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: id, Verbose: true})
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	sandbox := response.GetStatus()
+	running := sandbox.GetState() == runtimeapi.PodSandboxState_SANDBOX_READY
+	state := &container.State{Running: running, Status: container.StateRunning}
+	if !running {
+		state.Status = container.StateExited
+	}
+
+	var networkMode container.NetworkMode
+	if sandbox.GetLinux().GetNamespaces().GetOptions().GetNetwork() == runtimeapi.NamespaceMode_NODE {
+		networkMode = container.NetworkMode(network.NetworkHost)
+	}
+
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         sandbox.GetId(),
+			Name:       criContainerName(sandbox.GetMetadata()),
+			Created:    time.Unix(0, sandbox.GetCreatedAt()).UTC().Format(time.RFC3339Nano),
+			State:      state,
+			HostConfig: &container.HostConfig{NetworkMode: networkMode},
+		},
+		Config: &container.Config{Labels: criLabels(sandbox.GetMetadata(), sandbox.GetLabels())},
+		NetworkSettings: &container.NetworkSettings{
+			NetworkSettingsBase: container.NetworkSettingsBase{SandboxKey: criNetworkNamespace(response.GetInfo())},
+		},
+	}, nil
+}
+
+// Streams the container start and die events of the pods, detected by listing the ready pod sandboxes periodically.
+// The pods started since the time are reported by the first listing.
+func (r *CRIRuntime) WatchEvents(ctx context.Context, filterArgs filters.Args, since time.Time) (<-chan events.Message, <-chan error) {
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	send := func(action events.Action, id string, name string) bool {
+		if !filterArgs.ExactMatch("type", string(events.ContainerEventType)) || !filterArgs.ExactMatch("event", string(action)) {
+			return true
+		}
+
+		now := time.Now()
+		message := events.Message{
+			Type:     events.ContainerEventType,
+			Action:   action,
+			Actor:    events.Actor{ID: id, Attributes: map[string]string{"name": name}},
+			Scope:    "local",
+			Time:     now.Unix(),
+			TimeNano: now.UnixNano(),
+		}
+		select {
+		case messages <- message:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(criPollInterval)
+		defer ticker.Stop()
+
+		// Names of the ready sandboxes by ID, as of the previous listing.
+		var known map[string]string
+		for {
+			sandboxes, err := r.readySandboxes(ctx)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			current := make(map[string]string, len(sandboxes))
+			for _, sandbox := range sandboxes {
+				name := strings.TrimPrefix(criContainerName(sandbox.GetMetadata()), "/")
+				current[sandbox.GetId()] = name
+
+				_, seen := known[sandbox.GetId()]
+				started := seen || (known == nil && (since.IsZero() || time.Unix(0, sandbox.GetCreatedAt()).Before(since)))
+				if !started && !send(events.ActionStart, sandbox.GetId(), name) {
+					errs <- ctx.Err()
+					return
+				}
+			}
+			for id, name := range known {
+				if _, ok := current[id]; !ok && !send(events.ActionDie, id, name) {
+					errs <- ctx.Err()
+					return
+				}
+			}
+			known = current
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return messages, errs
+}
+
+func (r *CRIRuntime) ResolveNetns(inspect container.InspectResponse) (string, error) {
+	return dockerwatch.NetworkNamespace(inspect)
+}
+
+func (r *CRIRuntime) Ping(ctx context.Context) error {
+	_, err := r.client.Version(ctx, &runtimeapi.VersionRequest{})
+	return err
+}
+
+func (r *CRIRuntime) Version(ctx context.Context) (string, error) {
+	version, err := r.client.Version(ctx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s, CRI %s", version.GetRuntimeName(), version.GetRuntimeVersion(), version.GetRuntimeApiVersion()), nil
+}
+
+func (r *CRIRuntime) IsNotFound(err error) bool {
+	return errors.Is(err, errCRINotFound) || status.Code(err) == codes.NotFound
+}
+
+func (r *CRIRuntime) Close() error {
+	return r.conn.Close()
+}
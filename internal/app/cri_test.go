@@ -0,0 +1,277 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// CRI runtime serving the pod sandboxes from memory.
+type fakeCRIServer struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+
+	mu        sync.Mutex
+	sandboxes []*runtimeapi.PodSandboxStatus
+	// Verbose status info by the sandbox ID.
+	info map[string]map[string]string
+}
+
+func (s *fakeCRIServer) Version(ctx context.Context, r *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{RuntimeName: "containerd", RuntimeVersion: "v2.1.4", RuntimeApiVersion: "v1"}, nil
+}
+
+func (s *fakeCRIServer) ListPodSandbox(ctx context.Context, r *runtimeapi.ListPodSandboxRequest) (*runtimeapi.ListPodSandboxResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []*runtimeapi.PodSandbox
+	for _, sandbox := range s.sandboxes {
+		if r.GetFilter().GetState() != nil && r.GetFilter().GetState().GetState() != sandbox.GetState() {
+			continue
+		}
+		items = append(items, &runtimeapi.PodSandbox{
+			Id:        sandbox.GetId(),
+			Metadata:  sandbox.GetMetadata(),
+			State:     sandbox.GetState(),
+			CreatedAt: sandbox.GetCreatedAt(),
+			Labels:    sandbox.GetLabels(),
+		})
+	}
+	return &runtimeapi.ListPodSandboxResponse{Items: items}, nil
+}
+
+func (s *fakeCRIServer) PodSandboxStatus(ctx context.Context, r *runtimeapi.PodSandboxStatusRequest) (*runtimeapi.PodSandboxStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sandbox := range s.sandboxes {
+		if sandbox.GetId() == r.GetPodSandboxId() {
+			response := &runtimeapi.PodSandboxStatusResponse{Status: sandbox}
+			if r.GetVerbose() {
+				response.Info = s.info[sandbox.GetId()]
+			}
+			return response, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "an error occurred when try to find sandbox %q: not found", r.GetPodSandboxId())
+}
+
+// Sets the sandboxes served.
+func (s *fakeCRIServer) setSandboxes(sandboxes ...*runtimeapi.PodSandboxStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sandboxes = sandboxes
+}
+
+// Starts the CRI server. Returns the client of it.
+func startFakeCRI(t *testing.T, s *fakeCRIServer) *CRIRuntime {
+	socket := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	rt, err := newCRIRuntime("unix://" + socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { rt.Close() })
+	return rt
+}
+
+func criSandbox(id string, name string, namespace string, state runtimeapi.PodSandboxState, created time.Time) *runtimeapi.PodSandboxStatus {
+	return &runtimeapi.PodSandboxStatus{
+		Id:        id,
+		Metadata:  &runtimeapi.PodSandboxMetadata{Name: name, Namespace: namespace, Uid: "uid-" + id},
+		State:     state,
+		CreatedAt: created.UnixNano(),
+		Labels:    map[string]string{"app": name},
+	}
+}
+
+func TestCRIRuntime(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	spec, err := json.Marshal(map[string]any{
+		"pid": 4321,
+		"runtimeSpec": map[string]any{"linux": map[string]any{"namespaces": []map[string]string{
+			{"type": "pid"},
+			{"type": "network", "path": "/var/run/netns/cni-0f3c2a1e"},
+		}}},
+	})
+	require.NoError(t, err)
+
+	hostNetwork := criSandbox("e5f6a7b8", "node-exporter-x2k4p", "monitoring", runtimeapi.PodSandboxState_SANDBOX_READY, created)
+	hostNetwork.Linux = &runtimeapi.LinuxPodSandboxStatus{Namespaces: &runtimeapi.Namespace{
+		Options: &runtimeapi.NamespaceOption{Network: runtimeapi.NamespaceMode_NODE},
+	}}
+
+	server := &fakeCRIServer{info: map[string]map[string]string{
+		"a1b2c3d4": {"info": string(spec)},
+		"c3d4e5f6": {"info": `{"pid":1234}`},
+	}}
+	server.setSandboxes(
+		criSandbox("a1b2c3d4", "web-7d4b9", "default", runtimeapi.PodSandboxState_SANDBOX_READY, created),
+		criSandbox("b2c3d4e5", "web-5c8a1", "default", runtimeapi.PodSandboxState_SANDBOX_NOTREADY, created),
+		criSandbox("c3d4e5f6", "api-5f6a7", "prod", runtimeapi.PodSandboxState_SANDBOX_READY, created),
+		hostNetwork,
+	)
+	rt := startFakeCRI(t, server)
+
+	require.NoError(t, rt.Ping(context.Background()))
+	version, err := rt.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "containerd v2.1.4, CRI v1", version)
+
+	containers, err := rt.ListContainers(context.Background(), filters.NewArgs())
+	require.NoError(t, err)
+	require.Len(t, containers, 3)
+	assert.Equal(t, container.Summary{
+		ID:      "a1b2c3d4",
+		Names:   []string{"/web-7d4b9"},
+		Labels:  map[string]string{"app": "web-7d4b9", kubernetesPodNameLabel: "web-7d4b9", kubernetesPodNamespaceLabel: "default"},
+		Created: created.Unix(),
+		State:   container.StateRunning,
+	}, containers[0])
+
+	containers, err = rt.ListContainers(context.Background(), filters.NewArgs(filters.Arg("label", kubernetesPodNamespaceLabel+"=prod")))
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "c3d4e5f6", containers[0].ID)
+
+	containers, err = rt.ListContainers(context.Background(), filters.NewArgs(filters.Arg("name", "web")))
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "a1b2c3d4", containers[0].ID)
+
+	_, err = rt.ListContainers(context.Background(), filters.NewArgs(filters.Arg("network", "bridge")))
+	assert.EqualError(t, err, "filter is not supported by CRI runtime: network")
+
+	// Network namespace of the runtime spec.
+	inspect, err := rt.Inspect(context.Background(), "a1b2c3d4")
+	require.NoError(t, err)
+	assert.Equal(t, "/web-7d4b9", inspect.Name)
+	assert.True(t, inspect.State.Running)
+	assert.Equal(t, "default", inspect.Config.Labels[kubernetesPodNamespaceLabel])
+	netns, err := rt.ResolveNetns(inspect)
+	require.NoError(t, err)
+	assert.Equal(t, "/var/run/netns/cni-0f3c2a1e", netns)
+
+	// Network namespace of the sandbox process, found by the pod name.
+	inspect, err = rt.Inspect(context.Background(), "api-5f6a7")
+	require.NoError(t, err)
+	assert.Equal(t, "c3d4e5f6", inspect.ID)
+	netns, err = rt.ResolveNetns(inspect)
+	require.NoError(t, err)
+	assert.Equal(t, "/proc/1234/ns/net", netns)
+
+	// Stopped pod found by the ID prefix.
+	inspect, err = rt.Inspect(context.Background(), "b2c3")
+	require.NoError(t, err)
+	assert.False(t, inspect.State.Running)
+	_, err = rt.ResolveNetns(inspect)
+	assert.ErrorIs(t, err, dockerwatch.ErrNoSandbox)
+
+	inspect, err = rt.Inspect(context.Background(), "e5f6a7b8")
+	require.NoError(t, err)
+	_, err = rt.ResolveNetns(inspect)
+	assert.ErrorIs(t, err, dockerwatch.ErrHostNetwork)
+
+	_, err = rt.Inspect(context.Background(), "ffff")
+	assert.True(t, rt.IsNotFound(err))
+}
+
+func TestCRIRuntimeWatchEvents(t *testing.T) {
+	defer func(interval time.Duration) { criPollInterval = interval }(criPollInterval)
+	criPollInterval = 10 * time.Millisecond
+
+	now := time.Now()
+	server := &fakeCRIServer{}
+	server.setSandboxes(
+		criSandbox("a1b2c3d4", "web-7d4b9", "default", runtimeapi.PodSandboxState_SANDBOX_READY, now.Add(-time.Hour)),
+		criSandbox("b2c3d4e5", "api-5f6a7", "prod", runtimeapi.PodSandboxState_SANDBOX_READY, now.Add(-time.Minute)),
+	)
+	rt := startFakeCRI(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	filterArgs := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("event", string(events.ActionStart)),
+		filters.Arg("event", string(events.ActionDie)),
+	)
+	messages, errs := rt.WatchEvents(ctx, filterArgs, now.Add(-10*time.Minute))
+
+	receive := func() events.Message {
+		select {
+		case message := <-messages:
+			return message
+		case err := <-errs:
+			require.FailNow(t, "events stream failed", err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no event")
+		}
+		return events.Message{}
+	}
+
+	// Pod started since the time is replayed.
+	message := receive()
+	assert.Equal(t, events.ContainerEventType, message.Type)
+	assert.Equal(t, events.ActionStart, message.Action)
+	assert.Equal(t, "b2c3d4e5", message.Actor.ID)
+	assert.Equal(t, "api-5f6a7", message.Actor.Attributes["name"])
+
+	server.setSandboxes(
+		criSandbox("b2c3d4e5", "api-5f6a7", "prod", runtimeapi.PodSandboxState_SANDBOX_READY, now.Add(-time.Minute)),
+		criSandbox("c3d4e5f6", "db-0", "prod", runtimeapi.PodSandboxState_SANDBOX_READY, now),
+	)
+	message = receive()
+	assert.Equal(t, events.ActionStart, message.Action)
+	assert.Equal(t, "c3d4e5f6", message.Actor.ID)
+	message = receive()
+	assert.Equal(t, events.ActionDie, message.Action)
+	assert.Equal(t, "a1b2c3d4", message.Actor.ID)
+
+	cancel()
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "events stream is not ended")
+	}
+}
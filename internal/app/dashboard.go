@@ -0,0 +1,147 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of the recent notifications and failures shown on the dashboard.
+const dashboardRecentSize = 20
+
+// Data of the dashboard page.
+type Dashboard struct {
+	Time          time.Time
+	Version       string
+	Health        Health
+	Mappings      []ContainerMapping
+	TrackedLinks  int
+	Notifications []Notification
+	Failures      []RenameRecord
+	Config        Config
+	ConfigHash    string
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"trimSlash": func(name string) string { return strings.TrimPrefix(name, "/") },
+	"timeFmt":   func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="10">
+<title>docker-veth-namer</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: left; }
+th { background: #eee; }
+.ok { color: #080; }
+.problem { color: #b00; }
+</style>
+</head>
+<body>
+<h1>docker-veth-namer {{.Version}}</h1>
+<p>Updated at {{timeFmt .Time}}.
+{{if .Health.Healthy}}<span class="ok">Healthy</span>{{else}}<span class="problem">Not healthy: {{range $i, $p := .Health.Problems}}{{if $i}}, {{end}}{{$p}}{{end}}</span>{{end}}.
+Last Docker event: {{if .Health.LastEventAge}}{{.Health.LastEventAge}} ago{{else}}none{{end}}.</p>
+
+<h2>Mappings ({{len .Mappings}} containers, {{.TrackedLinks}} links)</h2>
+<table>
+<tr><th>Container</th><th>Container link</th><th>Host link</th><th>Original name</th><th>Ifindex</th><th>Image</th><th>Network</th></tr>
+{{range $m := .Mappings}}{{range .Links}}<tr><td>{{trimSlash $m.Name}}</td><td>{{.ContainerLink}}</td><td>{{.Name}}</td><td>{{.OriginalName}}</td><td>{{.Index}}</td><td>{{.Image}}</td><td>{{.Network}}</td></tr>
+{{end}}{{end}}</table>
+
+<h2>Recent events</h2>
+<table>
+<tr><th>Time</th><th>Type</th><th>Container</th><th>Container link</th><th>Host link</th></tr>
+{{range .Notifications}}<tr><td>{{timeFmt .Time}}</td><td>{{.Type}}</td><td>{{trimSlash .ContainerName}}</td><td>{{.ContainerLink}}</td><td>{{.Name}}</td></tr>
+{{end}}</table>
+
+<h2>Recent failures</h2>
+<table>
+<tr><th>Time</th><th>Operation</th><th>Container</th><th>Container link</th><th>Old name</th><th>New name</th><th>Error</th></tr>
+{{range .Failures}}<tr><td>{{timeFmt .Time}}</td><td>{{.Operation}}</td><td>{{trimSlash .ContainerName}}</td><td>{{.ContainerLink}}</td><td>{{.OldName}}</td><td>{{.NewName}}</td><td class="problem">{{.Error}}</td></tr>
+{{end}}</table>
+
+<h2>Configuration</h2>
+<table>
+<tr><th>Hash</th><td>{{.ConfigHash}}</td></tr>
+<tr><th>link_name_prefix</th><td>{{.Config.LinkNamePrefix}}</td></tr>
+<tr><th>link_index_separator</th><td>{{.Config.LinkIndexSeparator}}</td></tr>
+<tr><th>container_link_prefixes</th><td>{{range $i, $p := .Config.ContainerLinkPrefixes}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+<tr><th>replacements</th><td>{{len .Config.Replacements}}</td></tr>
+<tr><th>event_triggers</th><td>{{range $i, $t := .Config.EventTriggers}}{{if $i}}, {{end}}{{$t}}{{end}}</td></tr>
+<tr><th>restore_name_on_disconnect</th><td>{{.Config.RestoreNameOnDisconnect}}</td></tr>
+<tr><th>event_workers</th><td>{{.Config.EventWorkers}}</td></tr>
+<tr><th>startup_workers</th><td>{{.Config.StartupWorkers}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// Returns the most recent items, newest first.
+func recentFirst[T any](items []T, limit int) []T {
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	items = slices.Clone(items)
+	slices.Reverse(items)
+	return items
+}
+
+// Serves the dashboard page with the live mappings, recent events and failures, and the configuration summary.
+func dashboardHandler(l *EventLoop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The health is queried before locking the configuration, as the event loop may be waiting to reload it.
+		health := l.health(r.Context())
+
+		configMu.RLock()
+		c := config
+		configMu.RUnlock()
+
+		mappings := state.Mappings()
+		failures := slices.DeleteFunc(renameHistory.Records(), func(r RenameRecord) bool { return len(r.Error) == 0 })
+
+		d := Dashboard{
+			Time:          time.Now(),
+			Version:       AppVersion,
+			Health:        health,
+			Mappings:      mappings,
+			TrackedLinks:  countLinks(mappings),
+			Notifications: recentFirst(notificationHistory.Notifications(), dashboardRecentSize),
+			Failures:      recentFirst(failures, dashboardRecentSize),
+			Config:        c,
+			ConfigHash:    configHash(c),
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, d); err != nil {
+			log.Debugf("Cannot write dashboard: %s", err)
+		}
+	}
+}
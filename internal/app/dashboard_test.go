@@ -0,0 +1,63 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardTemplate(t *testing.T) {
+	d := Dashboard{
+		Time:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version: "1.0.0",
+		Health:  Health{Healthy: true},
+		Mappings: []ContainerMapping{
+			{ID: "0123", Name: "/web", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}}},
+		},
+		TrackedLinks: 1,
+		Failures: []RenameRecord{
+			{Operation: RenameApply, ContainerName: "/<script>", ContainerLink: "eth0", Error: "file exists"},
+		},
+		Config:     defaultConfig(),
+		ConfigHash: "0a1b2c3d4e5f",
+	}
+
+	var b strings.Builder
+	require.NoError(t, dashboardTemplate.Execute(&b, d))
+	page := b.String()
+	assert.Contains(t, page, "<td>web</td><td>eth0</td><td>vweb0</td><td>veth1</td><td>10</td>")
+	assert.Contains(t, page, "1 containers, 1 links")
+	assert.Contains(t, page, `<td class="problem">file exists</td>`)
+	assert.Contains(t, page, "&lt;script&gt;")
+	assert.NotContains(t, page, "<script>")
+}
+
+func TestRecentFirst(t *testing.T) {
+	items := []int{1, 2, 3, 4}
+	assert.Equal(t, []int{4, 3}, recentFirst(items, 2))
+	assert.Equal(t, []int{4, 3, 2, 1}, recentFirst(items, 10))
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Well-known name of the daemon on the system bus.
+	dbusName = "io.github.a_ilin.DockerVethNamer"
+	// Object exporting the interface.
+	dbusObjectPath = "/io/github/a_ilin/DockerVethNamer"
+	// Interface of the signals and the methods.
+	dbusInterface = "io.github.a_ilin.DockerVethNamer1"
+)
+
+// Signals emitted for the notification types.
+var dbusSignals = map[string]string{
+	NotificationMappingAdded:   "MappingAdded",
+	NotificationMappingRemoved: "MappingRemoved",
+	NotificationLinkFlapping:   "LinkFlapping",
+}
+
+// Introspection data of the exported object.
+const dbusIntrospection = `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">
+<node>
+  <interface name="` + dbusInterface + `">
+    <method name="GetMapping">
+      <arg name="host_link" type="s" direction="in"/>
+      <arg name="container_id" type="s" direction="out"/>
+      <arg name="container_name" type="s" direction="out"/>
+      <arg name="container_link" type="s" direction="out"/>
+      <arg name="ifindex" type="i" direction="out"/>
+      <arg name="original_name" type="s" direction="out"/>
+      <arg name="name" type="s" direction="out"/>
+    </method>` + dbusIntrospectionSignals + `
+  </interface>
+  <interface name="org.freedesktop.DBus.Introspectable">
+    <method name="Introspect">
+      <arg name="data" type="s" direction="out"/>
+    </method>
+  </interface>
+  <interface name="org.freedesktop.DBus.Peer">
+    <method name="Ping"/>
+  </interface>
+</node>
+`
+
+const dbusIntrospectionSignalArgs = `
+      <arg name="container_id" type="s"/>
+      <arg name="container_name" type="s"/>
+      <arg name="container_link" type="s"/>
+      <arg name="ifindex" type="i"/>
+      <arg name="original_name" type="s"/>
+      <arg name="name" type="s"/>
+    </signal>`
+
+const dbusIntrospectionSignals = `
+    <signal name="MappingAdded">` + dbusIntrospectionSignalArgs + `
+    <signal name="MappingRemoved">` + dbusIntrospectionSignalArgs + `
+    <signal name="LinkFlapping">` + dbusIntrospectionSignalArgs
+
+// Emits the notifications as signals on the system bus. When serving, the well-known name is acquired upfront,
+// and the mapping queries are answered; otherwise the signals are emitted from the unique name of the connection.
+// The bus address is taken from the environment variable DBUS_SYSTEM_BUS_ADDRESS, if set.
+type DBusSink struct {
+	serve bool
+	queue chan Notification
+	// Guards the connection, which is reset when the bus closes it.
+	mu   sync.Mutex
+	conn *dbus.Conn
+}
+
+// Makes the sink, serving the mapping queries when requested by the listen command,
+// so the other commands do not contend with the running daemon for the name.
+func newDBusSink(serve bool) *DBusSink {
+	s := &DBusSink{
+		serve: serve,
+		queue: make(chan Notification, notificationQueueSize),
+	}
+	go s.run()
+	return s
+}
+
+func (s *DBusSink) Notify(n Notification) {
+	select {
+	case s.queue <- n:
+	default:
+		log.Errorf("D-Bus queue is full, notification dropped: %s", n.Type)
+	}
+}
+
+func (s *DBusSink) Close() {
+	close(s.queue)
+}
+
+// Connects to the bus, and emits the queued notifications in order of appearance.
+// The connection is reestablished on the next notification when lost.
+func (s *DBusSink) run() {
+	if s.serve {
+		s.connect()
+	}
+	for n := range s.queue {
+		member, ok := dbusSignals[n.Type]
+		if !ok {
+			continue
+		}
+
+		conn := s.connection()
+		if conn == nil {
+			if conn = s.connect(); conn == nil {
+				continue
+			}
+		}
+
+		err := conn.Emit(dbusObjectPath, dbusInterface+"."+member,
+			n.ContainerID, n.ContainerName, n.ContainerLink, int32(n.Index), n.OriginalName, n.Name)
+		if err != nil {
+			log.Errorf("D-Bus signal failed: %s: %s", member, err)
+			s.disconnect(conn)
+		}
+	}
+
+	if conn := s.connection(); conn != nil {
+		s.disconnect(conn)
+	}
+}
+
+// Returns the current connection, or nil when not connected.
+func (s *DBusSink) connection() *dbus.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil && !s.conn.Connected() {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return s.conn
+}
+
+// Closes the connection, and forgets it unless replaced already.
+func (s *DBusSink) disconnect(conn *dbus.Conn) {
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	conn.Close()
+}
+
+// Connects to the bus. When serving, exports the object and acquires the well-known name. Returns nil on failure.
+func (s *DBusSink) connect() *dbus.Conn {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		log.Errorf("Cannot connect to D-Bus: %s", err)
+		return nil
+	}
+
+	if s.serve {
+		if err := exportDBusObject(conn); err != nil {
+			log.Errorf("Cannot acquire D-Bus name: %s: %s", dbusName, err)
+			conn.Close()
+			return nil
+		}
+		log.Infof("Connected to D-Bus as %s (%s)", dbusName, conn.Names()[0])
+	} else {
+		log.Debugf("Connected to D-Bus as %s", conn.Names()[0])
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+	return conn
+}
+
+// Exports the object answering the mapping queries, and acquires the well-known name.
+// Fails if the name is owned by another process.
+func exportDBusObject(conn *dbus.Conn) error {
+	if err := conn.Export(dbusMappingService{}, dbusObjectPath, dbusInterface); err != nil {
+		return err
+	}
+	if err := conn.Export(introspect.Introspectable(dbusIntrospection), dbusObjectPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(dbusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner && reply != dbus.RequestNameReplyAlreadyOwner {
+		return errors.New("the name is owned by another process")
+	}
+	return nil
+}
+
+// Methods of the exported interface.
+type dbusMappingService struct{}
+
+// Returns the mapping of the host link specified by its current or original name.
+func (dbusMappingService) GetMapping(hostLink string) (string, string, string, int32, string, string, *dbus.Error) {
+	for _, mapping := range state.Mappings() {
+		for _, link := range mapping.Links {
+			if link.Name == hostLink || link.OriginalName == hostLink {
+				return mapping.ID, mapping.Name, link.ContainerLink, int32(link.Index), link.OriginalName, link.Name, nil
+			}
+		}
+	}
+	return "", "", "", 0, "", "", dbus.NewError(dbusInterface+".Error.NotFound", []any{"Host link is not tracked: " + hostLink})
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBusGetMapping(t *testing.T) {
+	prevState := state
+	defer func() { state = prevState }()
+	state = newState()
+	state.SetLink("0123", "/web", LinkState{Index: 42, ContainerLink: "eth0", OriginalName: "veth1a2b3c4", Name: "vweb0"})
+
+	id, name, containerLink, index, originalName, linkName, dbusErr := dbusMappingService{}.GetMapping("vweb0")
+	require.Nil(t, dbusErr)
+	assert.Equal(t, []any{"0123", "/web", "eth0", int32(42), "veth1a2b3c4", "vweb0"}, []any{id, name, containerLink, index, originalName, linkName})
+
+	_, _, _, _, _, linkName, dbusErr = dbusMappingService{}.GetMapping("veth1a2b3c4")
+	require.Nil(t, dbusErr)
+	assert.Equal(t, "vweb0", linkName)
+
+	_, _, _, _, _, _, dbusErr = dbusMappingService{}.GetMapping("vdb0")
+	require.NotNil(t, dbusErr)
+	assert.Equal(t, dbusInterface+".Error.NotFound", dbusErr.Name)
+}
+
+// Starts a private bus, and points the system bus address to it.
+func startTestBus(t *testing.T) string {
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+
+	cmd := exec.Command(path, "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	address = strings.TrimSpace(address)
+	t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", address)
+	return address
+}
+
+func TestDBusSink(t *testing.T) {
+	address := startTestBus(t)
+
+	// The state is read by the bus connection goroutines, so it is not replaced.
+	state.SetLink("0123", "/web", LinkState{Index: 42, ContainerLink: "eth0", OriginalName: "veth1a2b3c4", Name: "vweb0"})
+	defer state.RemoveContainer("0123")
+
+	client, err := dbus.Connect(address)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.AddMatchSignal(dbus.WithMatchInterface(dbusInterface)))
+	signals := make(chan *dbus.Signal, 10)
+	client.Signal(signals)
+
+	sink := newDBusSink(true)
+	defer sink.Close()
+	require.Eventually(t, func() bool {
+		var owned bool
+		err := client.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, dbusName).Store(&owned)
+		return err == nil && owned
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The mapping queries are answered.
+	var id, name, containerLink, originalName, linkName string
+	var index int32
+	err = client.Object(dbusName, dbusObjectPath).Call(dbusInterface+".GetMapping", 0, "vweb0").
+		Store(&id, &name, &containerLink, &index, &originalName, &linkName)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"0123", "/web", "eth0", int32(42), "veth1a2b3c4", "vweb0"}, []any{id, name, containerLink, index, originalName, linkName})
+
+	var data string
+	err = client.Object(dbusName, dbusObjectPath).Call("org.freedesktop.DBus.Introspectable.Introspect", 0).Store(&data)
+	require.NoError(t, err)
+	assert.Contains(t, data, `<method name="GetMapping">`)
+
+	// Another process cannot acquire the name, but emits the signals.
+	other := newDBusSink(false)
+	defer other.Close()
+	other.Notify(Notification{Type: NotificationMappingAdded, ContainerID: "4567", ContainerName: "/db", ContainerLink: "eth0", Index: 43, OriginalName: "veth5d6e7f8", Name: "vdb0"})
+	sink.Notify(Notification{Type: NotificationMappingRemoved, ContainerID: "0123", ContainerName: "/web", ContainerLink: "eth0", Index: 42, OriginalName: "veth1a2b3c4", Name: "vweb0"})
+
+	received := make(map[string][]any)
+	for len(received) < 2 {
+		select {
+		case signal := <-signals:
+			received[signal.Name] = signal.Body
+		case <-time.After(5 * time.Second):
+			t.Fatalf("signals are not received: %v", received)
+		}
+	}
+	assert.Equal(t, []any{"4567", "/db", "eth0", int32(43), "veth5d6e7f8", "vdb0"}, received[dbusInterface+".MappingAdded"])
+	assert.Equal(t, []any{"0123", "/web", "eth0", int32(42), "veth1a2b3c4", "vweb0"}, received[dbusInterface+".MappingRemoved"])
+}
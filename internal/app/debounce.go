@@ -0,0 +1,113 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Coalesces repeated keys arriving within the time window.
+// The key becomes due once the window since its first appearance passes.
+type Debouncer struct {
+	window time.Duration
+	// Due time of the pending keys.
+	pending map[string]time.Time
+	timer   *time.Timer
+}
+
+func newDebouncer(window time.Duration) *Debouncer {
+	return &Debouncer{
+		window:  window,
+		pending: make(map[string]time.Time),
+	}
+}
+
+// Schedules the key, unless it is pending already.
+func (d *Debouncer) Add(key string) {
+	if _, ok := d.pending[key]; ok {
+		return
+	}
+
+	due := time.Now().Add(d.window)
+	d.pending[key] = due
+	d.schedule(due)
+}
+
+// Cancels the pending key.
+func (d *Debouncer) Remove(key string) {
+	delete(d.pending, key)
+}
+
+// Returns the channel firing when some keys may be due. Nil channel is returned when nothing is pending.
+func (d *Debouncer) Due() <-chan time.Time {
+	if d.timer == nil {
+		return nil
+	}
+	return d.timer.C
+}
+
+// Returns the keys which are due, ordered by due time, and rearms the timer for the rest.
+func (d *Debouncer) TakeDue() []string {
+	d.timer = nil
+
+	now := time.Now()
+	var keys []string
+	var next time.Time
+	for key, due := range d.pending {
+		if !due.After(now) {
+			keys = append(keys, key)
+		} else if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		return d.pending[a].Compare(d.pending[b])
+	})
+	for _, key := range keys {
+		delete(d.pending, key)
+	}
+
+	if !next.IsZero() {
+		d.schedule(next)
+	}
+
+	return keys
+}
+
+// Arms the timer for the due time, unless it fires earlier already.
+func (d *Debouncer) schedule(due time.Time) {
+	if d.timer != nil {
+		return
+	}
+	d.timer = time.NewTimer(time.Until(due))
+}
+
+// Returns the pending keys, ordered by due time.
+func (d *Debouncer) Pending() []string {
+	keys := slices.Collect(maps.Keys(d.pending))
+	slices.SortFunc(keys, func(a, b string) int {
+		return d.pending[a].Compare(d.pending[b])
+	})
+	return keys
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// Number of keys waiting for a free worker before submission blocks.
+const dispatcherBacklog = 1024
+
+// Runs tasks on a pool of workers.
+// Tasks submitted with the same key are executed one at a time in order of submission,
+// while tasks of different keys are executed concurrently.
+// Tasks submitted by SubmitLocked are executed holding the configuration read lock, so the configuration is not reloaded
+// in the middle of a task. Tasks submitted by Submit take the lock themselves, e.g. not to hold it while throttled.
+type Dispatcher struct {
+	mu sync.Mutex
+	// Pending tasks by key. The key is present while it is queued or being processed by a worker.
+	queues map[string][]dispatcherTask
+	ready  chan string
+	wg     sync.WaitGroup
+}
+
+// Task queued with the context to run it with.
+type dispatcherTask struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+func newDispatcher(workers int) *Dispatcher {
+	d := &Dispatcher{
+		queues: make(map[string][]dispatcherTask),
+		ready:  make(chan string, dispatcherBacklog),
+	}
+
+	for range max(workers, 1) {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	return d
+}
+
+// Queues the task for execution after the tasks submitted earlier with the same key.
+// The task is called with the context, without holding the configuration lock.
+func (d *Dispatcher) Submit(ctx context.Context, key string, task func(ctx context.Context)) {
+	d.mu.Lock()
+	q, active := d.queues[key]
+	d.queues[key] = append(q, dispatcherTask{ctx: ctx, fn: task})
+	d.mu.Unlock()
+
+	if !active {
+		d.ready <- key
+	}
+}
+
+// Queues the task like Submit, to be executed holding the configuration read lock.
+func (d *Dispatcher) SubmitLocked(ctx context.Context, key string, task func(ctx context.Context)) {
+	d.Submit(ctx, key, func(ctx context.Context) {
+		configMu.RLock()
+		defer configMu.RUnlock()
+		task(ctx)
+	})
+}
+
+// Waits for the queued tasks to complete, and stops the workers.
+func (d *Dispatcher) Close() {
+	close(d.ready)
+	d.wg.Wait()
+}
+
+// Processes tasks of ready keys until the dispatcher is closed.
+func (d *Dispatcher) work() {
+	defer reportPanic()
+	defer d.wg.Done()
+
+	for key := range d.ready {
+		for {
+			d.mu.Lock()
+			q := d.queues[key]
+			if len(q) == 0 {
+				delete(d.queues, key)
+				d.mu.Unlock()
+				break
+			}
+			task := q[0]
+			d.queues[key] = q[1:]
+			d.mu.Unlock()
+
+			task.fn(task.ctx)
+		}
+	}
+}
+
+// Returns the number of tasks queued and not started yet.
+func (d *Dispatcher) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pending := 0
+	for _, q := range d.queues {
+		pending += len(q)
+	}
+	return pending
+}
+
+// Returns the number of keys with pending tasks, waiting for a free worker.
+func (d *Dispatcher) Queued() int {
+	return len(d.ready)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatcherSerializesKey(t *testing.T) {
+	d := newDispatcher(4)
+
+	var mu sync.Mutex
+	order := make(map[string][]int)
+	for i := range 100 {
+		for _, key := range []string{"a", "b", "c"} {
+			d.Submit(context.Background(), key, func(context.Context) {
+				mu.Lock()
+				defer mu.Unlock()
+				order[key] = append(order[key], i)
+			})
+		}
+	}
+	d.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		assert.Len(t, order[key], 100)
+		assert.IsIncreasing(t, order[key])
+	}
+}
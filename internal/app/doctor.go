@@ -0,0 +1,306 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/sys/unix"
+)
+
+// Directories with udev rules, in order of precedence.
+var udevRulesDirs = []string{"/etc/udev/rules.d", "/run/udev/rules.d", "/usr/lib/udev/rules.d", "/lib/udev/rules.d"}
+
+// Directories with systemd link files, in order of precedence.
+var systemdLinkDirs = []string{"/etc/systemd/network", "/run/systemd/network", "/usr/lib/systemd/network", "/lib/systemd/network"}
+
+// NetworkManager configuration files.
+var networkManagerConfigs = []string{"/etc/NetworkManager/NetworkManager.conf", "/etc/NetworkManager/conf.d/*.conf", "/usr/lib/NetworkManager/conf.d/*.conf"}
+
+type FindingLevel string
+
+const (
+	FindingOK   FindingLevel = "OK"
+	FindingWarn FindingLevel = "WARN"
+	FindingFail FindingLevel = "FAIL"
+)
+
+// Result of the diagnostic check.
+type Finding struct {
+	Level   FindingLevel `json:"level"`
+	Message string       `json:"message"`
+	// Suggested action to resolve the problem.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Diagnoses the environment the program runs in.
+type Doctor struct {
+	findings []Finding
+}
+
+func (d *Doctor) ok(format string, args ...any) {
+	d.findings = append(d.findings, Finding{Level: FindingOK, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *Doctor) warn(hint string, format string, args ...any) {
+	d.findings = append(d.findings, Finding{Level: FindingWarn, Message: fmt.Sprintf(format, args...), Hint: hint})
+}
+
+func (d *Doctor) fail(hint string, format string, args ...any) {
+	d.findings = append(d.findings, Finding{Level: FindingFail, Message: fmt.Sprintf(format, args...), Hint: hint})
+}
+
+// Checks the configuration file loaded on startup.
+func (d *Doctor) checkConfig(configErr error) {
+	if configErr != nil {
+		d.fail("Fix the configuration file, see docker-veth-namer(8)", "Configuration file is invalid: %s: %s", configFilePath, configErr)
+		return
+	}
+	if len(configFilePath) == 0 {
+		d.ok("Configuration file is not used, running with defaults")
+		return
+	}
+	d.ok("Configuration file is valid: %s", configFilePath)
+}
+
+// Checks the effective capabilities of the process.
+func (d *Doctor) checkCapabilities() {
+	capEff, err := effectiveCapabilities()
+	if err != nil {
+		d.warn("", "Cannot read process capabilities: %s", err)
+		return
+	}
+
+	for _, capability := range []struct {
+		bit  int
+		name string
+	}{
+		{unix.CAP_NET_ADMIN, "CAP_NET_ADMIN"},
+		{unix.CAP_SYS_ADMIN, "CAP_SYS_ADMIN"},
+	} {
+		if capEff&(1<<capability.bit) == 0 {
+			d.fail("Run the program as root, or grant the capability", "Capability is missing: %s", capability.name)
+		} else {
+			d.ok("Capability is present: %s", capability.name)
+		}
+	}
+}
+
+// Checks the Docker API connectivity and version, and access to the container network namespaces.
+func (d *Doctor) checkDocker(ctx context.Context, rt ContainerRuntime) {
+	pingCtx, cancel := withTimeout(ctx, config.DockerEventsConnectTimeout)
+	err := rt.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		d.fail("Check that Docker is running, and DOCKER_HOST points to it", "Docker API is not accessible: %s", err)
+		return
+	}
+
+	versionCtx, cancel := withTimeout(ctx, config.DockerInspectTimeout)
+	version, err := rt.Version(versionCtx)
+	cancel()
+	if err != nil {
+		d.warn("", "Cannot get Docker version: %s", err)
+	} else {
+		d.ok("Docker API is accessible: %s", version)
+	}
+
+	// Network namespaces are checked on any container having its own one.
+	for _, inspect := range inspectRunningContainers(ctx, rt, filters.NewArgs()) {
+		sandboxKey, err := rt.ResolveNetns(inspect)
+		if err != nil {
+			continue
+		}
+
+		if _, err := listContainerLinks(sandboxKey); err != nil {
+			d.fail("Run the program in the host PID and mount namespaces, with access to "+filepath.Dir(sandboxKey),
+				"Network namespace is not accessible: %s %s: %s", inspect.Name, sandboxKey, err)
+		} else {
+			d.ok("Network namespace is accessible: %s %s", inspect.Name, sandboxKey)
+		}
+		return
+	}
+
+	d.warn("Start a container attached to a bridge network, and run the check again",
+		"Network namespace access is not checked: no running containers with own network namespace")
+}
+
+// Checks for the udev rules and the systemd link files renaming veth links.
+func (d *Doctor) checkUdev() {
+	found := false
+	for _, dir := range udevRulesDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.rules"))
+		for _, file := range files {
+			if line := findVethRename(file, udevRenamesVeth); len(line) > 0 {
+				d.warn("Exclude veth links from the rule", "udev rule may rename veth links: %s: %s", file, line)
+				found = true
+			}
+		}
+	}
+
+	for _, dir := range systemdLinkDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.link"))
+		for _, file := range files {
+			if line := findVethRename(file, systemdLinkRenamesVeth); len(line) > 0 {
+				d.warn("Exclude veth links from the link file", "systemd link file may rename veth links: %s: %s", file, line)
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		d.ok("No udev rules or systemd link files renaming veth links found")
+	}
+}
+
+// Checks whether NetworkManager manages veth links.
+func (d *Doctor) checkNetworkManager() {
+	if _, err := os.Stat("/run/NetworkManager"); err != nil {
+		d.ok("NetworkManager is not running")
+		return
+	}
+
+	for _, pattern := range networkManagerConfigs {
+		files, _ := filepath.Glob(pattern)
+		for _, file := range files {
+			if line := findVethRename(file, networkManagerIgnoresVeth); len(line) > 0 {
+				d.ok("NetworkManager ignores veth links: %s: %s", file, line)
+				return
+			}
+		}
+	}
+
+	d.warn("Add \"unmanaged-devices=interface-name:veth*;driver:veth\" to the [keyfile] section of NetworkManager configuration",
+		"NetworkManager is running, and may manage veth links")
+}
+
+// Prints the findings. Returns error if any check failed.
+func (d *Doctor) report(w io.Writer) error {
+	err := printOutput(w, d.findings, func(w io.Writer) error {
+		for _, finding := range d.findings {
+			fmt.Fprintf(w, "[%s] %s\n", finding.Level, finding.Message)
+			if len(finding.Hint) > 0 {
+				fmt.Fprintf(w, "       %s\n", finding.Hint)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, finding := range d.findings {
+		if finding.Level == FindingFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
+
+// Runs all diagnostic checks, and prints the findings.
+func runDoctor(ctx context.Context, rt ContainerRuntime, configErr error) error {
+	d := &Doctor{}
+	d.checkConfig(configErr)
+	if configErr == nil {
+		d.checkRules(config)
+	}
+	d.checkCapabilities()
+	d.checkDocker(ctx, rt)
+	d.checkUdev()
+	d.checkNetworkManager()
+	return d.report(os.Stdout)
+}
+
+// Returns the effective capabilities of the process.
+func effectiveCapabilities() (uint64, error) {
+	statusFile, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, err
+	}
+	defer statusFile.Close()
+
+	return parseCapEff(statusFile)
+}
+
+// Parses the effective capabilities from the process status.
+func parseCapEff(r io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "CapEff:"); ok {
+			return strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("CapEff is not found")
+}
+
+// Returns whether the udev rule renames veth links.
+func udevRenamesVeth(line string) bool {
+	return strings.Contains(line, "NAME=") && strings.Contains(line, "veth")
+}
+
+// Returns whether the systemd link file line matches veth links; such files usually assign names.
+func systemdLinkRenamesVeth(line string) bool {
+	return (strings.HasPrefix(line, "Driver=") || strings.HasPrefix(line, "Kind=") || strings.HasPrefix(line, "OriginalName=")) &&
+		strings.Contains(line, "veth")
+}
+
+// Returns whether the NetworkManager configuration line excludes veth links.
+func networkManagerIgnoresVeth(line string) bool {
+	return strings.HasPrefix(line, "unmanaged-devices") && strings.Contains(line, "veth")
+}
+
+// Returns the first line of the file, ignoring comments, matching the predicate.
+func findVethRename(path string, match func(line string) bool) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if match(line) {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseCapEff(t *testing.T) {
+	capEff, err := parseCapEff(strings.NewReader("Name:\tdocker-veth-namer\nCapInh:\t0000000000000000\nCapEff:\t0000000000201000\n"))
+	assert.NoError(t, err)
+	assert.NotZero(t, capEff&(1<<unix.CAP_NET_ADMIN))
+	assert.NotZero(t, capEff&(1<<unix.CAP_SYS_ADMIN))
+
+	_, err = parseCapEff(strings.NewReader("Name:\tdocker-veth-namer\n"))
+	assert.Error(t, err)
+}
+
+func TestFindVethRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "70-veth.rules")
+	err := os.WriteFile(path, []byte(`# SUBSYSTEM=="net", KERNEL=="veth*", NAME="old"
+SUBSYSTEM=="net", ACTION=="add", KERNEL=="eth*", NAME="lan0"
+SUBSYSTEM=="net", ACTION=="add", KERNEL=="veth*", NAME="ct%n"
+`), 0o600)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `SUBSYSTEM=="net", ACTION=="add", KERNEL=="veth*", NAME="ct%n"`, findVethRename(path, udevRenamesVeth))
+	assert.Equal(t, "", findVethRename(path, networkManagerIgnoresVeth))
+	assert.Equal(t, "", findVethRename(filepath.Join(t.TempDir(), "missing"), udevRenamesVeth))
+
+	assert.True(t, systemdLinkRenamesVeth("Driver=veth"))
+	assert.False(t, systemdLinkRenamesVeth("Driver=e1000e"))
+	assert.True(t, networkManagerIgnoresVeth("unmanaged-devices=interface-name:veth*"))
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Snapshot of the internal state for debugging.
+type StateDump struct {
+	Time       time.Time          `json:"time"`
+	Containers []ContainerMapping `json:"containers"`
+	// Containers waiting for the trigger events to be coalesced.
+	PendingEvents []string `json:"pending_events"`
+	// Started containers waiting for the connect events.
+	PendingStarts []string `json:"pending_starts"`
+	// Tasks queued for the workers.
+	PendingTasks int `json:"pending_tasks"`
+	// Operations waiting for the next retry attempt.
+	PendingRetries  int64     `json:"pending_retries"`
+	LastEventTime   time.Time `json:"last_event_time"`
+	LastEventType   string    `json:"last_event_type"`
+	LastEventAction string    `json:"last_event_action"`
+}
+
+// Makes the snapshot of the tracked containers and the event loop.
+// Must be called from the loop goroutine.
+func (l *EventLoop) stateDump() StateDump {
+	dump := StateDump{
+		Time:            time.Now(),
+		PendingEvents:   l.processDebouncer.Pending(),
+		PendingStarts:   l.startDebouncer.Pending(),
+		PendingTasks:    l.dispatcher.Pending(),
+		PendingRetries:  pendingRetries.Load(),
+		LastEventTime:   l.lastEvent.Time,
+		LastEventType:   string(l.lastEvent.Type),
+		LastEventAction: string(l.lastEvent.Action),
+		Containers:      state.Mappings(),
+	}
+
+	return dump
+}
+
+// Writes the state snapshot in JSON to the configured file, or to the log.
+func (l *EventLoop) dumpState() {
+	dumpJson, err := json.MarshalIndent(l.stateDump(), "", "  ")
+	if err != nil {
+		log.Errorf("json.Marshal to bytes failed: %s", err)
+		return
+	}
+
+	if len(config.StateDumpFile) == 0 {
+		log.Infof("State dump: %s", dumpJson)
+		return
+	}
+
+	if err := os.WriteFile(config.StateDumpFile, append(dumpJson, '\n'), 0o600); err != nil {
+		log.Errorf("Cannot write state dump: %s: %s", config.StateDumpFile, err)
+		return
+	}
+
+	log.Infof("State dumped: %s", config.StateDumpFile)
+}
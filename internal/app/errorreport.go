@@ -0,0 +1,224 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.yaml.in/yaml/v3"
+)
+
+// Number of error reports queued before new ones are dropped.
+const errorReportQueueSize = 64
+
+// Error log record, or panic, reported to the error tracking service.
+type ErrorReport struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	// Fields of the log record, e.g. the container and link being processed.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Stack trace of the panic.
+	Stack   string `json:"stack,omitempty"`
+	Host    string `json:"host"`
+	Version string `json:"version"`
+	// Hash of the effective configuration, to group the reports of the hosts sharing it.
+	ConfigHash string `json:"config_hash"`
+}
+
+// Posts the error log records as JSON documents to the error tracking service.
+type ErrorReportHook struct {
+	url        string
+	client     *http.Client
+	queue      chan ErrorReport
+	host       string
+	configHash string
+	// Closed to stop the delivery. The queue is never closed, as the records may be fired concurrently with Close.
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Current error reporting hook. Nil when error reporting is disabled.
+var errorReportHook atomic.Pointer[ErrorReportHook]
+
+func newErrorReportHook(url string, configHash string) *ErrorReportHook {
+	host, _ := os.Hostname()
+	h := &ErrorReportHook{
+		url:        url,
+		client:     &http.Client{Timeout: webhookTimeout},
+		queue:      make(chan ErrorReport, errorReportQueueSize),
+		host:       host,
+		configHash: configHash,
+		done:       make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+func (h *ErrorReportHook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel}
+}
+
+// Queues the report of the record. Fatal records are reported before returning, as the program exits afterwards.
+// The hook does not log its own failures, as they would be reported recursively.
+func (h *ErrorReportHook) Fire(entry *log.Entry) error {
+	report := h.report(entry.Level.String(), entry.Message)
+	report.Time = entry.Time
+	if len(entry.Data) > 0 {
+		report.Fields = make(map[string]string, len(entry.Data))
+		for key, value := range maps.All(entry.Data) {
+			report.Fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	if entry.Level <= log.FatalLevel {
+		return h.send(report)
+	}
+
+	select {
+	case h.queue <- report:
+	default:
+	}
+	return nil
+}
+
+// Stops the delivery of the queued reports. The records fired afterwards are dropped. Nil hook is ignored.
+func (h *ErrorReportHook) Close() {
+	if h != nil {
+		h.closeOnce.Do(func() { close(h.done) })
+	}
+}
+
+// Returns the report with the host identification.
+func (h *ErrorReportHook) report(level string, message string) ErrorReport {
+	return ErrorReport{
+		Time:       time.Now(),
+		Level:      level,
+		Message:    message,
+		Host:       h.host,
+		Version:    AppVersion,
+		ConfigHash: h.configHash,
+	}
+}
+
+// Delivers queued reports in order of appearance.
+func (h *ErrorReportHook) run() {
+	for {
+		select {
+		case report := <-h.queue:
+			h.send(report)
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// Posts the report.
+func (h *ErrorReportHook) send(report ErrorReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error report request failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Returns the short hash of the configuration.
+func configHash(c Config) string {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Forwards the error log records to the current error reporting hook. Registered once, so the hook is swapped
+// without changing the hooks of the logger while the records are fired.
+type errorReportLogHook struct{}
+
+var addErrorReportLogHook sync.Once
+
+func (errorReportLogHook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel}
+}
+
+func (errorReportLogHook) Fire(entry *log.Entry) error {
+	if h := errorReportHook.Load(); h != nil {
+		return h.Fire(entry)
+	}
+	return nil
+}
+
+// Reports the errors to the URL from the configuration, replacing the existing hook unless the URL and
+// the configuration hash are unchanged. Empty URL disables the reporting.
+func applyErrorReport() {
+	prev := errorReportHook.Load()
+	hash := configHash(config)
+	if prev != nil && prev.url == config.ErrorReportURL && prev.configHash == hash {
+		return
+	}
+
+	var hook *ErrorReportHook
+	if len(config.ErrorReportURL) > 0 {
+		hook = newErrorReportHook(config.ErrorReportURL, hash)
+		addErrorReportLogHook.Do(func() { addLogHook(errorReportLogHook{}) })
+	}
+	errorReportHook.Store(hook)
+	prev.Close()
+}
+
+// Reports the panic of the calling goroutine with the stack trace, and panics again.
+// Deferred at the start of the long-running goroutines.
+func reportPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if h := errorReportHook.Load(); h != nil {
+		report := h.report(log.PanicLevel.String(), fmt.Sprint(r))
+		report.Stack = string(debug.Stack())
+		h.send(report)
+	}
+	panic(r)
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorReportHook(t *testing.T) {
+	reports := make(chan ErrorReport, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report ErrorReport
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports <- report
+	}))
+	defer server.Close()
+
+	hook := newErrorReportHook(server.URL, "0a1b2c3d4e5f")
+	defer hook.Close()
+
+	entry := linkLogger("0123", "/web", "eth0", 42, "veth1a2b3c4")
+	entry.Level = log.ErrorLevel
+	entry.Message = "netlink.LinkSetName failed: file exists"
+	require.NoError(t, hook.Fire(entry))
+
+	report := <-reports
+	assert.Equal(t, "error", report.Level)
+	assert.Equal(t, "netlink.LinkSetName failed: file exists", report.Message)
+	assert.Equal(t, "0a1b2c3d4e5f", report.ConfigHash)
+	assert.Equal(t, "/web", report.Fields[logFieldContainerName])
+	assert.Equal(t, "eth0", report.Fields[logFieldContainerLink])
+	assert.Equal(t, "42", report.Fields[logFieldIndex])
+}
+
+func TestApplyErrorReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	prevConfig := config
+	defer func() {
+		config = prevConfig
+		applyErrorReport()
+	}()
+	config = defaultConfig()
+	config.ErrorReportURL = server.URL
+	applyErrorReport()
+	hook := errorReportHook.Load()
+	require.NotNil(t, hook)
+
+	applyErrorReport()
+	assert.Same(t, hook, errorReportHook.Load())
+
+	// The records fired while the hook is replaced are not sent to the closed hook.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			errorReportLogHook{}.Fire(&log.Entry{Level: log.ErrorLevel, Message: "error"})
+		}
+	}()
+	for i := range 100 {
+		config.HookTimeout = time.Duration(i+1) * time.Second
+		applyErrorReport()
+	}
+	wg.Wait()
+	assert.NotSame(t, hook, errorReportHook.Load())
+
+	config.ErrorReportURL = ""
+	applyErrorReport()
+	assert.Nil(t, errorReportHook.Load())
+}
+
+func TestConfigHash(t *testing.T) {
+	c := defaultConfig()
+	hash := configHash(c)
+	assert.Len(t, hash, 12)
+	assert.Equal(t, hash, configHash(defaultConfig()))
+
+	c.LinkNamePrefix = "x"
+	assert.NotEqual(t, hash, configHash(c))
+}
+
+func TestErrorReportURLValidation(t *testing.T) {
+	c := defaultConfig()
+	c.ErrorReportURL = "https://errors.example.com/report"
+	assert.NoError(t, validateConfig(c))
+
+	c.ErrorReportURL = "errors.example.com"
+	assert.ErrorContains(t, validateConfig(c), "error_report_url")
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/docker/docker/api/types/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// Policies of the full event queue.
+const (
+	// Reading the Docker events stream waits until there is room in the queue.
+	EventQueuePolicyDefer = "defer"
+	// The event is dropped, and the running containers are processed again once the queue has room.
+	EventQueuePolicyDrop = "drop"
+)
+
+// Bounded queue of the Docker events between the events stream and the event loop.
+// The stream is read by a goroutine, so it is drained while the loop is busy, e.g. reloading the configuration.
+// The queue never backs up silently: waiting for room and dropping the events are counted and logged.
+type EventQueue struct {
+	events chan events.Message
+	// Signaled when events are dropped.
+	dropped chan struct{}
+	drop    atomic.Bool
+	// Number of the events dropped, and the number of the stream reads deferred, since the start.
+	droppedCount  atomic.Uint64
+	deferredCount atomic.Uint64
+}
+
+func newEventQueue(size int, policy string) *EventQueue {
+	q := &EventQueue{
+		events:  make(chan events.Message, max(size, 1)),
+		dropped: make(chan struct{}, 1),
+	}
+	q.SetPolicy(policy)
+	return q
+}
+
+// Sets the policy applied when the queue is full.
+func (q *EventQueue) SetPolicy(policy string) {
+	q.drop.Store(policy == EventQueuePolicyDrop)
+}
+
+// Moves the events of the stream into the queue until the context is done.
+func (q *EventQueue) Forward(ctx context.Context, stream <-chan events.Message) {
+	defer reportPanic()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			q.push(ctx, event)
+		}
+	}
+}
+
+// Queues the event, applying the policy when the queue is full.
+func (q *EventQueue) push(ctx context.Context, event events.Message) {
+	select {
+	case q.events <- event:
+		return
+	default:
+	}
+
+	if q.drop.Load() {
+		q.droppedCount.Add(1)
+		warnfLimited(log.NewEntry(log.StandardLogger()), "Event queue is full, dropping Docker events")
+		select {
+		case q.dropped <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	q.deferredCount.Add(1)
+	warnfLimited(log.NewEntry(log.StandardLogger()), "Event queue is full, reading Docker events is deferred")
+	select {
+	case q.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// Returns the channel of the queued events.
+func (q *EventQueue) Events() <-chan events.Message {
+	return q.events
+}
+
+// Returns the channel signaled when events are dropped.
+func (q *EventQueue) Dropped() <-chan struct{} {
+	return q.dropped
+}
+
+// Returns the number of the queued events.
+func (q *EventQueue) Depth() int {
+	return len(q.events)
+}
+
+// Returns the maximum number of the queued events.
+func (q *EventQueue) Capacity() int {
+	return cap(q.events)
+}
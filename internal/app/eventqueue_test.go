@@ -0,0 +1,64 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventQueueDrop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := newEventQueue(1, EventQueuePolicyDrop)
+	q.push(ctx, events.Message{Actor: events.Actor{ID: "1"}})
+	q.push(ctx, events.Message{Actor: events.Actor{ID: "2"}})
+
+	assert.Equal(t, 1, q.Depth())
+	assert.Equal(t, 1, q.Capacity())
+	assert.Equal(t, uint64(1), q.droppedCount.Load())
+	require.Len(t, q.Dropped(), 1)
+	assert.Equal(t, "1", (<-q.Events()).Actor.ID)
+}
+
+func TestEventQueueDefer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := make(chan events.Message)
+	q := newEventQueue(1, EventQueuePolicyDefer)
+	go q.Forward(ctx, stream)
+
+	stream <- events.Message{Actor: events.Actor{ID: "1"}}
+	stream <- events.Message{Actor: events.Actor{ID: "2"}}
+	require.Eventually(t, func() bool { return q.deferredCount.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The deferred event is queued once there is room, nothing is lost.
+	assert.Equal(t, "1", (<-q.Events()).Actor.ID)
+	assert.Equal(t, "2", (<-q.Events()).Actor.ID)
+	assert.Zero(t, q.droppedCount.Load())
+	assert.Empty(t, q.Dropped())
+}
@@ -0,0 +1,1058 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	log "github.com/sirupsen/logrus"
+)
+
+// Delay after the configuration file change before reloading, to let the writer complete.
+const configReloadDelay = 500 * time.Millisecond
+
+const (
+	// Initial delay before resubscribing to Docker events after the stream failure.
+	reconnectInitialDelay = time.Second
+	// Maximum delay before resubscribing to Docker events after the stream failure.
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// Interval of writing the state file, when changed.
+const stateSaveInterval = 10 * time.Second
+
+// Delay after the container start event, after which the container is processed
+// unless a network connect event was received for it.
+const startFallbackDelay = 2 * time.Second
+
+// Receives Docker events and host link events, and dispatches the container processing to the workers.
+// Fields are accessed from the loop goroutine only, except the dispatcher.
+type EventLoop struct {
+	ctx        context.Context
+	runtime    ContainerRuntime
+	dispatcher *Dispatcher
+	// Events triggering the container processing.
+	triggers map[dockerwatch.Trigger]bool
+	// Coalesces repeated trigger events of the same container.
+	processDebouncer *Debouncer
+	// Delays processing of started containers, waiting for the connect events.
+	startDebouncer *Debouncer
+	// Time of the network connect event received since start, by the container ID.
+	connectSeen map[string]time.Time
+	// Networks the containers connected to since their processing was scheduled, by the container ID.
+	connectNetworks map[string][]string
+	linkWatcher     *LinkWatcher
+	// Receives links which were unknown before the resync, once the resync is completed.
+	resyncDone chan map[int]string
+	// Watches the configuration file for automatic reload.
+	configWatcher *ConfigWatcher
+	// Checks the remote configuration for changes, instead of watching the file.
+	configRefreshTicker *time.Ticker
+	// Receives whether the remote configuration changed.
+	configRefreshResult chan bool
+	configRefreshing    bool
+	// Coalesces the configuration file changes.
+	configDebouncer *Debouncer
+	// Last received Docker event.
+	lastEvent LastEvent
+	// Docker events read from the subscription, awaiting the processing.
+	eventQueue *EventQueue
+
+	// Docker events subscription.
+	filterArgs   filters.Args
+	errs         <-chan error
+	streamCancel context.CancelFunc
+	// Time of the last subscription.
+	subscribedAt time.Time
+	// Fires when the subscription is due to be reestablished after failure.
+	reconnectTimer *time.Timer
+	reconnectDelay time.Duration
+
+	// Time of the last event and the state version written to the state file.
+	savedEventTime    time.Time
+	savedStateVersion uint64
+	// Path and state version of the last written file_sd file.
+	savedFileSDPath    string
+	savedFileSDVersion uint64
+	// Path and state version of the last written mapping file.
+	savedMappingPath    string
+	savedMappingVersion uint64
+	// Publish the link mappings to the external systems.
+	mappingPublishers []MappingPublisher
+	// State version of the last mappings handed to the publishers.
+	publishedVersion uint64
+	// Pushes the inventory to the central collector, nil when disabled.
+	collector *CollectorPusher
+
+	// Docker API liveness check.
+	pingTicker *time.Ticker
+	pingResult chan error
+	pinging    bool
+	// Number of consecutive ping failures.
+	pingFailures int
+
+	// Whether renaming is paused by the maintenance mode.
+	paused bool
+
+	// Periodic activity summary.
+	heartbeatTicker *time.Ticker
+	lastHeartbeat   Heartbeat
+	// Number of Docker events received since the start.
+	eventCount uint64
+
+	startTime time.Time
+	// Receives the status requests from the control socket.
+	statusRequests chan chan Status
+	// Receives the resync requests from the control socket.
+	resyncRequests chan struct{}
+	// Receives the container revert requests from the control socket.
+	revertRequests chan RevertRequest
+	// Receives the configuration reload requests from the control socket.
+	reloadRequests chan chan error
+	// Background goroutines submitting to the dispatcher.
+	wg sync.WaitGroup
+}
+
+type LastEvent struct {
+	Time   time.Time
+	Type   events.Type
+	Action events.Action
+}
+
+// Iterates over running containers updating the corresponding host link names,
+// and starts listening to Docker events until the context is canceled.
+// The client is closed on return.
+func listenToDockerEvents(ctx context.Context, rt ContainerRuntime) {
+	triggers := dockerwatch.ParseTriggers(config.EventTriggers)
+
+	// Disconnect and exit events are always processed to keep the mappings consistent.
+	// Rename events drop the last inspect result of the container, see connectedInspect.
+	filterArgs := filters.NewArgs(
+		filters.Arg("type", string(events.NetworkEventType)),
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("event", string(events.ActionDisconnect)),
+		filters.Arg("event", string(events.ActionDie)),
+		filters.Arg("event", string(events.ActionDestroy)),
+		filters.Arg("event", string(events.ActionRename)),
+	)
+	for trigger := range triggers {
+		filterArgs.Add("event", string(trigger.Action))
+	}
+
+	l := &EventLoop{
+		ctx:                 ctx,
+		runtime:             rt,
+		filterArgs:          filterArgs,
+		reconnectDelay:      reconnectInitialDelay,
+		eventQueue:          newEventQueue(config.EventQueueSize, config.EventQueuePolicy),
+		dispatcher:          newDispatcher(config.EventWorkers),
+		triggers:            triggers,
+		processDebouncer:    newDebouncer(config.EventDebounce),
+		startDebouncer:      newDebouncer(startFallbackDelay),
+		connectSeen:         make(map[string]time.Time),
+		connectNetworks:     make(map[string][]string),
+		configDebouncer:     newDebouncer(configReloadDelay),
+		resyncDone:          make(chan map[int]string),
+		pingResult:          make(chan error),
+		configRefreshResult: make(chan bool),
+		startTime:           time.Now(),
+		statusRequests:      make(chan chan Status),
+		resyncRequests:      make(chan struct{}),
+		revertRequests:      make(chan RevertRequest),
+		reloadRequests:      make(chan chan error),
+	}
+	// The runtime client may be replaced on recovery.
+	defer func() { l.runtime.Close() }()
+	defer func() {
+		l.wg.Wait()
+		l.dispatcher.Close()
+	}()
+
+	// Replay the events missed while the program was not running.
+	var since time.Time
+	if len(config.StateFile) > 0 {
+		ps, err := loadPersistentState(config.StateFile)
+		if err != nil {
+			log.Errorf("Cannot load state file: %s: %s", config.StateFile, err)
+		}
+
+		// Adopt the links renamed by the previous run, preserving their original names.
+		state.Restore(ps.Containers)
+
+		if !ps.LastEventTime.IsZero() {
+			since = ps.LastEventTime
+			if windowStart := time.Now().Add(-config.EventReplayWindow); since.Before(windowStart) {
+				since = windowStart
+			}
+			log.Infof("Replaying Docker events since %s", since.Format(time.RFC3339Nano))
+		}
+	}
+
+	l.subscribe(since)
+	defer l.saveState()
+	defer l.saveFileSD()
+	defer l.saveMappingFile()
+
+	l.setupMappingPublishers()
+	defer l.closeMappingPublishers()
+
+	l.setupCollector()
+	defer l.closeCollector()
+
+	l.setPingInterval(config.DockerPingInterval)
+	defer l.setPingInterval(0)
+
+	l.lastHeartbeat = Heartbeat{time: time.Now()}
+	l.setHeartbeatInterval(config.HeartbeatInterval)
+	defer l.setHeartbeatInterval(0)
+
+	saveTicker := time.NewTicker(stateSaveInterval)
+	defer saveTicker.Stop()
+
+	l.paused = isPaused()
+	if l.paused {
+		log.Warn("Renaming is paused")
+	}
+
+	pauseTicker := time.NewTicker(pauseCheckInterval)
+	defer pauseTicker.Stop()
+
+	logLimiterTicker := time.NewTicker(logLimiterFlushInterval)
+	defer logLimiterTicker.Stop()
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+
+	defer closeTimeline()
+
+	if config.WatchLinkEvents {
+		var err error
+		l.linkWatcher, err = newLinkWatcher()
+		if err != nil {
+			log.Errorf("netlink.LinkSubscribe failed, link events are not watched: %s", err)
+		}
+	}
+	defer func() { l.linkWatcher.Close() }()
+
+	if config.AutoReload {
+		l.startConfigWatcher()
+	}
+	defer l.stopConfigWatcher()
+
+	if len(config.ControlSocket) > 0 {
+		controlServer, err := newControlServer(config.ControlSocket, l)
+		if err != nil {
+			log.Errorf("Cannot listen on control socket: %s: %s", config.ControlSocket, err)
+		}
+		defer controlServer.Close()
+	}
+
+	if len(config.MetricsAddress) > 0 {
+		metricsServer, err := newMetricsServer(config.MetricsAddress, l)
+		if err != nil {
+			log.Errorf("Cannot listen on metrics address: %s: %s", config.MetricsAddress, err)
+		}
+		defer metricsServer.Close()
+	}
+
+	if len(apiListenAddress) > 0 {
+		apiServer, err := newAPIServer(apiListenAddress, l)
+		if err != nil {
+			log.Errorf("Cannot listen on API address: %s: %s", apiListenAddress, err)
+		}
+		defer apiServer.Close()
+	}
+
+	if len(grpcListenAddress) > 0 {
+		grpcServer, err := newGRPCServer(grpcListenAddress, l)
+		if err != nil {
+			log.Errorf("Cannot listen on gRPC address: %s: %s", grpcListenAddress, err)
+		}
+		defer grpcServer.Close()
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	sigusr1 := make(chan os.Signal, 1)
+	signal.Notify(sigusr1, syscall.SIGUSR1)
+	defer signal.Stop(sigusr1)
+
+	// Process currently running containers after events channel is created, to avoid race during system startup.
+	processRunningContainers(ctx, rt, filters.NewArgs(), nil)
+	dropStaleLinks()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case err := <-l.errs:
+			if ctx.Err() != nil {
+				return
+			}
+
+			// The stream is broken, for example on Docker restart. Resubscribe later.
+			l.scheduleReconnect(err)
+
+		case <-l.reconnectDue():
+			l.reconnectTimer = nil
+
+			// Replay the events happened during the gap.
+			since := l.replaySince()
+			log.Infof("Reconnecting to Docker events stream since %s", since.Format(time.RFC3339Nano))
+			l.subscribe(since)
+
+		case event := <-l.eventQueue.Events():
+			l.reconnectDelay = reconnectInitialDelay
+			l.handleEvent(event)
+
+		case <-l.eventQueue.Dropped():
+			log.Warn("Docker events were dropped, processing running containers")
+			l.resync(nil)
+
+		case <-l.processDebouncer.Due():
+			for _, containerID := range l.processDebouncer.TakeDue() {
+				l.submitProcessContainer(containerID)
+			}
+
+		case <-l.startDebouncer.Due():
+			for _, containerID := range l.startDebouncer.TakeDue() {
+				if _, ok := l.connectSeen[containerID]; ok {
+					continue
+				}
+
+				log.Debugf("No network connect event received for started container, processing: %s", containerID)
+				l.submitProcessContainer(containerID)
+			}
+
+		case update, ok := <-l.linkWatcher.Updates():
+			if !ok {
+				log.Error("Link events subscription is closed")
+				l.linkWatcher = nil
+				continue
+			}
+			l.linkWatcher.HandleUpdate(update)
+
+		case <-l.linkWatcher.ResyncDue():
+			unknown := l.linkWatcher.TakePending()
+			if len(unknown) == 0 {
+				continue
+			}
+
+			log.Infof("Unknown veth links detected, processing running containers: %v", slices.Sorted(maps.Values(unknown)))
+			l.resync(unknown)
+
+		case unknown := <-l.resyncDone:
+			if l.linkWatcher != nil {
+				l.linkWatcher.ResyncDone(unknown)
+			}
+
+		case <-sighup:
+			log.Info("SIGHUP received, reloading configuration")
+			l.reloadConfig()
+
+		case <-sigusr1:
+			l.dumpState()
+
+		case reply := <-l.statusRequests:
+			reply <- l.status()
+
+		case <-l.resyncRequests:
+			log.Info("Resync requested, processing running containers")
+			l.resync(nil)
+
+		case req := <-l.revertRequests:
+			req.Reply <- l.revertContainer(req.Container)
+
+		case reply := <-l.reloadRequests:
+			log.Info("Reload requested, reloading configuration")
+			reply <- l.reloadConfig()
+
+		case <-saveTicker.C:
+			l.saveState()
+			l.saveFileSD()
+			l.saveMappingFile()
+			l.publishMappings()
+
+		case <-pauseTicker.C:
+			l.checkPaused()
+
+		case <-logLimiterTicker.C:
+			flushLimitedLogs()
+
+		case <-pruneTicker.C:
+			l.prune()
+
+		case <-l.pingDue():
+			l.ping()
+
+		case <-l.heartbeatDue():
+			l.heartbeat()
+
+		case err := <-l.pingResult:
+			l.pinging = false
+			l.handlePingResult(err)
+
+		case event, ok := <-l.configWatcher.Events():
+			if !ok {
+				log.Error("Configuration file watch is closed")
+				l.configWatcher = nil
+				continue
+			}
+			if l.configWatcher.IsConfigChanged(event) {
+				l.configDebouncer.Add(configFilePath)
+			}
+
+		case err := <-l.configWatcher.Errors():
+			log.Errorf("Configuration file watch failed: %s", err)
+
+		case <-l.configRefreshDue():
+			l.refreshConfig()
+
+		case changed := <-l.configRefreshResult:
+			l.configRefreshing = false
+			if changed {
+				log.Info("Remote configuration changed, reloading configuration")
+				l.reloadConfig()
+			}
+
+		case <-l.configDebouncer.Due():
+			l.configDebouncer.TakeDue()
+			log.Info("Configuration file changed, reloading configuration")
+			l.reloadConfig()
+		}
+	}
+}
+
+// Subscribes to Docker events. The past events since the specified time are replayed, unless the time is zero.
+// The Docker API is pinged first, to not hang on connection to the wedged daemon.
+func (l *EventLoop) subscribe(since time.Time) {
+	l.subscribedAt = time.Now()
+
+	streamCtx, streamCancel := context.WithCancel(l.ctx)
+	l.streamCancel = streamCancel
+
+	pingCtx, cancel := withTimeout(l.ctx, config.DockerEventsConnectTimeout)
+	err := l.runtime.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		if l.ctx.Err() == nil {
+			l.scheduleReconnect(err)
+		}
+		return
+	}
+
+	var stream <-chan events.Message
+	stream, l.errs = l.runtime.WatchEvents(streamCtx, l.filterArgs, since)
+	go l.eventQueue.Forward(streamCtx, stream)
+}
+
+// Drops the failed subscription, and schedules the next attempt with increasing delay.
+func (l *EventLoop) scheduleReconnect(err error) {
+	log.Errorf("Docker events stream failed, reconnecting in %s: %s", l.reconnectDelay, err)
+	recordAlertEvent(AlertEventDockerReconnects)
+	l.streamCancel()
+	l.errs = nil
+	l.reconnectTimer = time.NewTimer(l.reconnectDelay)
+	l.reconnectDelay = min(l.reconnectDelay*2, reconnectMaxDelay)
+}
+
+// Detects toggling of the maintenance mode. Running containers are processed on resume,
+// to apply the renames skipped while paused.
+func (l *EventLoop) checkPaused() {
+	paused := isPaused()
+	if paused == l.paused {
+		return
+	}
+	l.paused = paused
+
+	if paused {
+		log.Warn("Renaming is paused")
+		return
+	}
+
+	log.Info("Renaming is resumed, processing running containers")
+	l.resync(nil)
+}
+
+// Returns the time since which the events are to be replayed upon resubscription.
+func (l *EventLoop) replaySince() time.Time {
+	since := l.subscribedAt
+	if l.lastEvent.Time.After(since) {
+		since = l.lastEvent.Time
+	}
+	return since
+}
+
+// Starts or stops the periodic Docker API liveness check. Zero interval stops the check.
+func (l *EventLoop) setPingInterval(interval time.Duration) {
+	if l.pingTicker != nil {
+		l.pingTicker.Stop()
+		l.pingTicker = nil
+	}
+
+	if interval > 0 {
+		l.pingTicker = time.NewTicker(interval)
+	}
+}
+
+// Returns the channel firing when the liveness check is due. Nil channel is returned when the check is disabled.
+func (l *EventLoop) pingDue() <-chan time.Time {
+	if l.pingTicker == nil {
+		return nil
+	}
+	return l.pingTicker.C
+}
+
+// Pings the Docker API in background. The result is sent back to the loop.
+func (l *EventLoop) ping() {
+	if l.pinging {
+		return
+	}
+	l.pinging = true
+
+	ctx, rt := l.ctx, l.runtime
+	timeout := config.DockerEventsConnectTimeout
+	l.wg.Go(func() {
+		pingCtx, cancel := withTimeout(ctx, timeout)
+		err := rt.Ping(pingCtx)
+		cancel()
+
+		select {
+		case l.pingResult <- err:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// Counts consecutive ping failures, and recovers the Docker connection once there are too many.
+// The connection may be silently dead, producing neither events nor errors.
+func (l *EventLoop) handlePingResult(err error) {
+	if err == nil {
+		l.pingFailures = 0
+		return
+	}
+
+	l.pingFailures++
+	log.Warnf("Docker API ping failed (%d of %d): %s", l.pingFailures, config.DockerPingFailures, err)
+
+	if l.pingFailures >= config.DockerPingFailures {
+		l.pingFailures = 0
+		l.recoverConnection()
+	}
+}
+
+// Rebuilds the Docker client and the events stream, and processes running containers.
+func (l *EventLoop) recoverConnection() {
+	log.Error("Docker API is not responding, reconnecting")
+	recordAlertEvent(AlertEventDockerReconnects)
+
+	rt, err := newContainerRuntime()
+	if err != nil {
+		log.Errorf("Failed to connect to Docker API: %s", err)
+		return
+	}
+
+	if l.streamCancel != nil {
+		l.streamCancel()
+	}
+	if l.reconnectTimer != nil {
+		l.reconnectTimer.Stop()
+		l.reconnectTimer = nil
+	}
+
+	// The tasks in progress may still use the old client, and fail.
+	l.runtime.Close()
+	l.runtime = rt
+
+	l.reconnectDelay = reconnectInitialDelay
+	l.subscribe(l.replaySince())
+	l.resync(nil)
+}
+
+// Writes the time of the last processed event and the link mappings to the state file, when changed.
+func (l *EventLoop) saveState() {
+	stateVersion := state.Version()
+	if len(config.StateFile) == 0 || (l.lastEvent.Time.Equal(l.savedEventTime) && stateVersion == l.savedStateVersion) {
+		return
+	}
+
+	ps := PersistentState{
+		LastEventTime: l.lastEvent.Time,
+		Containers:    state.Mappings(),
+	}
+	if err := savePersistentState(config.StateFile, ps); err != nil {
+		log.Errorf("Cannot save state file: %s: %s", config.StateFile, err)
+		return
+	}
+
+	l.savedEventTime = l.lastEvent.Time
+	l.savedStateVersion = stateVersion
+}
+
+// Writes the link mappings to the file_sd file, when changed.
+func (l *EventLoop) saveFileSD() {
+	stateVersion := state.Version()
+	if len(config.FileSDFile) == 0 || (config.FileSDFile == l.savedFileSDPath && stateVersion == l.savedFileSDVersion) {
+		return
+	}
+
+	if err := saveFileSD(config.FileSDFile, state.Mappings()); err != nil {
+		log.Errorf("Cannot save file_sd file: %s: %s", config.FileSDFile, err)
+		return
+	}
+
+	l.savedFileSDPath = config.FileSDFile
+	l.savedFileSDVersion = stateVersion
+}
+
+// Writes the link mappings to the mapping file, when changed.
+func (l *EventLoop) saveMappingFile() {
+	stateVersion := state.Version()
+	if len(config.MappingFile) == 0 || (config.MappingFile == l.savedMappingPath && stateVersion == l.savedMappingVersion) {
+		return
+	}
+
+	if err := saveMappingFile(config.MappingFile, state.Mappings()); err != nil {
+		log.Errorf("Cannot save mapping file: %s: %s", config.MappingFile, err)
+		return
+	}
+
+	l.savedMappingPath = config.MappingFile
+	l.savedMappingVersion = stateVersion
+}
+
+// Starts publishing the link mappings to the external systems, as configured.
+func (l *EventLoop) setupMappingPublishers() {
+	if len(config.KVStore) > 0 {
+		publisher, err := newKVPublisher(config.KVStore, config.KVPrefix, config.KVTTL)
+		if err != nil {
+			log.Errorf("Cannot publish mappings to key-value store: %s", err)
+		} else {
+			l.mappingPublishers = append(l.mappingPublishers, publisher)
+		}
+	}
+
+	if len(config.NtopngURL) > 0 {
+		publisher, err := newNtopngPublisher(config.NtopngURL, config.NtopngToken)
+		if err != nil {
+			log.Errorf("Cannot publish interface aliases to ntopng: %s", err)
+		} else {
+			l.mappingPublishers = append(l.mappingPublishers, publisher)
+		}
+	}
+
+	if len(config.KubernetesPublish) > 0 {
+		publisher, err := newKubernetesPublisher(config.KubernetesPublish, config.KubernetesNamespace,
+			config.KubernetesNodeName, config.KubernetesPublishInterval)
+		if err != nil {
+			log.Errorf("Cannot publish mappings to Kubernetes: %s", err)
+		} else {
+			l.mappingPublishers = append(l.mappingPublishers, publisher)
+		}
+	}
+
+	l.publishedVersion = 0
+	l.publishMappings()
+}
+
+// Stops publishing the link mappings to the external systems.
+func (l *EventLoop) closeMappingPublishers() {
+	for _, publisher := range l.mappingPublishers {
+		publisher.Close()
+	}
+	l.mappingPublishers = nil
+}
+
+// Hands the link mappings to the publishers, when changed.
+func (l *EventLoop) publishMappings() {
+	stateVersion := state.Version()
+	if len(l.mappingPublishers) == 0 || stateVersion == l.publishedVersion {
+		return
+	}
+
+	mappings := state.Mappings()
+	for _, publisher := range l.mappingPublishers {
+		publisher.Publish(mappings)
+	}
+	l.publishedVersion = stateVersion
+}
+
+// Starts pushing the inventory to the central collector, when configured.
+func (l *EventLoop) setupCollector() {
+	if len(config.CollectorURL) > 0 {
+		l.collector = newCollectorPusher(config.CollectorURL, config.CollectorToken, config.CollectorInterval, l)
+	}
+}
+
+// Stops pushing the inventory to the central collector.
+func (l *EventLoop) closeCollector() {
+	if l.collector != nil {
+		l.collector.Close()
+		l.collector = nil
+	}
+}
+
+// Returns the channel firing when the subscription is due to be reestablished.
+// Nil channel is returned when the subscription is active.
+func (l *EventLoop) reconnectDue() <-chan time.Time {
+	if l.reconnectTimer == nil {
+		return nil
+	}
+	return l.reconnectTimer.C
+}
+
+// Starts watching the configuration file for changes, or checking the remote configuration periodically.
+func (l *EventLoop) startConfigWatcher() {
+	if len(configFilePath) == 0 {
+		return
+	}
+
+	if isRemoteConfig(configFilePath) {
+		l.configRefreshTicker = time.NewTicker(config.ConfigRefreshInterval)
+		return
+	}
+
+	var err error
+	l.configWatcher, err = newConfigWatcher(configFilePath)
+	if err != nil {
+		log.Errorf("Cannot watch configuration file, automatic reload is disabled: %s: %s", configFilePath, err)
+	}
+}
+
+// Stops watching the configuration for changes.
+func (l *EventLoop) stopConfigWatcher() {
+	l.configWatcher.Close()
+	l.configWatcher = nil
+	if l.configRefreshTicker != nil {
+		l.configRefreshTicker.Stop()
+		l.configRefreshTicker = nil
+	}
+}
+
+// Returns the channel firing when the remote configuration is due to be checked.
+// Nil channel is returned when the configuration is not remote, or is not reloaded automatically.
+func (l *EventLoop) configRefreshDue() <-chan time.Time {
+	if l.configRefreshTicker == nil {
+		return nil
+	}
+	return l.configRefreshTicker.C
+}
+
+// Checks the remote configuration for changes in background. The result is sent back to the loop.
+func (l *EventLoop) refreshConfig() {
+	if l.configRefreshing {
+		return
+	}
+	l.configRefreshing = true
+
+	ctx, path := l.ctx, configFilePath
+	l.wg.Go(func() {
+		_, changed, err := loadRemoteConfig(path)
+		if err != nil {
+			log.Errorf("Cannot fetch configuration: %s: %s", path, err)
+		}
+
+		select {
+		case l.configRefreshResult <- changed:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// Reloads the configuration, applies it to the event loop, and processes running containers according to it.
+// Invalid configuration is rejected, and the current one is kept.
+func (l *EventLoop) reloadConfig() error {
+	prev, err := reloadConfig()
+	if err != nil {
+		log.Errorf("Configuration is not reloaded: %s", err)
+		return err
+	}
+
+	l.processDebouncer.window = config.EventDebounce
+	l.eventQueue.SetPolicy(config.EventQueuePolicy)
+
+	if config.EventWorkers != prev.EventWorkers {
+		log.Warnf("Changing event_workers requires restart: %d => %d", prev.EventWorkers, config.EventWorkers)
+	}
+
+	if config.EventQueueSize != prev.EventQueueSize {
+		log.Warnf("Changing event_queue_size requires restart: %d => %d", prev.EventQueueSize, config.EventQueueSize)
+	}
+
+	if config.ControlSocket != prev.ControlSocket {
+		log.Warnf("Changing control_socket requires restart: %s => %s", prev.ControlSocket, config.ControlSocket)
+	}
+
+	if config.MetricsAddress != prev.MetricsAddress {
+		log.Warnf("Changing metrics_address requires restart: %s => %s", prev.MetricsAddress, config.MetricsAddress)
+	}
+
+	if config.TLSCertFile != prev.TLSCertFile || config.TLSKeyFile != prev.TLSKeyFile || config.TLSClientCAFile != prev.TLSClientCAFile {
+		log.Warn("Changing tls_cert_file, tls_key_file, or tls_client_ca_file requires restart")
+	}
+
+	if config.HeartbeatInterval != prev.HeartbeatInterval {
+		l.setHeartbeatInterval(config.HeartbeatInterval)
+	}
+
+	if config.DockerPingInterval != prev.DockerPingInterval {
+		l.setPingInterval(config.DockerPingInterval)
+	}
+
+	if !maps.Equal(dockerwatch.ParseTriggers(config.EventTriggers), l.triggers) {
+		log.Warnf("Changing event_triggers requires restart: %v => %v", prev.EventTriggers, config.EventTriggers)
+	}
+
+	if config.WatchLinkEvents && l.linkWatcher == nil {
+		l.linkWatcher, err = newLinkWatcher()
+		if err != nil {
+			log.Errorf("netlink.LinkSubscribe failed, link events are not watched: %s", err)
+		}
+	} else if !config.WatchLinkEvents && l.linkWatcher != nil {
+		l.linkWatcher.Close()
+		l.linkWatcher = nil
+	}
+
+	if config.KVStore != prev.KVStore || config.KVPrefix != prev.KVPrefix || config.KVTTL != prev.KVTTL ||
+		config.NtopngURL != prev.NtopngURL || config.NtopngToken != prev.NtopngToken ||
+		config.KubernetesPublish != prev.KubernetesPublish || config.KubernetesNamespace != prev.KubernetesNamespace ||
+		config.KubernetesNodeName != prev.KubernetesNodeName || config.KubernetesPublishInterval != prev.KubernetesPublishInterval {
+		l.closeMappingPublishers()
+		l.setupMappingPublishers()
+	}
+
+	if config.CollectorURL != prev.CollectorURL || config.CollectorToken != prev.CollectorToken || config.CollectorInterval != prev.CollectorInterval {
+		l.closeCollector()
+		l.setupCollector()
+	}
+
+	watching := l.configWatcher != nil || l.configRefreshTicker != nil
+	if config.AutoReload && !watching {
+		l.startConfigWatcher()
+	} else if !config.AutoReload && watching {
+		l.stopConfigWatcher()
+	} else if l.configRefreshTicker != nil && config.ConfigRefreshInterval != prev.ConfigRefreshInterval {
+		l.configRefreshTicker.Reset(config.ConfigRefreshInterval)
+	}
+
+	log.Info("Configuration reloaded")
+
+	l.resync(nil)
+	return nil
+}
+
+// Processes running containers by the workers in background.
+// The links which were unknown before the resync are sent back to the loop upon completion.
+// Must be called from the loop goroutine.
+func (l *EventLoop) resync(unknown map[int]string) {
+	// The links may have changed without Docker events.
+	inspectCache.Clear()
+
+	ctx, rt := l.ctx, l.runtime
+	l.wg.Go(func() {
+		processRunningContainers(ctx, rt, filters.NewArgs(), l.dispatcher)
+		if unknown == nil {
+			return
+		}
+
+		select {
+		case l.resyncDone <- unknown:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// Queues processing of the container by the workers. Must be called from the loop goroutine.
+func (l *EventLoop) submitProcessContainer(containerID string) {
+	rt := l.runtime
+	networks := l.connectNetworks[containerID]
+	delete(l.connectNetworks, containerID)
+	l.dispatcher.Submit(l.ctx, containerID, func(ctx context.Context) {
+		processContainer(ctx, rt, containerID, networks)
+	})
+}
+
+// Renames links of the container which has just connected to the networks.
+// The known container connected to a single network is not inspected, when the naming strategy allows.
+// The configuration read lock is taken by the function, and must not be held by the caller.
+func processContainer(ctx context.Context, rt ContainerRuntime, containerID string, networks []string) {
+	inspect, ok := inspectConnected(ctx, rt, containerID, networks)
+	if ok {
+		renameContainerLinks(ctx, rt, inspect, true)
+	}
+}
+
+// Returns the inspect result of the connected container, unless it is not running or cannot be inspected.
+func inspectConnected(ctx context.Context, rt ContainerRuntime, containerID string, networks []string) (container.InspectResponse, bool) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	inspect, ok := connectedInspect(containerID, networks)
+	if ok {
+		containerLogger(inspect.ID, inspect.Name).Debugf("Container is known, not inspecting it: %s %s", inspect.Name, inspect.ID)
+	} else {
+		var err error
+		inspect, err = inspectContainer(ctx, rt, containerID)
+		if err != nil {
+			errorfLimited(log.WithField(logFieldContainerID, containerID), "cli.ContainerInspect failed for container ID %s: %s", containerID, err)
+			return inspect, false
+		}
+	}
+
+	if inspect.State != nil && !inspect.State.Running {
+		countSkip(SkipNotRunning, inspect.ID, inspect.Name, "")
+		containerLogger(inspect.ID, inspect.Name).Debugf("Container is not running, skipping: %s %s", inspect.Name, inspect.ID)
+		return inspect, false
+	}
+	return inspect, true
+}
+
+// Makes the inspect result of the known running container connected to the network, from its last inspect result.
+// The endpoint of the network is added without the MAC address, and is attributed the link unmatched by the others.
+// Fails unless the naming strategy uses the container name only, and the container connected to a single new network.
+func connectedInspect(containerID string, networks []string) (container.InspectResponse, bool) {
+	if _, ok := namingStrategy().(nameOnlyStrategy); !ok || len(networks) != 1 || len(networks[0]) == 0 {
+		return container.InspectResponse{}, false
+	}
+
+	inspect, ok := inspectCache.Sandbox(containerID)
+	if !ok || inspect.NetworkSettings == nil {
+		return container.InspectResponse{}, false
+	}
+	if _, ok := inspect.NetworkSettings.Networks[networks[0]]; ok {
+		return container.InspectResponse{}, false
+	}
+
+	settings := *inspect.NetworkSettings
+	settings.Networks = maps.Clone(settings.Networks)
+	if settings.Networks == nil {
+		settings.Networks = make(map[string]*network.EndpointSettings)
+	}
+	settings.Networks[networks[0]] = &network.EndpointSettings{}
+	inspect.NetworkSettings = &settings
+	return inspect, true
+}
+
+// Processes the Docker event.
+// Repeated trigger events of the same container are coalesced by the debouncer.
+// The container processing is serialized per container ID by the dispatcher.
+func (l *EventLoop) handleEvent(event events.Message) {
+	logger := log.WithField(logFieldEventAction, string(event.Action))
+	logger.Debugf("Event: Type: %s, Action: %s, ID: %s, Attr: %v", event.Type, event.Action, event.Actor.ID, event.Actor.Attributes)
+
+	l.eventCount++
+	l.lastEvent = LastEvent{
+		Time:   time.Unix(0, event.TimeNano),
+		Type:   event.Type,
+		Action: event.Action,
+	}
+
+	var containerID string
+	switch event.Type {
+	case events.NetworkEventType:
+		containerID = event.Actor.Attributes["container"]
+	case events.ContainerEventType:
+		containerID = event.Actor.ID
+	default:
+		return
+	}
+
+	trigger := dockerwatch.EventTrigger(event)
+	isExit := event.Type == events.ContainerEventType && (event.Action == events.ActionDie || event.Action == events.ActionDestroy)
+	isDisconnect := event.Type == events.NetworkEventType && event.Action == events.ActionDisconnect
+
+	if event.Type == events.ContainerEventType && event.Action == events.ActionRename && len(containerID) > 0 {
+		inspectCache.Forget(containerID)
+	}
+
+	if !isExit && !isDisconnect && !l.triggers[trigger] {
+		return
+	}
+
+	if len(containerID) == 0 {
+		logger.Errorf("Event has no container ID: %s %s", trigger, event.Actor.ID)
+		return
+	}
+
+	// The networks of the container change, the cached inspect result and links are stale.
+	// Connecting to a network keeps the last inspect result of the container, see connectedInspect.
+	switch {
+	case isExit || isDisconnect:
+		inspectCache.Forget(containerID)
+	case trigger == dockerwatch.TriggerNetworkConnect:
+		inspectCache.Invalidate(containerID)
+	}
+
+	switch {
+	case isDisconnect:
+		rt := l.runtime
+		l.dispatcher.SubmitLocked(l.ctx, containerID, func(ctx context.Context) {
+			handleNetworkDisconnect(ctx, rt, containerID)
+		})
+
+	case isExit:
+		// Container name is prefixed with slash in the inspect records.
+		containerName := event.Actor.Attributes["name"]
+		if len(containerName) > 0 {
+			containerName = "/" + containerName
+		}
+
+		l.processDebouncer.Remove(containerID)
+		l.startDebouncer.Remove(containerID)
+		delete(l.connectSeen, containerID)
+		delete(l.connectNetworks, containerID)
+		l.dispatcher.SubmitLocked(l.ctx, containerID, func(context.Context) {
+			handleContainerExit(containerID, containerName)
+		})
+
+	case trigger == dockerwatch.TriggerContainerStart && l.triggers[dockerwatch.TriggerNetworkConnect]:
+		// Connect events may be absent for custom network drivers, or lost during daemon startup.
+		// Process the started container only if no connect event is received for it.
+		l.startDebouncer.Add(containerID)
+
+	default:
+		if trigger == dockerwatch.TriggerNetworkConnect {
+			l.connectSeen[containerID] = time.Now()
+			l.connectNetworks[containerID] = append(l.connectNetworks[containerID], event.Actor.Attributes["name"])
+		}
+
+		if config.EventDebounce > 0 {
+			l.processDebouncer.Add(containerID)
+		} else {
+			l.submitProcessContainer(containerID)
+		}
+	}
+}
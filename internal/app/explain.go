@@ -0,0 +1,55 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+)
+
+// Returns the trace of making the host link name for the container link.
+func explainLinkName(containerName string, containerLinkName string) *naming.Trace {
+	trace := &naming.Trace{ContainerName: containerName, ContainerLink: containerLinkName}
+	req := NameRequest{ContainerName: containerName, ContainerLink: containerLinkName, MaxLength: naming.MaxLength}
+	trace.LinkName, _ = namingStrategy().LinkName(req, trace)
+	return trace
+}
+
+// Prints the trace as numbered steps.
+func printNameTrace(w io.Writer, t *naming.Trace) error {
+	fmt.Fprintf(w, "Container name: %s\n", t.ContainerName)
+	fmt.Fprintf(w, "Container link: %s\n", t.ContainerLink)
+	for i, step := range t.Steps {
+		fmt.Fprintf(w, "%2d. %s\n", i+1, step.Description)
+		if len(step.Result) > 0 {
+			fmt.Fprintf(w, "    => %s\n", step.Result)
+		}
+	}
+
+	if len(t.LinkName) == 0 {
+		_, err := fmt.Fprintln(w, "Host link name: cannot be made")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Host link name: %s (%d bytes)\n", t.LinkName, len(t.LinkName))
+	return err
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainLinkName(t *testing.T) {
+	config = defaultConfig()
+	config.Replacements = []map[string]string{{"server": "srv"}, {"postgres": "pg"}, {"-": ""}}
+
+	trace := explainLinkName("/project/postgres-server-primary", "eth0")
+	assert.Equal(t, makeLinkName("/project/postgres-server-primary", "eth0"), trace.LinkName)
+
+	var descriptions []string
+	for _, step := range trace.Steps {
+		descriptions = append(descriptions, step.Description)
+	}
+	assert.Contains(t, descriptions, `Rule "server" => "srv": matched once`)
+	assert.Contains(t, descriptions, `Rule "-" => "": matched 2 times`)
+	assert.Equal(t, "pgsrvprimary", trace.Steps[3].Result)
+	assert.Equal(t, trace.LinkName, trace.Steps[len(trace.Steps)-1].Result)
+
+	var buf bytes.Buffer
+	require.NoError(t, printNameTrace(&buf, trace))
+	assert.Contains(t, buf.String(), "Truncate \"pgsrvprimary\" (12 bytes) to 10 bytes")
+}
+
+func TestExplainLinkNameFailure(t *testing.T) {
+	config = defaultConfig()
+
+	trace := explainLinkName("web", "very-long-link-name")
+	assert.Empty(t, trace.LinkName)
+	assert.Contains(t, trace.Steps[len(trace.Steps)-1].Description, "No room left")
+}
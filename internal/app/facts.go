@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Facts of the host for the configuration management tools, as the Ansible local facts or the Salt grains.
+type Facts struct {
+	Version string `json:"version"`
+	Host    string `json:"host"`
+	// Whether the daemon responds on the control socket.
+	Running bool `json:"running"`
+	// Status of the running daemon, empty when it is not running.
+	Status *Status `json:"status,omitempty"`
+	// Renamed host links, ordered by the link name.
+	Links []MappingRecord `json:"links"`
+	// Host link names by the container name and the container link.
+	Containers map[string]map[string]string `json:"containers"`
+}
+
+// Returns the facts of the running daemon, or of the state file when the daemon is not running.
+func collectFacts(mappings []ContainerMapping) Facts {
+	facts := Facts{
+		Version:    AppVersion,
+		Links:      mappingRecords(mappings),
+		Containers: map[string]map[string]string{},
+	}
+	facts.Host, _ = os.Hostname()
+
+	if len(config.ControlSocket) > 0 {
+		var status Status
+		if err := controlRequest(config.ControlSocket, http.MethodGet, "/status", &status); err == nil {
+			facts.Running = true
+			facts.Status = &status
+		} else {
+			log.Debugf("Daemon is not reachable: %s", err)
+		}
+	}
+
+	for _, record := range facts.Links {
+		links, ok := facts.Containers[record.ContainerName]
+		if !ok {
+			links = map[string]string{}
+			facts.Containers[record.ContainerName] = links
+		}
+		links[record.ContainerLink] = record.Name
+	}
+	return facts
+}
+
+// Prints the facts as a JSON object, e.g. for the executable fact file of Ansible
+// /etc/ansible/facts.d/docker_veth_namer.fact.
+func printFacts(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collectFacts(daemonMappings()))
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectFacts(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.ControlSocket = ""
+
+	facts := collectFacts([]ContainerMapping{
+		{ID: "1234", Name: "/web", Links: []LinkState{
+			{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"},
+			{Index: 11, ContainerLink: "eth1", OriginalName: "veth3", Name: "vweb1"},
+		}},
+		{ID: "4567", Name: "/db", Links: []LinkState{{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0"}}},
+	})
+
+	assert.False(t, facts.Running)
+	assert.Nil(t, facts.Status)
+	require.Len(t, facts.Links, 3)
+	assert.Equal(t, "vdb0", facts.Links[0].Name)
+	assert.Equal(t, map[string]map[string]string{
+		"web": {"eth0": "vweb0", "eth1": "vweb1"},
+		"db":  {"eth0": "vdb0"},
+	}, facts.Containers)
+
+	var b bytes.Buffer
+	require.NoError(t, json.NewEncoder(&b).Encode(facts))
+	assert.NotContains(t, b.String(), `"status"`)
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Target group of the Prometheus file service discovery.
+type FileSDTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// Returns the target group of each host link, ordered by the link name.
+// The labels match the ones of dvn_interface_info.
+func fileSDTargetGroups(mappings []ContainerMapping) []FileSDTargetGroup {
+	groups := []FileSDTargetGroup{}
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			groups = append(groups, FileSDTargetGroup{
+				Targets: []string{link.Name},
+				Labels: map[string]string{
+					"device":          link.Name,
+					"original_device": link.OriginalName,
+					"ifindex":         strconv.Itoa(link.Index),
+					"container":       strings.TrimPrefix(mapping.Name, "/"),
+					"container_id":    mapping.ID,
+					"container_link":  link.ContainerLink,
+					"image":           link.Image,
+					"network":         link.Network,
+				},
+			})
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b FileSDTargetGroup) int {
+		return strings.Compare(a.Targets[0], b.Targets[0])
+	})
+	return groups
+}
+
+// Writes the target groups of the host links to the file atomically, so Prometheus never reads a partial file.
+// The file is readable by everyone, as Prometheus does not run as root.
+func saveFileSD(path string, mappings []ContainerMapping) error {
+	data, err := json.MarshalIndent(fileSDTargetGroups(mappings), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0o644)
+}
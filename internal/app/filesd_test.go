@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveFileSD(t *testing.T) {
+	mappings := []ContainerMapping{
+		{ID: "0123", Name: "/web", Links: []LinkState{
+			{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0", LinkLabels: LinkLabels{Image: "nginx", Network: "frontend"}},
+		}},
+		{ID: "4567", Name: "/db", Links: []LinkState{
+			{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0", LinkLabels: LinkLabels{Image: "mariadb", Network: "backend"}},
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "targets", "dvn.json")
+	require.NoError(t, saveFileSD(path, mappings))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var groups []FileSDTargetGroup
+	require.NoError(t, json.Unmarshal(data, &groups))
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"vdb0"}, groups[0].Targets)
+	assert.Equal(t, map[string]string{
+		"device":          "vweb0",
+		"original_device": "veth1",
+		"ifindex":         "10",
+		"container":       "web",
+		"container_id":    "0123",
+		"container_link":  "eth0",
+		"image":           "nginx",
+		"network":         "frontend",
+	}, groups[1].Labels)
+
+	// Prometheus expects a list even when there are no targets.
+	require.NoError(t, saveFileSD(path, nil))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+const NotificationLinkFlapping = "link_flapping"
+
+// Detects host links whose name keeps changing, indicating a fight with udev or NetworkManager,
+// or a replacement rule producing unstable output.
+type FlapDetector struct {
+	mu sync.Mutex
+	// Times of the recent renames by link index.
+	renames map[int][]time.Time
+	// Time when flapping was reported by link index.
+	reported map[int]time.Time
+}
+
+var flapDetector = newFlapDetector()
+
+func newFlapDetector() *FlapDetector {
+	return &FlapDetector{
+		renames:  make(map[int][]time.Time),
+		reported: make(map[int]time.Time),
+	}
+}
+
+// Records the rename of the link. Returns the number of renames within the window,
+// and whether the link starts flapping: the threshold is reached, and it was not reported within the window.
+func (d *FlapDetector) Record(index int, now time.Time, window time.Duration, threshold int) (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	windowStart := now.Add(-window)
+
+	renames := d.renames[index]
+	for len(renames) > 0 && renames[0].Before(windowStart) {
+		renames = renames[1:]
+	}
+	renames = append(renames, now)
+	d.renames[index] = renames
+
+	if threshold <= 0 || len(renames) < threshold {
+		return len(renames), false
+	}
+
+	if reported, ok := d.reported[index]; ok && reported.After(windowStart) {
+		return len(renames), false
+	}
+	d.reported[index] = now
+
+	return len(renames), true
+}
+
+// Forgets the link, e.g. when it is removed.
+func (d *FlapDetector) Forget(index int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.renames, index)
+	delete(d.reported, index)
+}
+
+// Forgets the links neither renamed nor reported within the window. Returns the number of the forgotten links.
+func (d *FlapDetector) Prune(now time.Time, window time.Duration) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	windowStart := now.Add(-window)
+
+	pruned := 0
+	for index, renames := range d.renames {
+		if len(renames) > 0 && !renames[len(renames)-1].Before(windowStart) {
+			continue
+		}
+		if reported, ok := d.reported[index]; ok && reported.After(windowStart) {
+			continue
+		}
+		delete(d.renames, index)
+		delete(d.reported, index)
+		pruned++
+	}
+	return pruned
+}
+
+// Records the rename of the tracked link, and warns when the link is flapping.
+func recordLinkRename(containerID string, containerName string, link LinkState, renamedBy string) {
+	count, flapping := flapDetector.Record(link.Index, time.Now(), config.FlapWindow, config.FlapThreshold)
+	if !flapping {
+		return
+	}
+
+	trackedLinkLogger(containerID, containerName, link).Warnf("Link name is flapping, renamed %d times within %s, last by %s: %s %s: %s",
+		count, config.FlapWindow, renamedBy, containerName, link.ContainerLink, link.Name)
+	notify(NotificationLinkFlapping, containerID, containerName, link)
+	recordAlertEvent(AlertEventLinkFlaps)
+}
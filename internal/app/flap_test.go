@@ -0,0 +1,73 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlapDetector(t *testing.T) {
+	d := newFlapDetector()
+	start := time.Unix(1700000000, 0)
+	window := time.Minute
+
+	for i := range 2 {
+		count, flapping := d.Record(1, start.Add(time.Duration(i)*time.Second), window, 3)
+		assert.Equal(t, i+1, count)
+		assert.False(t, flapping)
+	}
+
+	// Threshold is reached.
+	_, flapping := d.Record(1, start.Add(2*time.Second), window, 3)
+	assert.True(t, flapping)
+
+	// Reported once per window.
+	_, flapping = d.Record(1, start.Add(3*time.Second), window, 3)
+	assert.False(t, flapping)
+
+	// Old renames are out of the window.
+	count, flapping := d.Record(1, start.Add(2*window), window, 3)
+	assert.Equal(t, 1, count)
+	assert.False(t, flapping)
+
+	// Disabled with zero threshold.
+	d.Forget(1)
+	for i := range 5 {
+		_, flapping = d.Record(1, start.Add(time.Duration(i)*time.Second), window, 0)
+		assert.False(t, flapping)
+	}
+}
+
+func TestFlapDetectorPrune(t *testing.T) {
+	d := newFlapDetector()
+	start := time.Unix(1700000000, 0)
+	window := time.Minute
+
+	d.Record(1, start, window, 3)
+	d.Record(2, start.Add(window/2), window, 3)
+
+	assert.Equal(t, 1, d.Prune(start.Add(window+time.Second), window))
+	assert.NotContains(t, d.renames, 1)
+	assert.Contains(t, d.renames, 2)
+}
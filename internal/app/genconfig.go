@@ -0,0 +1,166 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/a-ilin/docker-veth-namer/dist"
+	"github.com/docker/docker/api/types/filters"
+	"go.yaml.in/yaml/v3"
+)
+
+// Minimum length of the container name word to suggest a replacement for.
+const suggestMinWordLength = 6
+
+// Maximum number of the suggested replacements.
+const suggestMaxReplacements = 20
+
+// Line of the default configuration file, before which the suggested replacements are inserted.
+// The single letter replacements go last, to not shadow the suggested ones.
+const suggestInsertBefore = "  - a: \"\"\n"
+
+// Returns the words of the container names, for which replacements are worth adding, ordered by frequency.
+// Words already shortened by the multi-letter replacements of the configuration are skipped.
+func suggestReplacements(containerNames []string, replacements []map[string]string) []map[string]string {
+	counts := make(map[string]int)
+	for _, name := range containerNames {
+		name = name[strings.LastIndex(name, "/")+1:]
+		words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+			return !('a' <= r && r <= 'z')
+		})
+		for _, word := range words {
+			if len(word) >= suggestMinWordLength {
+				counts[word]++
+			}
+		}
+	}
+
+	var words []string
+	for word := range counts {
+		covered := slices.ContainsFunc(replacements, func(pair map[string]string) bool {
+			needle, _ := mapKeyVal(pair)
+			return len(needle) > 1 && strings.Contains(word, needle)
+		})
+		if !covered {
+			words = append(words, word)
+		}
+	}
+
+	slices.SortFunc(words, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	if len(words) > suggestMaxReplacements {
+		words = words[:suggestMaxReplacements]
+	}
+
+	suggested := make([]map[string]string, 0, len(words))
+	for _, word := range words {
+		suggested = append(suggested, map[string]string{word: abbreviate(word)})
+	}
+	return suggested
+}
+
+// Abbreviates the word to four letters: the first letter, and the following consonants.
+func abbreviate(word string) string {
+	abbr := []byte{word[0]}
+	for i := 1; i < len(word) && len(abbr) < 4; i++ {
+		if strings.IndexByte("aeiouy", word[i]) != -1 || word[i] == abbr[len(abbr)-1] {
+			continue
+		}
+		abbr = append(abbr, word[i])
+	}
+	return string(abbr)
+}
+
+// Decodes the default configuration file.
+func parseDefaultConfigFile() (Config, error) {
+	c := defaultConfig()
+	err := yaml.Unmarshal([]byte(dist.ConfigFile), &c)
+	return c, err
+}
+
+// Returns the default configuration file with the suggested replacements added.
+func generateConfig(suggested []map[string]string) string {
+	if len(suggested) == 0 {
+		return dist.ConfigFile
+	}
+
+	var sb strings.Builder
+	sb.WriteString("  # Suggested for the running containers.\n")
+	for _, pair := range suggested {
+		needle, replacement := mapKeyVal(pair)
+		fmt.Fprintf(&sb, "  - %s: %s\n", needle, replacement)
+	}
+	sb.WriteString("  # Generic replacements.\n")
+
+	return strings.Replace(dist.ConfigFile, suggestInsertBefore, sb.String()+suggestInsertBefore, 1)
+}
+
+// Returns names of the running containers.
+func runningContainerNames(ctx context.Context, rt ContainerRuntime) ([]string, error) {
+	listCtx, cancel := withTimeout(ctx, config.DockerListTimeout)
+	containers, err := rt.ListContainers(listCtx, filters.NewArgs())
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("cli.ContainerList failed: %w", err)
+	}
+
+	var names []string
+	for _, c := range containers {
+		names = append(names, c.Names...)
+	}
+	return names, nil
+}
+
+// Writes the default configuration file to the path, or to the writer when the path is empty.
+// Existing file is not overwritten unless forced.
+func writeGeneratedConfig(w io.Writer, path string, data string, force bool) error {
+	if len(path) == 0 {
+		_, err := io.WriteString(w, data)
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	configFile, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("file exists, use --force to overwrite: %s", path)
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(configFile, data); err != nil {
+		configFile.Close()
+		return err
+	}
+	return configFile.Close()
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbbreviate(t *testing.T) {
+	assert.Equal(t, "grfn", abbreviate("grafana"))
+	assert.Equal(t, "nxtc", abbreviate("nextcloud"))
+	assert.Equal(t, "apl", abbreviate("apple"))
+}
+
+func TestGenerateConfig(t *testing.T) {
+	defaultReplacements := []map[string]string{{"server": "srv"}, {"a": ""}}
+	suggested := suggestReplacements(
+		[]string{"/grafana-server", "/grafana_agent", "/nextcloud-1", "/db"},
+		defaultReplacements,
+	)
+	assert.Equal(t, []map[string]string{{"grafana": "grfn"}, {"nextcloud": "nxtc"}}, suggested)
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, writeGeneratedConfig(nil, path, generateConfig(suggested), false))
+	assert.Error(t, writeGeneratedConfig(nil, path, generateConfig(suggested), false))
+
+	c, err := loadConfig(path)
+	require.NoError(t, err)
+	assert.Contains(t, c.Replacements, map[string]string{"grafana": "grfn"})
+
+	// Suggested replacements are not shadowed by the single letter ones.
+	config = c
+	defer func() { config = Config{} }()
+	assert.Equal(t, "vgrfnsrv0", makeLinkName("/grafana-server", "eth0"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Suggested for the running containers.")
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"net"
+	"time"
+
+	apiv1 "github.com/a-ilin/docker-veth-namer/api/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TCP address of the gRPC API, specified on the command line. Empty when the API is disabled.
+var grpcListenAddress string
+
+// Serves the control requests as the gRPC service defined in api/v1/namer.proto.
+type GRPCServer struct {
+	server   *grpc.Server
+	listener net.Listener
+}
+
+// Starts serving the gRPC API at the TCP address. Requests are passed to the event loop.
+func newGRPCServer(address string, l *EventLoop) (*GRPCServer, error) {
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(grpcAuthUnaryInterceptor),
+		grpc.StreamInterceptor(grpcAuthStreamInterceptor),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := &GRPCServer{server: grpc.NewServer(options...), listener: listener}
+	apiv1.RegisterNamerServer(s.server, &namerService{l: l})
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			log.Errorf("gRPC server failed: %s", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Stops serving, waiting for the requests in progress up to controlRequestTimeout. Nil server is ignored.
+func (s *GRPCServer) Close() {
+	if s == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(controlRequestTimeout):
+		s.server.Stop()
+	}
+}
+
+// Implements the gRPC service on top of the event loop, as the control socket does.
+type namerService struct {
+	apiv1.UnimplementedNamerServer
+	l *EventLoop
+}
+
+// Returns the protobuf timestamp, nil for the zero time.
+func protoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func (s *namerService) GetStatus(ctx context.Context, _ *apiv1.GetStatusRequest) (*apiv1.Status, error) {
+	st, alive, _ := s.l.queryStatus(ctx)
+	if !alive {
+		return nil, status.Error(codes.Unavailable, "event loop is not responding")
+	}
+
+	return &apiv1.Status{
+		Version:            st.Version,
+		StartTime:          protoTime(st.StartTime),
+		DockerConnected:    st.DockerConnected,
+		DockerPingFailures: int32(st.DockerPingFailures),
+		Paused:             st.Paused,
+		TrackedContainers:  int32(st.TrackedContainers),
+		TrackedLinks:       int32(st.TrackedLinks),
+		PendingTasks:       int32(st.PendingTasks),
+		QueuedContainers:   int32(st.QueuedContainers),
+		PendingRetries:     st.PendingRetries,
+		LastEventTime:      protoTime(st.LastEventTime),
+	}, nil
+}
+
+func (s *namerService) ListMappings(context.Context, *apiv1.ListMappingsRequest) (*apiv1.ListMappingsResponse, error) {
+	resp := &apiv1.ListMappingsResponse{}
+	for _, mapping := range state.Mappings() {
+		container := &apiv1.ContainerMapping{Id: mapping.ID, Name: mapping.Name}
+		for _, link := range mapping.Links {
+			container.Links = append(container.Links, &apiv1.Link{
+				Ifindex:       int32(link.Index),
+				ContainerLink: link.ContainerLink,
+				OriginalName:  link.OriginalName,
+				Name:          link.Name,
+				Image:         link.Image,
+				Network:       link.Network,
+			})
+		}
+		resp.Containers = append(resp.Containers, container)
+	}
+	return resp, nil
+}
+
+func (s *namerService) ListHistory(_ context.Context, req *apiv1.ListHistoryRequest) (*apiv1.ListHistoryResponse, error) {
+	resp := &apiv1.ListHistoryResponse{}
+	for _, r := range filterRenameRecords(renameHistory.Records(), req.GetContainer(), int(req.GetLimit())) {
+		resp.Records = append(resp.Records, &apiv1.RenameRecord{
+			Time:          protoTime(r.Time),
+			Operation:     r.Operation,
+			ContainerId:   r.ContainerID,
+			ContainerName: r.ContainerName,
+			ContainerLink: r.ContainerLink,
+			Ifindex:       int32(r.Index),
+			OldName:       r.OldName,
+			NewName:       r.NewName,
+			Error:         r.Error,
+			DryRun:        r.DryRun,
+		})
+	}
+	return resp, nil
+}
+
+func (s *namerService) WatchEvents(_ *apiv1.WatchEventsRequest, stream grpc.ServerStreamingServer[apiv1.Event]) error {
+	events := eventBroker.Subscribe()
+	defer eventBroker.Unsubscribe(events)
+
+	for {
+		select {
+		case e := <-events:
+			err := stream.Send(&apiv1.Event{
+				Type:          e.Type,
+				Time:          protoTime(e.Time),
+				ContainerId:   e.ContainerID,
+				ContainerName: e.ContainerName,
+				ContainerLink: e.ContainerLink,
+				Ifindex:       int32(e.Index),
+				OldName:       e.OldName,
+				NewName:       e.NewName,
+				Reason:        e.Reason,
+				Error:         e.Error,
+				DryRun:        e.DryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case <-s.l.ctx.Done():
+			return status.Error(codes.Unavailable, "shutting down")
+		}
+	}
+}
+
+func (s *namerService) Resync(ctx context.Context, _ *apiv1.ResyncRequest) (*apiv1.ResyncResponse, error) {
+	select {
+	case s.l.resyncRequests <- struct{}{}:
+		return &apiv1.ResyncResponse{}, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-s.l.ctx.Done():
+		return nil, status.Error(codes.Unavailable, "shutting down")
+	}
+}
+
+func (s *namerService) Revert(ctx context.Context, req *apiv1.RevertRequest) (*apiv1.RevertResponse, error) {
+	if len(req.GetContainer()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "container is not specified")
+	}
+
+	revert := RevertRequest{Container: req.GetContainer(), Reply: make(chan error, 1)}
+	select {
+	case s.l.revertRequests <- revert:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-s.l.ctx.Done():
+		return nil, status.Error(codes.Unavailable, "shutting down")
+	}
+
+	if err := <-revert.Reply; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &apiv1.RevertResponse{}, nil
+}
+
+func (s *namerService) SetPaused(_ context.Context, req *apiv1.SetPausedRequest) (*apiv1.SetPausedResponse, error) {
+	configMu.RLock()
+	err := setPaused(req.GetPaused())
+	configMu.RUnlock()
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &apiv1.SetPausedResponse{}, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/a-ilin/docker-veth-namer/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &EventLoop{ctx: ctx, resyncRequests: make(chan struct{}, 1), revertRequests: make(chan RevertRequest)}
+	go func() {
+		for {
+			select {
+			case req := <-l.revertRequests:
+				req.Reply <- l.revertContainer(req.Container)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s, err := newGRPCServer("127.0.0.1:0", l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	conn, err := grpc.NewClient(s.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := apiv1.NewNamerClient(conn)
+
+	_, err = client.Resync(ctx, &apiv1.ResyncRequest{})
+	require.NoError(t, err)
+	assert.Len(t, l.resyncRequests, 1)
+
+	_, err = client.Revert(ctx, &apiv1.RevertRequest{Container: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := client.ListMappings(ctx, &apiv1.ListMappingsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetContainers(), len(state.Mappings()))
+
+	stream, err := client.WatchEvents(ctx, &apiv1.WatchEventsRequest{})
+	require.NoError(t, err)
+	// The subscription is made when the stream is opened, wait for the server side.
+	require.Eventually(t, func() bool {
+		eventBroker.mu.Lock()
+		defer eventBroker.mu.Unlock()
+		return len(eventBroker.subscribers) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	countSkip(SkipNoneNetwork, "0123", "/web", "")
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, StreamEventSkip, event.GetType())
+	assert.Equal(t, SkipNoneNetwork, event.GetReason())
+	assert.Equal(t, "/web", event.GetContainerName())
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Time the event loop is given to respond to the health request.
+const healthLoopTimeout = 2 * time.Second
+
+// Health of the running daemon.
+type Health struct {
+	Healthy bool `json:"healthy"`
+	// Whether the event loop responds to requests.
+	EventLoopAlive  bool      `json:"event_loop_alive"`
+	DockerConnected bool      `json:"docker_connected"`
+	LastEventTime   time.Time `json:"last_event_time"`
+	// Time since the last Docker event, empty if no events were received.
+	LastEventAge string   `json:"last_event_age,omitempty"`
+	Problems     []string `json:"problems,omitempty"`
+}
+
+// Queries the status from the event loop, giving it healthLoopTimeout to respond.
+// Returns whether the event loop responded, and whether it is shutting down.
+func (l *EventLoop) queryStatus(ctx context.Context) (status Status, alive bool, shuttingDown bool) {
+	ctx, cancel := context.WithTimeout(ctx, healthLoopTimeout)
+	defer cancel()
+
+	reply := make(chan Status, 1)
+	select {
+	case l.statusRequests <- reply:
+		select {
+		case status = <-reply:
+			alive = true
+		case <-ctx.Done():
+		}
+	case <-ctx.Done():
+	case <-l.ctx.Done():
+		shuttingDown = true
+	}
+	return status, alive, shuttingDown
+}
+
+// Returns the health of the daemon, queried from the event loop.
+func (l *EventLoop) health(ctx context.Context) Health {
+	var health Health
+
+	status, alive, shuttingDown := l.queryStatus(ctx)
+	health.EventLoopAlive = alive
+	if shuttingDown {
+		health.Problems = append(health.Problems, "shutting down")
+	}
+
+	if !health.EventLoopAlive {
+		health.Problems = append(health.Problems, "event loop is not responding")
+	} else {
+		health.DockerConnected = status.DockerConnected
+		health.LastEventTime = status.LastEventTime
+		if !status.LastEventTime.IsZero() {
+			health.LastEventAge = time.Since(status.LastEventTime).Round(time.Second).String()
+		}
+		if !status.DockerConnected {
+			health.Problems = append(health.Problems, "Docker events stream is not connected")
+		}
+	}
+
+	health.Healthy = len(health.Problems) == 0
+	return health
+}
+
+// Serves the health of the daemon: 200 when healthy, 503 otherwise.
+func healthHandler(l *EventLoop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		health := l.health(r.Context())
+		if !health.Healthy {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(health)
+			return
+		}
+		writeJSON(w, health)
+	}
+}
+
+// Queries the health of the running daemon via the control socket.
+func queryHealth(path string) (Health, error) {
+	var health Health
+	if len(path) == 0 {
+		return health, errors.New("control socket is disabled in the configuration")
+	}
+
+	resp, err := controlClient(path).Get("http://daemon/healthz")
+	if err != nil {
+		return health, fmt.Errorf("cannot connect to the daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return health, fmt.Errorf("daemon responded: %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	return health, err
+}
+
+// Prints the health of the running daemon.
+// Returns an error if the daemon is not reachable, or not healthy.
+func runHealthcheck(w io.Writer, path string) error {
+	health, err := queryHealth(path)
+	if err != nil {
+		return err
+	}
+
+	err = printOutput(w, health, func(w io.Writer) error {
+		if health.Healthy {
+			_, err := fmt.Fprintln(w, "healthy")
+			return err
+		}
+		_, err := fmt.Fprintf(w, "unhealthy: %s\n", strings.Join(health.Problems, ", "))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !health.Healthy {
+		return errors.New("daemon is not healthy")
+	}
+	return nil
+}
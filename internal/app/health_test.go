@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthcheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	connected := make(chan bool, 1)
+	l := &EventLoop{ctx: ctx, statusRequests: make(chan chan Status)}
+	go func() {
+		for {
+			select {
+			case reply := <-l.statusRequests:
+				reply <- Status{DockerConnected: <-connected, LastEventTime: time.Now().Add(-time.Minute)}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "control.sock")
+	s, err := newControlServer(path, l)
+	require.NoError(t, err)
+	defer s.Close()
+
+	var buf bytes.Buffer
+	connected <- true
+	require.NoError(t, runHealthcheck(&buf, path))
+	assert.Equal(t, "healthy\n", buf.String())
+
+	buf.Reset()
+	connected <- false
+	require.Error(t, runHealthcheck(&buf, path))
+	assert.Equal(t, "unhealthy: Docker events stream is not connected\n", buf.String())
+
+	assert.Error(t, runHealthcheck(&buf, filepath.Join(t.TempDir(), "missing.sock")))
+}
+
+func TestHealthEventLoopNotResponding(t *testing.T) {
+	l := &EventLoop{ctx: context.Background(), statusRequests: make(chan chan Status)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	health := l.health(ctx)
+	assert.False(t, health.Healthy)
+	assert.False(t, health.EventLoopAlive)
+	assert.Equal(t, []string{"event loop is not responding"}, health.Problems)
+}
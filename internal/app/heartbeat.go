@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Numbers of the host links renamed, and failed to be renamed, since the start.
+var (
+	renameCount        atomic.Uint64
+	renameFailureCount atomic.Uint64
+)
+
+// Activity counters at the time of the previous heartbeat.
+type Heartbeat struct {
+	time     time.Time
+	events   uint64
+	renames  uint64
+	failures uint64
+}
+
+// Sets the interval of the heartbeat log records. Zero interval disables the heartbeat.
+func (l *EventLoop) setHeartbeatInterval(interval time.Duration) {
+	if l.heartbeatTicker != nil {
+		l.heartbeatTicker.Stop()
+		l.heartbeatTicker = nil
+	}
+
+	if interval > 0 {
+		l.heartbeatTicker = time.NewTicker(interval)
+	}
+}
+
+// Returns the channel firing when the heartbeat is due. Nil channel is returned when the heartbeat is disabled.
+func (l *EventLoop) heartbeatDue() <-chan time.Time {
+	if l.heartbeatTicker == nil {
+		return nil
+	}
+	return l.heartbeatTicker.C
+}
+
+// Logs the summary of the activity since the previous heartbeat.
+func (l *EventLoop) heartbeat() {
+	now := Heartbeat{
+		time:     time.Now(),
+		events:   l.eventCount,
+		renames:  renameCount.Load(),
+		failures: renameFailureCount.Load(),
+	}
+
+	var containers, links int
+	for _, cs := range state.Containers() {
+		containers++
+		links += len(cs.Links)
+	}
+
+	log.WithFields(log.Fields{
+		"events":             now.events - l.lastHeartbeat.events,
+		"renames":            now.renames - l.lastHeartbeat.renames,
+		"failures":           now.failures - l.lastHeartbeat.failures,
+		"tracked_containers": containers,
+		"tracked_links":      links,
+	}).Infof("Heartbeat: %d events, %d renames, %d failures in the last %s; tracking %d containers, %d links",
+		now.events-l.lastHeartbeat.events, now.renames-l.lastHeartbeat.renames, now.failures-l.lastHeartbeat.failures,
+		now.time.Sub(l.lastHeartbeat.time).Round(time.Second), containers, links)
+
+	l.lastHeartbeat = now
+}
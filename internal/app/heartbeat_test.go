@@ -0,0 +1,70 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeat(t *testing.T) {
+	prevState := state
+	defer func() { state = prevState }()
+	state = newState()
+	state.SetLink("0123", "/web", LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"})
+
+	var b bytes.Buffer
+	out := log.StandardLogger().Out
+	log.SetOutput(&b)
+	defer log.SetOutput(out)
+
+	l := &EventLoop{eventCount: 7, lastHeartbeat: Heartbeat{
+		time:     time.Now().Add(-time.Hour),
+		events:   2,
+		renames:  renameCount.Load(),
+		failures: renameFailureCount.Load(),
+	}}
+	renameCount.Add(3)
+	renameFailureCount.Add(1)
+
+	l.heartbeat()
+	assert.Contains(t, b.String(), "Heartbeat: 5 events, 3 renames, 1 failures in the last 1h0m0s; tracking 1 containers, 1 links")
+	assert.Equal(t, uint64(7), l.lastHeartbeat.events)
+
+	b.Reset()
+	l.heartbeat()
+	assert.Contains(t, b.String(), "Heartbeat: 0 events, 0 renames, 0 failures")
+}
+
+func TestHeartbeatInterval(t *testing.T) {
+	l := &EventLoop{}
+	assert.Nil(t, l.heartbeatDue())
+
+	l.setHeartbeatInterval(time.Hour)
+	assert.NotNil(t, l.heartbeatDue())
+
+	l.setHeartbeatInterval(0)
+	assert.Nil(t, l.heartbeatDue())
+}
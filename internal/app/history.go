@@ -0,0 +1,279 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of recent rename operations kept in memory.
+const renameHistorySize = 1000
+
+const (
+	// Renaming to the name made by the program.
+	RenameApply = "rename"
+	// Renaming back to the original name.
+	RenameRestore = "restore"
+	// Stopping tracking of the host link, e.g. on the container exit. The name is left as is.
+	// Recorded only in the timeline database, ending the assignment of the name.
+	RenameRelease = "release"
+)
+
+// Rename operation on the host link.
+type RenameRecord struct {
+	Time          time.Time `json:"time"`
+	Operation     string    `json:"operation"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	ContainerLink string    `json:"container_link"`
+	Index         int       `json:"ifindex"`
+	OldName       string    `json:"old_name"`
+	NewName       string    `json:"new_name"`
+	// Empty on success.
+	Error  string `json:"error,omitempty"`
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// Returns the result of the operation.
+func (r RenameRecord) Result() string {
+	if len(r.Error) > 0 {
+		return "failed: " + r.Error
+	}
+	if r.DryRun {
+		return "dry run"
+	}
+	return "ok"
+}
+
+// Recent rename operations.
+type RenameHistory struct {
+	mu      sync.Mutex
+	size    int
+	records []RenameRecord
+}
+
+var renameHistory = newRenameHistory(renameHistorySize)
+
+// Serializes appending to the audit log.
+var auditLogMu sync.Mutex
+
+func newRenameHistory(size int) *RenameHistory {
+	return &RenameHistory{size: size}
+}
+
+func (h *RenameHistory) Add(record RenameRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.records) == h.size {
+		h.records = slices.Delete(h.records, 0, 1)
+	}
+	h.records = append(h.records, record)
+}
+
+// Returns a copy of the records, oldest first.
+func (h *RenameHistory) Records() []RenameRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Clone(h.records)
+}
+
+// Records the rename operation in the history, and appends it to the audit log when configured.
+// The restored name ends the assignment in the timeline database.
+func recordRename(operation string, containerID string, containerName string, containerLink string, index int, oldName string, newName string, err error) {
+	record := RenameRecord{
+		Time:          time.Now(),
+		Operation:     operation,
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		ContainerLink: containerLink,
+		Index:         index,
+		OldName:       oldName,
+		NewName:       newName,
+		DryRun:        dryRun,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	renameHistory.Add(record)
+	if err != nil && !dryRun {
+		recordAlertEvent(AlertEventRenameFailures)
+	}
+	eventBroker.Publish(renameStreamEvent(record))
+
+	if len(config.AuditLogFile) > 0 {
+		if err := appendAuditLog(config.AuditLogFile, record); err != nil {
+			log.Errorf("Cannot write audit log: %s: %s", config.AuditLogFile, err)
+		}
+	}
+
+	if operation == RenameRestore && err == nil {
+		recordTimeline(func(t *Timeline) error { return t.End(record.Time, index, RenameRestore) })
+	}
+}
+
+// Records the assignment of the name to the host link in the timeline database, ending its previous assignment.
+func recordAssignment(containerID string, containerName string, link LinkState) {
+	at := time.Now()
+	recordTimeline(func(t *Timeline) error {
+		return t.Assign(at, TimelineAssignment{
+			Index:         link.Index,
+			Name:          link.Name,
+			OriginalName:  link.OriginalName,
+			ContainerID:   containerID,
+			ContainerName: containerName,
+			ContainerLink: link.ContainerLink,
+		})
+	})
+}
+
+// Records the end of the tracking of the host link in the timeline database, so the link is not owned anymore.
+func recordRelease(trackedLink LinkState) {
+	at := time.Now()
+	recordTimeline(func(t *Timeline) error { return t.End(at, trackedLink.Index, RenameRelease) })
+}
+
+// Appends the record as a JSON line to the audit log.
+func appendAuditLog(path string, record RenameRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	auditLogMu.Lock()
+	defer auditLogMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	auditFile, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := auditFile.Write(append(data, '\n')); err != nil {
+		auditFile.Close()
+		return err
+	}
+	return auditFile.Close()
+}
+
+// Reads the records from the audit log. Missing file results in no records.
+func readAuditLog(path string) ([]RenameRecord, error) {
+	auditFile, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer auditFile.Close()
+
+	var records []RenameRecord
+	scanner := bufio.NewScanner(auditFile)
+	for scanner.Scan() {
+		var record RenameRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Tolerate the line truncated by a crash.
+			log.Warnf("Invalid audit log record: %s: %s", path, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Returns the records of the container specified by name or ID (or its prefix), limited to the most recent ones.
+// Empty container selects all records, zero limit selects all records.
+func filterRenameRecords(records []RenameRecord, nameOrID string, limit int) []RenameRecord {
+	if len(nameOrID) > 0 {
+		records = slices.DeleteFunc(slices.Clone(records), func(r RenameRecord) bool {
+			return !matchContainer(ContainerState{ID: r.ContainerID, Name: r.ContainerName}, nameOrID)
+		})
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records
+}
+
+// Prints the recent rename operations: from the running daemon, or from the audit log when the daemon is not reachable.
+func printHistory(w io.Writer, nameOrID string, limit int) error {
+	var records []RenameRecord
+
+	query := url.Values{}
+	if len(nameOrID) > 0 {
+		query.Set("container", nameOrID)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var err error
+	if len(config.ControlSocket) > 0 {
+		err = controlRequest(config.ControlSocket, "GET", "/history?"+query.Encode(), &records)
+	} else {
+		err = errors.New("control socket is disabled in the configuration")
+	}
+
+	if err != nil {
+		if len(config.AuditLogFile) == 0 {
+			return err
+		}
+
+		log.Debugf("Daemon is not reachable, reading audit log: %s", err)
+		records, err = readAuditLog(config.AuditLogFile)
+		if err != nil {
+			return fmt.Errorf("cannot read audit log: %w", err)
+		}
+		records = filterRenameRecords(records, nameOrID, limit)
+	}
+
+	return printOutput(w, records, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tOPERATION\tCONTAINER\tLINK\tOLD NAME\tNEW NAME\tRESULT")
+		for _, r := range records {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Time.Local().Format(time.DateTime), r.Operation, strings.TrimPrefix(r.ContainerName, "/"),
+				r.ContainerLink, r.OldName, r.NewName, r.Result())
+		}
+		return tw.Flush()
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+
+	records, err := readAuditLog(path)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, appendAuditLog(path, RenameRecord{Operation: RenameApply, ContainerID: "0123", ContainerName: "/web", OldName: "veth1", NewName: "vweb0"}))
+	require.NoError(t, appendAuditLog(path, RenameRecord{Operation: RenameApply, ContainerID: "4567", ContainerName: "/db", OldName: "veth2", NewName: "vdb0", Error: "busy"}))
+
+	// Truncated line is skipped.
+	auditFile, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = auditFile.WriteString("{\"time\":\n")
+	require.NoError(t, err)
+	require.NoError(t, auditFile.Close())
+
+	records, err = readAuditLog(path)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "ok", records[0].Result())
+	assert.Equal(t, "failed: busy", records[1].Result())
+
+	assert.Len(t, filterRenameRecords(records, "", 0), 2)
+	assert.Len(t, filterRenameRecords(records, "", 1), 1)
+	assert.Equal(t, "vdb0", filterRenameRecords(records, "", 1)[0].NewName)
+	assert.Equal(t, "vweb0", filterRenameRecords(records, "web", 0)[0].NewName)
+	assert.Equal(t, "vdb0", filterRenameRecords(records, "45", 0)[0].NewName)
+}
+
+func TestRenameHistory(t *testing.T) {
+	h := newRenameHistory(2)
+	for _, name := range []string{"a", "b", "c"} {
+		h.Add(RenameRecord{NewName: name})
+	}
+
+	records := h.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, "b", records[0].NewName)
+	assert.Equal(t, "c", records[1].NewName)
+}
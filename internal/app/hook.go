@@ -0,0 +1,108 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	HookPreRename  = "pre_rename"
+	HookPostRename = "post_rename"
+
+	// Results of the rename passed to the post-rename hook.
+	HookResultRenamed = "renamed"
+	HookResultFailed  = "failed"
+)
+
+// Details of the rename passed to the hooks via the environment variables.
+type HookRename struct {
+	ContainerID   string
+	ContainerName string
+	ContainerLink string
+	Index         int
+	OldName       string
+	NewName       string
+	// Result of the rename, set for the post-rename hook only.
+	Result string
+	// Failure of the rename, set for the post-rename hook only.
+	Err error
+}
+
+// Returns the environment of the hook command.
+func (r HookRename) environ(hook string) []string {
+	env := append(os.Environ(),
+		"DVN_HOOK="+hook,
+		"DVN_CONTAINER="+strings.TrimPrefix(r.ContainerName, "/"),
+		"DVN_CONTAINER_ID="+r.ContainerID,
+		"DVN_CONTAINER_LINK="+r.ContainerLink,
+		"DVN_IFINDEX="+strconv.Itoa(r.Index),
+		"DVN_OLD_NAME="+r.OldName,
+		"DVN_NEW_NAME="+r.NewName,
+	)
+	if len(r.Result) > 0 {
+		env = append(env, "DVN_RESULT="+r.Result)
+	}
+	if r.Err != nil {
+		env = append(env, "DVN_ERROR="+r.Err.Error())
+	}
+	return env
+}
+
+// Returns the command run via the shell, which is killed along with its children when the context is done.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	// The commands started by the shell are killed too, as they may keep the output open.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
+
+// Runs the hook command via the shell, and waits for it to complete within the configured timeout.
+// Empty command is ignored. The error includes the output of the failed command.
+func runHook(hook string, command string, rename HookRename) error {
+	if len(command) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.HookTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Env = rename.environ(hook)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if output = bytes.TrimSpace(output); len(output) > 0 {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHook(t *testing.T) {
+	prevConfig := config
+	defer func() { config = prevConfig }()
+	config = defaultConfig()
+
+	rename := HookRename{
+		ContainerID:   "0123",
+		ContainerName: "/web",
+		ContainerLink: "eth0",
+		Index:         42,
+		OldName:       "veth1a2b3c4",
+		NewName:       "vweb0",
+	}
+
+	assert.NoError(t, runHook(HookPreRename, "", rename))
+	assert.NoError(t, runHook(HookPreRename,
+		`test "$DVN_HOOK $DVN_CONTAINER $DVN_CONTAINER_ID $DVN_CONTAINER_LINK $DVN_IFINDEX $DVN_OLD_NAME $DVN_NEW_NAME" = "pre_rename web 0123 eth0 42 veth1a2b3c4 vweb0"`,
+		rename))
+	assert.NoError(t, runHook(HookPreRename, `test -z "$DVN_RESULT"`, rename))
+
+	err := runHook(HookPreRename, `echo "vweb0 is reserved"; exit 1`, rename)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vweb0 is reserved")
+
+	rename.Result, rename.Err = HookResultFailed, errors.New("file exists")
+	assert.NoError(t, runHook(HookPostRename, `test "$DVN_HOOK $DVN_RESULT $DVN_ERROR" = "post_rename failed file exists"`, rename))
+
+	config.HookTimeout = 100 * time.Millisecond
+	assert.Error(t, runHook(HookPostRename, "sleep 5", rename))
+}
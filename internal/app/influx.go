@@ -0,0 +1,201 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+var (
+	// Escapes the measurement name of the line protocol.
+	influxMeasurementReplacer = strings.NewReplacer(",", `\,`, " ", `\ `)
+	// Escapes the tag keys and values, and the field keys of the line protocol.
+	influxTagReplacer = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// Line of the InfluxDB line protocol.
+type InfluxPoint struct {
+	Measurement string
+	// Tags in order of appearance. Tags with empty values are omitted, as the protocol does not allow them.
+	Tags [][2]string
+	// Fields in order of appearance, formatted as the protocol values.
+	Fields [][2]string
+}
+
+func (p *InfluxPoint) tag(key string, value string) {
+	p.Tags = append(p.Tags, [2]string{key, value})
+}
+
+func (p *InfluxPoint) integer(key string, value uint64) {
+	p.Fields = append(p.Fields, [2]string{key, strconv.FormatUint(value, 10) + "i"})
+}
+
+func (p *InfluxPoint) float(key string, value float64) {
+	p.Fields = append(p.Fields, [2]string{key, strconv.FormatFloat(value, 'f', -1, 64)})
+}
+
+func (p *InfluxPoint) boolean(key string, value bool) {
+	p.Fields = append(p.Fields, [2]string{key, strconv.FormatBool(value)})
+}
+
+// Writes the point with the timestamp in nanoseconds.
+func (p InfluxPoint) write(w io.Writer, now time.Time) error {
+	var sb strings.Builder
+	sb.WriteString(influxMeasurementReplacer.Replace(p.Measurement))
+	for _, tag := range p.Tags {
+		if len(tag[1]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, ",%s=%s", influxTagReplacer.Replace(tag[0]), influxTagReplacer.Replace(tag[1]))
+	}
+	for i, field := range p.Fields {
+		separator := ","
+		if i == 0 {
+			separator = " "
+		}
+		fmt.Fprintf(&sb, "%s%s=%s", separator, influxTagReplacer.Replace(field[0]), field[1])
+	}
+
+	_, err := fmt.Fprintf(w, "%s %d\n", sb.String(), now.UnixNano())
+	return err
+}
+
+// Returns the points of the metrics snapshot: one per host link with its counters, when known, and the daemon indicators.
+// The tags of the host link points match the labels of dvn_interface_info.
+func influxPoints(snapshot MetricsSnapshot, linkStatistics func(index int) *netlink.LinkStatistics) []InfluxPoint {
+	var points []InfluxPoint
+	for _, mapping := range snapshot.Mappings {
+		for _, link := range mapping.Links {
+			p := InfluxPoint{Measurement: "dvn_interface"}
+			p.tag("device", link.Name)
+			p.tag("original_device", link.OriginalName)
+			p.tag("ifindex", strconv.Itoa(link.Index))
+			p.tag("container", strings.TrimPrefix(mapping.Name, "/"))
+			p.tag("container_id", mapping.ID)
+			p.tag("container_link", link.ContainerLink)
+			p.tag("image", link.Image)
+			p.tag("network", link.Network)
+
+			p.integer("info", 1)
+			if stats := linkStatistics(link.Index); stats != nil {
+				p.integer("rx_bytes", stats.RxBytes)
+				p.integer("tx_bytes", stats.TxBytes)
+				p.integer("rx_packets", stats.RxPackets)
+				p.integer("tx_packets", stats.TxPackets)
+				p.integer("rx_errors", stats.RxErrors)
+				p.integer("tx_errors", stats.TxErrors)
+				p.integer("rx_dropped", stats.RxDropped)
+				p.integer("tx_dropped", stats.TxDropped)
+			}
+			points = append(points, p)
+		}
+	}
+
+	p := InfluxPoint{Measurement: "dvn"}
+	p.integer("tracked_links", uint64(snapshot.TrackedLinks))
+	if d := snapshot.Daemon; d != nil {
+		p.boolean("event_loop_up", d.EventLoopAlive)
+		p.integer("pending_tasks", uint64(d.PendingTasks))
+		p.integer("queued_containers", uint64(d.QueuedContainers))
+		p.integer("pending_retries", uint64(d.PendingRetries))
+		p.integer("event_queue_depth", uint64(d.EventQueueDepth))
+		p.integer("event_queue_capacity", uint64(d.EventQueueCapacity))
+		p.integer("events_dropped", d.EventsDropped)
+		p.integer("event_reads_deferred", d.EventReadsDeferred)
+		if d.LastEventAgeSeconds >= 0 {
+			p.float("last_event_age_seconds", d.LastEventAgeSeconds)
+		}
+		p.integer("goroutines", uint64(d.Goroutines))
+	}
+	points = append(points, p)
+
+	for _, stage := range slices.Sorted(maps.Keys(snapshot.Durations)) {
+		p := InfluxPoint{Measurement: "dvn_duration"}
+		p.tag("stage", stage)
+		p.integer("count", snapshot.Durations[stage].Count)
+		p.float("sum_seconds", snapshot.Durations[stage].SumSeconds)
+		points = append(points, p)
+	}
+
+	for _, reason := range slices.Sorted(maps.Keys(snapshot.Skipped)) {
+		p := InfluxPoint{Measurement: "dvn_skipped"}
+		p.tag("reason", reason)
+		p.integer("count", snapshot.Skipped[reason])
+		points = append(points, p)
+	}
+
+	return points
+}
+
+// Returns the counters of the host link, or nil if the link is gone.
+func hostLinkStatistics(index int) *netlink.LinkStatistics {
+	link, err := nlLinkByIndex(index)
+	if err != nil {
+		log.Debugf("netlink.LinkByIndex failed: %d: %s", index, err)
+		return nil
+	}
+	return link.Attrs().Statistics
+}
+
+// Prints the metrics of the running daemon, queried via the control socket, and the counters of the host links
+// in the InfluxDB line protocol, e.g. for the exec input of Telegraf.
+func printInflux(w io.Writer, path string) error {
+	if len(path) == 0 {
+		return errors.New("control socket is disabled in the configuration")
+	}
+
+	var snapshot MetricsSnapshot
+	if err := controlRequest(path, http.MethodGet, "/metrics?format=json", &snapshot); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, p := range influxPoints(snapshot, hostLinkStatistics) {
+		if err := p.write(w, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prints the metrics in the InfluxDB line protocol on each line read, until the input is closed.
+// This is the protocol of the execd input of Telegraf with signal "STDIN". Failures are logged, and do not stop the loop.
+func runInfluxExecd(r io.Reader, w io.Writer, path string) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if err := printInflux(w, path); err != nil {
+			log.Errorf("Cannot print metrics: %s", err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestInfluxPoints(t *testing.T) {
+	snapshot := MetricsSnapshot{
+		TrackedLinks: 2,
+		Durations:    map[string]HistogramSnapshot{"rename": {Count: 3, SumSeconds: 0.25}},
+		Skipped:      map[string]uint64{SkipPaused: 1},
+		Mappings: []ContainerMapping{
+			{ID: "4567", Name: "/db", Links: []LinkState{
+				{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0", LinkLabels: LinkLabels{Image: "postgres:16", Network: "back end"}},
+				{Index: 14, ContainerLink: "eth1", OriginalName: "veth3", Name: "vdb1"},
+			}},
+		},
+		Daemon: &DaemonMetrics{EventLoopAlive: true, PendingTasks: 1, LastEventAgeSeconds: -1, Goroutines: 12},
+	}
+
+	statistics := func(index int) *netlink.LinkStatistics {
+		if index != 12 {
+			return nil
+		}
+		return &netlink.LinkStatistics{RxBytes: 100, TxBytes: 200, RxPackets: 1, TxPackets: 2}
+	}
+
+	var buf bytes.Buffer
+	now := time.Unix(1767225600, 0)
+	for _, p := range influxPoints(snapshot, statistics) {
+		require.NoError(t, p.write(&buf, now))
+	}
+
+	assert.Equal(t, []string{
+		`dvn_interface,device=vdb0,original_device=veth2,ifindex=12,container=db,container_id=4567,container_link=eth0,image=postgres:16,network=back\ end ` +
+			`info=1i,rx_bytes=100i,tx_bytes=200i,rx_packets=1i,tx_packets=2i,rx_errors=0i,tx_errors=0i,rx_dropped=0i,tx_dropped=0i 1767225600000000000`,
+		`dvn_interface,device=vdb1,original_device=veth3,ifindex=14,container=db,container_id=4567,container_link=eth1 info=1i 1767225600000000000`,
+		`dvn tracked_links=2i,event_loop_up=true,pending_tasks=1i,queued_containers=0i,pending_retries=0i,event_queue_depth=0i,event_queue_capacity=0i,events_dropped=0i,event_reads_deferred=0i,goroutines=12i 1767225600000000000`,
+		`dvn_duration,stage=rename count=3i,sum_seconds=0.25 1767225600000000000`,
+		`dvn_skipped,reason=paused count=1i 1767225600000000000`,
+	}, strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"))
+}
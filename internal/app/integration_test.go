@@ -0,0 +1,222 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build integration
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	nlmsg "github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// Scratch network namespace holding the container end of the veth pair, with its host end in the namespace of the test.
+type scratchContainer struct {
+	namespace string
+	path      string
+	hostIndex int
+	hostName  string
+	peerMAC   string
+}
+
+// Creates the named network namespace with the container end of a new veth pair named eth0.
+// Everything is removed at the end of the test.
+func newScratchContainer(t *testing.T, suffix string) *scratchContainer {
+	c := &scratchContainer{
+		namespace: fmt.Sprintf("dvn-it-%d-%s", os.Getpid(), suffix),
+		hostName:  "veth" + suffix,
+	}
+	c.path = "/var/run/netns/" + c.namespace
+
+	// Creating the namespace switches the thread into it.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	origin, err := netns.Get()
+	require.NoError(t, err)
+	defer origin.Close()
+
+	ns, err := netns.NewNamed(c.namespace)
+	require.NoError(t, err)
+	defer ns.Close()
+	require.NoError(t, netns.Set(origin))
+	t.Cleanup(func() { _ = netns.DeleteNamed(c.namespace) })
+
+	peerName := "dvnit" + suffix
+	veth := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: c.hostName}, PeerName: peerName}
+	require.NoError(t, netlink.LinkAdd(veth))
+	t.Cleanup(func() {
+		if link, err := netlink.LinkByIndex(c.hostIndex); err == nil {
+			_ = netlink.LinkDel(link)
+		}
+	})
+
+	host, err := netlink.LinkByName(c.hostName)
+	require.NoError(t, err)
+	c.hostIndex = host.Attrs().Index
+
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetNsFd(peer, int(ns)))
+
+	handle, err := netlink.NewHandleAt(ns)
+	require.NoError(t, err)
+	defer handle.Close()
+	peer, err = handle.LinkByName(peerName)
+	require.NoError(t, err)
+	require.NoError(t, handle.LinkSetName(peer, "eth0"))
+	c.peerMAC = peer.Attrs().HardwareAddr.String()
+
+	return c
+}
+
+// Returns the alternative names of the host link.
+func hostAltNames(t *testing.T, index int) []string {
+	link, err := netlink.LinkByIndex(index)
+	require.NoError(t, err)
+	return link.Attrs().AltNames
+}
+
+func TestIntegrationRenamePipeline(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("integration tests require root")
+	}
+
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	c := newScratchContainer(t, "it0")
+	inspect := bridgeContainer("it0", "/dvnit", c.path, c.peerMAC)
+
+	summary := renameContainerLinks(context.Background(), newFakeRuntime(), inspect, false)
+	require.Equal(t, 1, summary.Renamed, "failures: %v", summary.Failures)
+
+	link, err := netlink.LinkByIndex(c.hostIndex)
+	require.NoError(t, err)
+	assert.Equal(t, "vdvnit0", link.Attrs().Name)
+	// Alternative names are supported since Linux 5.5.
+	if altNames := hostAltNames(t, c.hostIndex); len(altNames) > 0 {
+		assert.True(t, slices.Contains(altNames, c.hostName), "alternative names: %v", altNames)
+	}
+
+	links := state.Links("it0")
+	require.Len(t, links, 1)
+	assert.Equal(t, LinkState{Index: c.hostIndex, ContainerLink: "eth0", OriginalName: c.hostName, Name: "vdvnit0",
+		LinkLabels: LinkLabels{Image: "nginx", Network: "bridge"}}, links[0])
+
+	// The next pass finds the link renamed already.
+	summary = renameContainerLinks(context.Background(), newFakeRuntime(), inspect, false)
+	assert.Equal(t, 1, summary.Unchanged)
+
+	restoreLinkName("it0", "/dvnit", links[0])
+	link, err = netlink.LinkByIndex(c.hostIndex)
+	require.NoError(t, err)
+	assert.Equal(t, c.hostName, link.Attrs().Name)
+	assert.NotContains(t, hostAltNames(t, c.hostIndex), c.hostName)
+}
+
+func TestIntegrationNameConflict(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("integration tests require root")
+	}
+
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	// Another link holds the name the container link would get.
+	blocker := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "vdvnit0"}, PeerName: "dvnitblock"}
+	require.NoError(t, netlink.LinkAdd(blocker))
+	defer netlink.LinkDel(blocker)
+
+	c := newScratchContainer(t, "it1")
+	summary := renameContainerLinks(context.Background(), newFakeRuntime(), bridgeContainer("it1", "/dvnit", c.path, c.peerMAC), false)
+	assert.Equal(t, 1, summary.Failed)
+
+	link, err := netlink.LinkByIndex(c.hostIndex)
+	require.NoError(t, err)
+	assert.Equal(t, c.hostName, link.Attrs().Name)
+	assert.Empty(t, state.Links("it1"))
+}
+
+func TestIntegrationKindFilter(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("integration tests require root")
+	}
+
+	c := newScratchContainer(t, "2")
+	ns, err := netns.GetFromPath(c.path)
+	require.NoError(t, err)
+	defer ns.Close()
+
+	s, err := nlmsg.GetNetlinkSocketAt(ns, netns.None(), unix.NETLINK_ROUTE)
+	require.NoError(t, err)
+	defer s.Close()
+
+	// The loopback link is filtered out by the kernel.
+	links, err := listVEthLinks(s)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "eth0", links[0].Attrs().Name)
+	assert.Equal(t, "veth", links[0].Type())
+}
+
+func TestIntegrationSelftest(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("integration tests require root")
+	}
+
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.OwnershipMarker = true
+	// The throwaway veth pair is isolated from the audit log and the tracked links.
+	config.AuditLogFile = filepath.Join(t.TempDir(), "audit.log")
+
+	d := &Doctor{}
+	d.selftest()
+	for _, finding := range d.findings {
+		assert.NotEqual(t, FindingFail, finding.Level, finding.Message)
+	}
+	require.NotEmpty(t, d.findings)
+	assert.Equal(t, "Throwaway veth pair is removed", d.findings[len(d.findings)-1].Message)
+
+	_, err := netlink.LinkByName(fmt.Sprintf("vethdvn%x", os.Getpid()))
+	assert.Error(t, err)
+	assert.NoFileExists(t, config.AuditLogFile)
+	assert.Empty(t, state.Containers())
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Socket of the journald native protocol.
+const journalSocket = "/run/systemd/journal/socket"
+
+// Identifier of the log records in the journal.
+const journalIdentifier = "docker-veth-namer"
+
+// Stable identifiers of the journal messages, to be queried with journalctl MESSAGE_ID=...
+const (
+	MessageIDLinkRenamed       = "ea270e33bb334749a5b85b67c1f997ca"
+	MessageIDLinkRenameFailed  = "626dd4bd91d2461b96ebecef0457c917"
+	MessageIDLinkRestored      = "56dc74b2dd9442a3902d922e549c56b0"
+	MessageIDLinkRestoreFailed = "e430c0cc27be4bf1b1b1cf23158948dd"
+)
+
+// Sends the log records to journald via the native protocol, with the record fields as the journal fields.
+type JournalHook struct {
+	mu   sync.Mutex
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// Returns the hook sending the log records to the journal socket.
+func newJournalHook(path string) (*JournalHook, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &JournalHook{conn: conn, addr: &net.UnixAddr{Name: path, Net: "unixgram"}}, nil
+}
+
+func (h *JournalHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Sends the record to the journal.
+func (h *JournalHook) Fire(entry *log.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.conn.WriteToUnix(journalMessage(entry), h.addr)
+	return err
+}
+
+// Closes the connection. Nil hook is ignored.
+func (h *JournalHook) Close() error {
+	if h == nil {
+		return nil
+	}
+	return h.conn.Close()
+}
+
+// Returns the syslog priority of the log level.
+func journalPriority(level log.Level) int {
+	switch level {
+	case log.PanicLevel:
+		return 0
+	case log.FatalLevel:
+		return 2
+	case log.ErrorLevel:
+		return 3
+	case log.WarnLevel:
+		return 4
+	case log.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// Returns the journal field name for the log record field: upper case letters, digits, and underscores,
+// not starting with underscore or digit, which are reserved or not allowed.
+func journalFieldName(name string) string {
+	var sb strings.Builder
+	for _, c := range strings.ToUpper(name) {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+
+	return strings.TrimLeft(sb.String(), "_0123456789")
+}
+
+// Appends the field in the journal native protocol format.
+// Values containing new lines are written with the explicit length.
+func appendJournalField(b *bytes.Buffer, name string, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(b, "%s=%s\n", name, value)
+		return
+	}
+
+	b.WriteString(name)
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// Returns the log record in the journal native protocol format.
+func journalMessage(entry *log.Entry) []byte {
+	var b bytes.Buffer
+	appendJournalField(&b, "MESSAGE", entry.Message)
+	appendJournalField(&b, "PRIORITY", fmt.Sprint(journalPriority(entry.Level)))
+	appendJournalField(&b, "SYSLOG_IDENTIFIER", journalIdentifier)
+
+	for _, key := range slices.Sorted(maps.Keys(entry.Data)) {
+		name := journalFieldName(key)
+		if len(name) == 0 || slices.Contains([]string{"MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER"}, name) {
+			continue
+		}
+		appendJournalField(&b, name, fmt.Sprint(entry.Data[key]))
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalMessage(t *testing.T) {
+	entry := log.NewEntry(log.StandardLogger()).WithFields(log.Fields{
+		logFieldContainerName: "/web",
+		logFieldIndex:         12,
+		logFieldMessageID:     MessageIDLinkRenamed,
+		"_private":            "x",
+	})
+	entry.Level = log.WarnLevel
+	entry.Message = "Link renamed"
+
+	assert.Equal(t, "MESSAGE=Link renamed\nPRIORITY=4\nSYSLOG_IDENTIFIER=docker-veth-namer\n"+
+		"PRIVATE=x\nCONTAINER_NAME=/web\nIFINDEX=12\nMESSAGE_ID="+MessageIDLinkRenamed+"\n",
+		string(journalMessage(entry)))
+
+	// Multiline value is written with the explicit length.
+	entry = log.NewEntry(log.StandardLogger())
+	entry.Message = "a\nb"
+	assert.Equal(t, "MESSAGE\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n", string(journalMessage(entry))[:len("MESSAGE\n")+8+4])
+}
+
+func TestJournalHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "socket")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer server.Close()
+
+	hook, err := newJournalHook(path)
+	require.NoError(t, err)
+	defer hook.Close()
+
+	entry := log.NewEntry(log.StandardLogger())
+	entry.Level = log.ErrorLevel
+	entry.Message = "Link renaming failed"
+	require.NoError(t, hook.Fire(entry))
+
+	buf := make([]byte, 4096)
+	n, err := server.Read(buf)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), "MESSAGE=Link renaming failed\nPRIORITY=3\n")
+
+	_, err = newJournalHook(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
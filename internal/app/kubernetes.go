@@ -0,0 +1,303 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Publish the mappings as the ConfigMap "docker-veth-namer-<node>", one key per host link.
+	KubernetesPublishConfigMap = "configmap"
+	// Publish the mappings as the annotation of the node.
+	KubernetesPublishAnnotation = "annotation"
+
+	// Labels of the containers and the pod sandboxes, set by kubelet.
+	kubernetesPodNameLabel      = "io.kubernetes.pod.name"
+	kubernetesPodNamespaceLabel = "io.kubernetes.pod.namespace"
+
+	// Annotation of the node holding the mappings.
+	kubernetesAnnotation = "docker-veth-namer/links"
+	// Field manager of the server-side apply.
+	kubernetesFieldManager = "docker-veth-namer"
+	kubernetesTimeout      = 10 * time.Second
+)
+
+var kubernetesPublishModes = map[string]bool{
+	KubernetesPublishConfigMap:  true,
+	KubernetesPublishAnnotation: true,
+}
+
+// Directory of the service account credentials mounted into the pod.
+var kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Pod owning the host link.
+type KubernetesLink struct {
+	Namespace     string `json:"namespace"`
+	Pod           string `json:"pod"`
+	ContainerLink string `json:"container_link"`
+	OriginalName  string `json:"original_name"`
+	Index         int    `json:"ifindex"`
+}
+
+// Returns the pod of the container named by kubelet as "k8s_<container>_<pod>_<namespace>_<uid>_<attempt>".
+// The network namespace of the pod belongs to its sandbox container, named "k8s_POD_...".
+func kubernetesPod(containerName string) (namespace string, pod string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(containerName, "/"), "_")
+	if len(parts) != 6 || parts[0] != "k8s" {
+		return "", "", false
+	}
+	return parts[3], parts[2], true
+}
+
+// Returns the pods by the host link name. The links of the containers not run by kubelet are omitted.
+// The pod is taken from the kubelet labels of the container, or from its name when not labeled.
+func kubernetesLinks(mappings []ContainerMapping) map[string]KubernetesLink {
+	links := map[string]KubernetesLink{}
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			namespace, pod := link.PodNamespace, link.Pod
+			if len(pod) == 0 {
+				var ok bool
+				if namespace, pod, ok = kubernetesPod(mapping.Name); !ok {
+					continue
+				}
+			}
+			links[link.Name] = KubernetesLink{
+				Namespace:     namespace,
+				Pod:           pod,
+				ContainerLink: link.ContainerLink,
+				OriginalName:  link.OriginalName,
+				Index:         link.Index,
+			}
+		}
+	}
+	return links
+}
+
+// Client of the Kubernetes API server, authenticated by the service account of the pod.
+type KubernetesClient struct {
+	url       string
+	tokenPath string
+	client    *http.Client
+}
+
+// Returns the client of the API server of the cluster running the pod.
+func newKubernetesClient() (*KubernetesClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return nil, errors.New("not running in a Kubernetes pod: KUBERNETES_SERVICE_HOST or KUBERNETES_SERVICE_PORT is not set")
+	}
+
+	ca, err := os.ReadFile(filepath.Join(kubernetesServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates in %s", filepath.Join(kubernetesServiceAccountDir, "ca.crt"))
+	}
+
+	return &KubernetesClient{
+		url:       "https://" + net.JoinHostPort(host, port),
+		tokenPath: filepath.Join(kubernetesServiceAccountDir, "token"),
+		client: &http.Client{
+			Timeout:   kubernetesTimeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+		},
+	}, nil
+}
+
+// Patches the object with the patch of the content type. The token is read on each request,
+// as kubelet rotates it.
+func (c *KubernetesClient) Patch(path string, contentType string, patch any) error {
+	token, err := os.ReadFile(c.tokenPath)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+string(bytes.TrimSpace(token)))
+	header.Set("Content-Type", contentType)
+	return jsonRequest(c.client, http.MethodPatch, c.url+path, header, patch, nil)
+}
+
+// Publishes the pods of the host links of the node to the Kubernetes API server,
+// as a ConfigMap or as an annotation of the node. The updates are rate-limited to one per interval.
+type KubernetesPublisher struct {
+	client    *KubernetesClient
+	mode      string
+	namespace string
+	node      string
+	interval  time.Duration
+	// Receives the latest mappings, older ones not yet published are replaced.
+	updates chan []ContainerMapping
+	done    chan struct{}
+
+	// Links published last time.
+	published map[string]KubernetesLink
+	// Links to be published.
+	desired map[string]KubernetesLink
+}
+
+func newKubernetesPublisher(mode string, namespace string, node string, interval time.Duration) (*KubernetesPublisher, error) {
+	client, err := newKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(node) == 0 {
+		if node, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &KubernetesPublisher{
+		client:    client,
+		mode:      mode,
+		namespace: namespace,
+		node:      node,
+		interval:  interval,
+		updates:   make(chan []ContainerMapping, 1),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *KubernetesPublisher) Publish(mappings []ContainerMapping) {
+	offerMappings(p.updates, mappings)
+}
+
+// Stops publishing. The published mappings are left in place.
+func (p *KubernetesPublisher) Close() {
+	close(p.done)
+}
+
+// Publishes the changed mappings at most once per interval.
+func (p *KubernetesPublisher) run() {
+	limiter := time.NewTimer(0)
+	defer limiter.Stop()
+	ready := false
+
+	for {
+		select {
+		case <-p.done:
+			return
+
+		case mappings := <-p.updates:
+			p.desired = kubernetesLinks(mappings)
+
+		case <-limiter.C:
+			ready = true
+		}
+
+		if ready && p.sync() {
+			ready = false
+			limiter.Reset(p.interval)
+		}
+	}
+}
+
+// Publishes the links when changed since the last time. Returns whether the API server was requested.
+// The links failed to be published are retried on the next sync.
+func (p *KubernetesPublisher) sync() bool {
+	if p.desired == nil || (p.published != nil && maps.Equal(p.published, p.desired)) {
+		return false
+	}
+
+	var err error
+	switch p.mode {
+	case KubernetesPublishConfigMap:
+		err = p.applyConfigMap()
+	case KubernetesPublishAnnotation:
+		err = p.annotateNode()
+	}
+	if err != nil {
+		log.Errorf("Cannot publish mappings to Kubernetes: %s", err)
+		return true
+	}
+
+	log.Debugf("Published %d host links of the pods to Kubernetes", len(p.desired))
+	p.published = p.desired
+	return true
+}
+
+// Applies the ConfigMap holding the links by their host link names. The keys of the removed links
+// are removed by the server-side apply.
+func (p *KubernetesPublisher) applyConfigMap() error {
+	data := map[string]string{}
+	for name, link := range p.desired {
+		value, err := json.Marshal(link)
+		if err != nil {
+			return err
+		}
+		data[name] = string(value)
+	}
+
+	name := "docker-veth-namer-" + p.node
+	configMap := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": p.namespace,
+			"labels": map[string]string{
+				"app.kubernetes.io/managed-by": kubernetesFieldManager,
+				"kubernetes.io/hostname":       p.node,
+			},
+		},
+		"data": data,
+	}
+
+	path := fmt.Sprintf("/api/v1/namespaces/%s/configmaps/%s?fieldManager=%s&force=true",
+		url.PathEscape(p.namespace), url.PathEscape(name), kubernetesFieldManager)
+	return p.client.Patch(path, "application/apply-patch+yaml", configMap)
+}
+
+// Sets the annotation of the node to the JSON object of the links by their host link names.
+func (p *KubernetesPublisher) annotateNode() error {
+	value, err := json.Marshal(p.desired)
+	if err != nil {
+		return err
+	}
+
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{kubernetesAnnotation: string(value)},
+		},
+	}
+	return p.client.Patch("/api/v1/nodes/"+url.PathEscape(p.node), "application/merge-patch+json", patch)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesPod(t *testing.T) {
+	namespace, pod, ok := kubernetesPod("/k8s_POD_web-7d4b9_default_0f3c2a1e-5b6d-4c8e-9f10-1a2b3c4d5e6f_0")
+	assert.True(t, ok)
+	assert.Equal(t, "default", namespace)
+	assert.Equal(t, "web-7d4b9", pod)
+
+	_, _, ok = kubernetesPod("/web")
+	assert.False(t, ok)
+	_, _, ok = kubernetesPod("/k8s_web")
+	assert.False(t, ok)
+}
+
+func TestKubernetesLinks(t *testing.T) {
+	mappings := []ContainerMapping{
+		// Pod sandbox of the CRI runtime, labeled by kubelet.
+		{ID: "1234", Name: "/web-7d4b9", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0",
+			LinkLabels: LinkLabels{Pod: "web-7d4b9", PodNamespace: "default"}}}},
+		// Sandbox container of kubelet on Docker, restored from the state without labels.
+		{ID: "4567", Name: "/k8s_POD_api-5f6a7_prod_0f3c2a1e_0", Links: []LinkState{{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vapi0"}}},
+		{ID: "7890", Name: "/db", Links: []LinkState{{Index: 14, ContainerLink: "eth0", OriginalName: "veth3", Name: "vdb0"}}},
+	}
+
+	assert.Equal(t, map[string]KubernetesLink{
+		"vweb0": {Namespace: "default", Pod: "web-7d4b9", ContainerLink: "eth0", OriginalName: "veth1", Index: 10},
+		"vapi0": {Namespace: "prod", Pod: "api-5f6a7", ContainerLink: "eth0", OriginalName: "veth2", Index: 12},
+	}, kubernetesLinks(mappings))
+}
+
+func TestKubernetesPublisherSync(t *testing.T) {
+	type request struct {
+		Method      string
+		Path        string
+		ContentType string
+		Body        map[string]any
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		requests = append(requests, request{r.Method, r.URL.RequestURI(), r.Header.Get("Content-Type"), body})
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("token\n"), 0600))
+	client := &KubernetesClient{url: server.URL, tokenPath: tokenPath, client: server.Client()}
+
+	mappings := []ContainerMapping{
+		{ID: "1234", Name: "/k8s_POD_web-7d4b9_default_0f3c2a1e_0", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}}},
+		{ID: "4567", Name: "/db", Links: []LinkState{{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0"}}},
+	}
+
+	p := &KubernetesPublisher{client: client, mode: KubernetesPublishConfigMap, namespace: "kube-system", node: "node1"}
+	p.desired = kubernetesLinks(mappings)
+	assert.True(t, p.sync())
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPatch, requests[0].Method)
+	assert.Equal(t, "/api/v1/namespaces/kube-system/configmaps/docker-veth-namer-node1?fieldManager=docker-veth-namer&force=true", requests[0].Path)
+	assert.Equal(t, "application/apply-patch+yaml", requests[0].ContentType)
+	assert.Equal(t, map[string]any{
+		"vweb0": `{"namespace":"default","pod":"web-7d4b9","container_link":"eth0","original_name":"veth1","ifindex":10}`,
+	}, requests[0].Body["data"])
+
+	// Unchanged links are not published again.
+	p.desired = kubernetesLinks(mappings)
+	assert.False(t, p.sync())
+	assert.Len(t, requests, 1)
+
+	p = &KubernetesPublisher{client: client, mode: KubernetesPublishAnnotation, node: "node1"}
+	p.desired = kubernetesLinks(mappings)
+	assert.True(t, p.sync())
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/api/v1/nodes/node1", requests[1].Path)
+	assert.Equal(t, "application/merge-patch+json", requests[1].ContentType)
+	assert.Equal(t, map[string]any{
+		"annotations": map[string]any{
+			"docker-veth-namer/links": `{"vweb0":{"namespace":"default","pod":"web-7d4b9","container_link":"eth0","original_name":"veth1","ifindex":10}}`,
+		},
+	}, requests[1].Body["metadata"])
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	consul "github.com/hashicorp/consul/api"
+	log "github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcd "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// Timeout of the key-value store request.
+var kvTimeout = 5 * time.Second
+
+// Returned by KVStore.RenewSession when the session has expired, and its keys were removed.
+var errKVSessionExpired = errors.New("session has expired")
+
+// Key-value store receiving the host link mappings.
+type KVStore interface {
+	// Starts a session expiring after the TTL unless renewed. The keys put within the session are removed when it expires.
+	CreateSession(ttl time.Duration) (string, error)
+	// Extends the session by its TTL. Returns errKVSessionExpired when the session has expired.
+	RenewSession(session string) error
+	// Puts the key bound to the session.
+	Put(session string, key string, value []byte) error
+	Delete(key string) error
+	Close() error
+}
+
+// Makes the key-value store of the URL: "consul://[:token@]host[:port]", or "etcd://host[:port]".
+func newKVStore(rawURL string) (KVStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "consul":
+		cfg := consul.DefaultConfig()
+		cfg.Address = hostWithDefaultPort(u, 8500)
+		cfg.Scheme = "http"
+		cfg.HttpClient = &http.Client{Timeout: kvTimeout}
+		if u.User != nil {
+			cfg.Token, _ = u.User.Password()
+		}
+		client, err := consul.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &ConsulStore{client: client}, nil
+
+	case "etcd":
+		client, err := etcd.New(etcd.Config{
+			Endpoints:   []string{hostWithDefaultPort(u, 2379)},
+			DialTimeout: kvTimeout,
+			Logger:      zap.NewNop(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &EtcdStore{client: client, timeout: kvTimeout}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key-value store: %q", u.Scheme)
+	}
+}
+
+// Returns the host and port of the URL, with the port defaulted.
+func hostWithDefaultPort(u *url.URL, port int) string {
+	if len(u.Port()) > 0 {
+		return u.Host
+	}
+	return u.Hostname() + ":" + strconv.Itoa(port)
+}
+
+// Unsuccessful HTTP response of the JSON request.
+type HTTPStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return e.Message
+}
+
+// Sends the HTTP request with the JSON or raw body, and decodes the JSON reply unless it is nil.
+func jsonRequest(client *http.Client, method string, url string, header http.Header, body any, reply any) error {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if _, raw := body.([]byte); body != nil && !raw && len(req.Header.Get("Content-Type")) == 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("%s %s: %s: %s", method, req.URL.Path, resp.Status, strings.TrimSpace(string(message))),
+		}
+	}
+	if reply == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// Consul KV store. The keys are acquired by a session with the delete behavior,
+// so they are removed when the session expires.
+type ConsulStore struct {
+	client *consul.Client
+}
+
+func (s *ConsulStore) CreateSession(ttl time.Duration) (string, error) {
+	id, _, err := s.client.Session().Create(&consul.SessionEntry{
+		Name:     "docker-veth-namer",
+		TTL:      ttl.String(),
+		Behavior: consul.SessionBehaviorDelete,
+		// The keys of the expired session are acquired again by the restarted daemon without delay.
+		LockDelay: time.Millisecond,
+	}, nil)
+	return id, err
+}
+
+func (s *ConsulStore) RenewSession(session string) error {
+	entry, _, err := s.client.Session().Renew(session, nil)
+	if err != nil {
+		return err
+	}
+	// Unknown session is reported with no entry.
+	if entry == nil {
+		return errKVSessionExpired
+	}
+	return nil
+}
+
+func (s *ConsulStore) Put(session string, key string, value []byte) error {
+	acquired, _, err := s.client.KV().Acquire(&consul.KVPair{Key: key, Value: value, Session: session}, nil)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return fmt.Errorf("key is locked by another session: %s", key)
+	}
+	return nil
+}
+
+func (s *ConsulStore) Delete(key string) error {
+	_, err := s.client.KV().Delete(key, nil)
+	return err
+}
+
+func (s *ConsulStore) Close() error {
+	return nil
+}
+
+// etcd v3 store. The keys are attached to a lease, so they are removed when the lease expires.
+type EtcdStore struct {
+	client  *etcd.Client
+	timeout time.Duration
+}
+
+// Returns the context of a request.
+func (s *EtcdStore) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+func (s *EtcdStore) CreateSession(ttl time.Duration) (string, error) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	lease, err := s.client.Grant(ctx, int64(ttl.Seconds()))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(int64(lease.ID), 10), nil
+}
+
+func (s *EtcdStore) RenewSession(session string) error {
+	id, err := strconv.ParseInt(session, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	_, err = s.client.KeepAliveOnce(ctx, etcd.LeaseID(id))
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return errKVSessionExpired
+	}
+	return err
+}
+
+func (s *EtcdStore) Put(session string, key string, value []byte) error {
+	id, err := strconv.ParseInt(session, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	_, err = s.client.Put(ctx, key, string(value), etcd.WithLease(etcd.LeaseID(id)))
+	return err
+}
+
+func (s *EtcdStore) Delete(key string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	_, err := s.client.Delete(ctx, key)
+	return err
+}
+
+func (s *EtcdStore) Close() error {
+	return s.client.Close()
+}
+
+// Publishes the host link mappings to the key-value store under the host-scoped prefix,
+// one key per renamed host link: "<prefix>/<host name>/<host link name>", holding the mapping record as JSON.
+// The keys are bound to a session renewed periodically, so the keys of a dead host expire after the TTL.
+type KVPublisher struct {
+	store  KVStore
+	prefix string
+	ttl    time.Duration
+	// Receives the latest mappings, older ones not yet published are replaced.
+	updates chan []ContainerMapping
+	done    chan struct{}
+
+	session string
+	// Values of the keys put within the session.
+	published map[string]string
+	// Values of the keys to be published.
+	desired map[string]string
+}
+
+func newKVPublisher(rawURL string, prefix string, ttl time.Duration) (*KVPublisher, error) {
+	store, err := newKVStore(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &KVPublisher{
+		store:   store,
+		prefix:  strings.Trim(prefix, "/") + "/" + host,
+		ttl:     ttl,
+		updates: make(chan []ContainerMapping, 1),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *KVPublisher) Publish(mappings []ContainerMapping) {
+	offerMappings(p.updates, mappings)
+}
+
+// Stops publishing. The published keys expire after the TTL, unless another publisher takes over.
+func (p *KVPublisher) Close() {
+	close(p.done)
+}
+
+// Publishes the mappings, and renews the session at a third of the TTL.
+func (p *KVPublisher) run() {
+	ticker := time.NewTicker(p.ttl / 3)
+	defer ticker.Stop()
+
+	defer p.store.Close()
+
+	for {
+		select {
+		case <-p.done:
+			return
+
+		case mappings := <-p.updates:
+			p.desired = kvEntries(p.prefix, mappings)
+			p.sync()
+
+		case <-ticker.C:
+			if len(p.session) > 0 {
+				err := p.store.RenewSession(p.session)
+				if errors.Is(err, errKVSessionExpired) {
+					log.Warnf("Key-value store session has expired, publishing the mappings again")
+					p.session = ""
+				} else if err != nil {
+					log.Errorf("Cannot renew key-value store session: %s", err)
+					continue
+				}
+			}
+			p.sync()
+		}
+	}
+}
+
+// Puts the changed keys, and deletes the removed ones, starting a session when there is none.
+// The keys failed to be published are retried on the next sync.
+func (p *KVPublisher) sync() {
+	if p.desired == nil {
+		return
+	}
+
+	if len(p.session) == 0 {
+		session, err := p.store.CreateSession(p.ttl)
+		if err != nil {
+			log.Errorf("Cannot create key-value store session: %s", err)
+			return
+		}
+		p.session = session
+		p.published = map[string]string{}
+	}
+
+	for key, value := range p.desired {
+		if published, ok := p.published[key]; ok && published == value {
+			continue
+		}
+		if err := p.store.Put(p.session, key, []byte(value)); err != nil {
+			log.Errorf("Cannot put key to key-value store: %s: %s", key, err)
+			continue
+		}
+		p.published[key] = value
+	}
+
+	for key := range p.published {
+		if _, ok := p.desired[key]; ok {
+			continue
+		}
+		if err := p.store.Delete(key); err != nil {
+			log.Errorf("Cannot delete key from key-value store: %s: %s", key, err)
+			continue
+		}
+		delete(p.published, key)
+	}
+}
+
+// Returns the keys and values of the mappings.
+func kvEntries(prefix string, mappings []ContainerMapping) map[string]string {
+	entries := map[string]string{}
+	for _, record := range mappingRecords(mappings) {
+		value, err := json.Marshal(record)
+		if err != nil {
+			log.Errorf("json.Marshal to bytes failed: %s", err)
+			continue
+		}
+		entries[prefix+"/"+record.Name] = string(value)
+	}
+	return entries
+}
@@ -0,0 +1,297 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"google.golang.org/grpc"
+)
+
+// Key-value store keeping the keys in memory.
+type memoryKVStore struct {
+	sessions int
+	keys     map[string]string
+	puts     int
+}
+
+func (s *memoryKVStore) CreateSession(ttl time.Duration) (string, error) {
+	s.sessions++
+	s.keys = map[string]string{}
+	return "session", nil
+}
+
+func (s *memoryKVStore) RenewSession(session string) error {
+	return nil
+}
+
+func (s *memoryKVStore) Put(session string, key string, value []byte) error {
+	s.puts++
+	s.keys[key] = string(value)
+	return nil
+}
+
+func (s *memoryKVStore) Delete(key string) error {
+	delete(s.keys, key)
+	return nil
+}
+
+func (s *memoryKVStore) Close() error {
+	return nil
+}
+
+func TestKVPublisherSync(t *testing.T) {
+	store := &memoryKVStore{}
+	p := &KVPublisher{store: store, prefix: "dvn/node1", ttl: time.Minute}
+
+	mappings := []ContainerMapping{
+		{ID: "1234", Name: "/web", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}}},
+		{ID: "4567", Name: "/db", Links: []LinkState{{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0"}}},
+	}
+	p.desired = kvEntries(p.prefix, mappings)
+	p.sync()
+
+	assert.Equal(t, 1, store.sessions)
+	require.Len(t, store.keys, 2)
+	assert.JSONEq(t, `{"name":"vweb0","original_name":"veth1","ifindex":10,"container_id":"1234","container_name":"web","container_link":"eth0","image":"","network":""}`,
+		store.keys["dvn/node1/vweb0"])
+
+	// Unchanged keys are not put again, removed ones are deleted.
+	p.desired = kvEntries(p.prefix, mappings[1:])
+	p.sync()
+	assert.Equal(t, 2, store.puts)
+	assert.Equal(t, []string{"dvn/node1/vdb0"}, mapKeys(store.keys))
+
+	// All keys are put again within a new session.
+	p.session = ""
+	p.sync()
+	assert.Equal(t, 2, store.sessions)
+	assert.Equal(t, []string{"dvn/node1/vdb0"}, mapKeys(store.keys))
+}
+
+func mapKeys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestConsulStore(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Consul-Token")+" "+string(body))
+		switch {
+		case r.URL.Path == "/v1/session/create":
+			io.WriteString(w, `{"ID":"abc"}`)
+		case strings.HasPrefix(r.URL.Path, "/v1/session/renew/"):
+			http.Error(w, "session not found", http.StatusNotFound)
+		case r.Method == http.MethodPut:
+			io.WriteString(w, "true")
+		}
+	}))
+	defer server.Close()
+
+	store, err := newKVStore("consul://:secret@" + strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	session, err := store.CreateSession(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", session)
+	require.NoError(t, store.Put(session, "dvn/node1/vweb0", []byte("{}")))
+	require.NoError(t, store.Delete("dvn/node1/vweb0"))
+	assert.ErrorIs(t, store.RenewSession(session), errKVSessionExpired)
+
+	require.Len(t, requests, 4)
+	assert.Equal(t, "PUT /v1/session/create secret ", requests[0][:len("PUT /v1/session/create secret ")])
+	assert.JSONEq(t, `{"Behavior":"delete","LockDelay":"1ms","Name":"docker-veth-namer","TTL":"1m0s"}`,
+		strings.TrimPrefix(requests[0], "PUT /v1/session/create secret "))
+	assert.Equal(t, `PUT /v1/kv/dvn/node1/vweb0?acquire=abc secret {}`, requests[1])
+	assert.Equal(t, `DELETE /v1/kv/dvn/node1/vweb0 secret `, requests[2])
+	assert.Equal(t, `PUT /v1/session/renew/abc secret `, requests[3])
+}
+
+func TestConsulStoreErrors(t *testing.T) {
+	defer func(timeout time.Duration) { kvTimeout = timeout }(kvTimeout)
+	kvTimeout = 200 * time.Millisecond
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/session/create":
+			http.Error(w, "ACL not found", http.StatusForbidden)
+		case r.Method == http.MethodDelete:
+			<-release
+		case r.Method == http.MethodPut:
+			io.WriteString(w, "false")
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	store, err := newKVStore("consul://" + strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.CreateSession(time.Minute)
+	assert.ErrorContains(t, err, "ACL not found")
+	assert.ErrorContains(t, store.Put("abc", "dvn/node1/vweb0", []byte("{}")), "locked by another session")
+
+	start := time.Now()
+	assert.Error(t, store.Delete("dvn/node1/vweb0"))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// etcd server keeping the keys and leases in memory.
+type fakeEtcdServer struct {
+	etcdserverpb.UnimplementedKVServer
+	etcdserverpb.UnimplementedLeaseServer
+
+	mu     sync.Mutex
+	keys   map[string]string
+	leases map[int64]bool
+	// Delays the replies to the puts when set.
+	stall chan struct{}
+}
+
+func (s *fakeEtcdServer) Put(ctx context.Context, r *etcdserverpb.PutRequest) (*etcdserverpb.PutResponse, error) {
+	if s.stall != nil {
+		select {
+		case <-s.stall:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.leases[r.Lease] {
+		return nil, rpctypes.ErrGRPCLeaseNotFound
+	}
+	s.keys[string(r.Key)] = string(r.Value)
+	return &etcdserverpb.PutResponse{Header: &etcdserverpb.ResponseHeader{}}, nil
+}
+
+func (s *fakeEtcdServer) DeleteRange(ctx context.Context, r *etcdserverpb.DeleteRangeRequest) (*etcdserverpb.DeleteRangeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, string(r.Key))
+	return &etcdserverpb.DeleteRangeResponse{Header: &etcdserverpb.ResponseHeader{}}, nil
+}
+
+func (s *fakeEtcdServer) LeaseGrant(ctx context.Context, r *etcdserverpb.LeaseGrantRequest) (*etcdserverpb.LeaseGrantResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases[7587] = true
+	return &etcdserverpb.LeaseGrantResponse{Header: &etcdserverpb.ResponseHeader{}, ID: 7587, TTL: r.TTL}, nil
+}
+
+func (s *fakeEtcdServer) LeaseKeepAlive(stream etcdserverpb.Lease_LeaseKeepAliveServer) error {
+	for {
+		r, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		// Expired lease is reported with no TTL.
+		var ttl int64
+		if s.leases[r.ID] {
+			ttl = 60
+		}
+		s.mu.Unlock()
+
+		if err := stream.Send(&etcdserverpb.LeaseKeepAliveResponse{Header: &etcdserverpb.ResponseHeader{}, ID: r.ID, TTL: ttl}); err != nil {
+			return err
+		}
+	}
+}
+
+// Starts the etcd server. Returns its address.
+func startFakeEtcd(t *testing.T, s *fakeEtcdServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	etcdserverpb.RegisterKVServer(server, s)
+	etcdserverpb.RegisterLeaseServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestEtcdStore(t *testing.T) {
+	etcd := &fakeEtcdServer{keys: map[string]string{}, leases: map[int64]bool{}}
+	store, err := newKVStore("etcd://" + startFakeEtcd(t, etcd))
+	require.NoError(t, err)
+	defer store.Close()
+
+	session, err := store.CreateSession(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "7587", session)
+	require.NoError(t, store.Put(session, "dvn/node1/vweb0", []byte("{}")))
+	require.NoError(t, store.Put(session, "dvn/node1/vdb0", []byte("{}")))
+	require.NoError(t, store.Delete("dvn/node1/vweb0"))
+	assert.Equal(t, map[string]string{"dvn/node1/vdb0": "{}"}, etcd.keys)
+	require.NoError(t, store.RenewSession(session))
+
+	etcd.mu.Lock()
+	etcd.leases = map[int64]bool{}
+	etcd.mu.Unlock()
+	assert.ErrorIs(t, store.RenewSession(session), errKVSessionExpired)
+	assert.Error(t, store.Put(session, "dvn/node1/vweb0", []byte("{}")))
+}
+
+func TestEtcdStoreTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { kvTimeout = timeout }(kvTimeout)
+	kvTimeout = 200 * time.Millisecond
+
+	etcd := &fakeEtcdServer{keys: map[string]string{}, leases: map[int64]bool{7587: true}, stall: make(chan struct{})}
+	defer close(etcd.stall)
+	store, err := newKVStore("etcd://" + startFakeEtcd(t, etcd))
+	require.NoError(t, err)
+	defer store.Close()
+
+	start := time.Now()
+	assert.ErrorIs(t, store.Put("7587", "dvn/node1/vweb0", []byte("{}")), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The server is not reachable.
+	store, err = newKVStore("etcd://127.0.0.1:1")
+	require.NoError(t, err)
+	defer store.Close()
+	_, err = store.CreateSession(time.Minute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Delay before an unknown veth link triggers resync, to let the corresponding Docker event be processed first.
+const linkEventResyncDelay = 2 * time.Second
+
+// Watches host links appearing without a mapping, which may happen when Docker events are lost or missed.
+type LinkWatcher struct {
+	updates chan netlink.LinkUpdate
+	done    chan struct{}
+	// Unknown links pending for resync by index.
+	pending map[int]string
+	// Links left unknown after resync. Those are likely not owned by Docker containers, and are ignored further.
+	// The time each one was ignored at is kept to evict the oldest ones beyond max_cache_entries.
+	ignored map[int]time.Time
+	timer   *time.Timer
+}
+
+// Subscribes to host link updates.
+func newLinkWatcher() (*LinkWatcher, error) {
+	w := &LinkWatcher{
+		updates: make(chan netlink.LinkUpdate, 64),
+		done:    make(chan struct{}),
+		pending: make(map[int]string),
+		ignored: make(map[int]time.Time),
+	}
+
+	err := netlink.LinkSubscribeWithOptions(w.updates, w.done, netlink.LinkSubscribeOptions{
+		ErrorCallback: func(err error) {
+			log.Errorf("netlink.LinkSubscribe failed: %s", err)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Stops the link updates subscription.
+func (w *LinkWatcher) Close() {
+	if w == nil {
+		return
+	}
+
+	close(w.done)
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// Returns the channel of link updates. Nil watcher returns nil channel blocking forever.
+func (w *LinkWatcher) Updates() <-chan netlink.LinkUpdate {
+	if w == nil {
+		return nil
+	}
+	return w.updates
+}
+
+// Returns the channel firing when resync is due. Nil channel is returned when nothing is pending.
+func (w *LinkWatcher) ResyncDue() <-chan time.Time {
+	if w == nil || w.timer == nil {
+		return nil
+	}
+	return w.timer.C
+}
+
+// Tracks the veth link when it appears without a mapping.
+func (w *LinkWatcher) HandleUpdate(update netlink.LinkUpdate) {
+	index := int(update.Index)
+	if traceNetlink {
+		traceLinkUpdate(update)
+	}
+
+	if update.Header.Type == unix.RTM_DELLINK {
+		delete(w.pending, index)
+		delete(w.ignored, index)
+		flapDetector.Forget(index)
+		return
+	}
+
+	if update.Link == nil || update.Link.Type() != "veth" {
+		return
+	}
+
+	if cs, link, ok := state.FindLink(index); ok {
+		if name := update.Link.Attrs().Name; name != link.Name {
+			// Renamed by someone else.
+			trackedLinkLogger(cs.ID, cs.Name, link).WithFields(renameFields(link.Name, name)).
+				Debugf("Tracked link was renamed externally: %s %s: %s => %s", cs.Name, link.ContainerLink, link.Name, name)
+			recordLinkRename(cs.ID, cs.Name, link, "someone else")
+		}
+		return
+	}
+
+	if _, ok := w.ignored[index]; ok {
+		return
+	}
+
+	if _, ok := w.pending[index]; !ok {
+		log.WithFields(log.Fields{logFieldIndex: index, logFieldLink: update.Link.Attrs().Name}).
+			Debugf("Unknown veth link appeared: %d %s", index, update.Link.Attrs().Name)
+	}
+	w.pending[index] = update.Link.Attrs().Name
+
+	if w.timer == nil {
+		w.timer = time.NewTimer(linkEventResyncDelay)
+	}
+}
+
+// Returns the pending links which still exist and are still unknown, and resets the pending list.
+func (w *LinkWatcher) TakePending() map[int]string {
+	w.timer = nil
+
+	unknown := make(map[int]string)
+	for index := range w.pending {
+		if state.TracksLink(index) {
+			continue
+		}
+
+		link, err := nlLinkByIndex(index)
+		if err != nil {
+			// The link is gone.
+			continue
+		}
+
+		unknown[index] = link.Attrs().Name
+	}
+	clear(w.pending)
+
+	return unknown
+}
+
+// Ignores the links which were not picked up by resync.
+func (w *LinkWatcher) ResyncDone(unknown map[int]string) {
+	for index, name := range unknown {
+		if !state.TracksLink(index) {
+			log.WithFields(log.Fields{logFieldIndex: index, logFieldLink: name}).
+				Debugf("Link does not belong to a container, ignoring: %d %s", index, name)
+			w.ignored[index] = time.Now()
+		}
+	}
+}
+
+// Forgets the oldest ignored links beyond the maximum number. Those are picked up by a resync again when updated.
+// Returns the number of the forgotten links.
+func (w *LinkWatcher) Prune(maxEntries int) int {
+	if w == nil {
+		return 0
+	}
+	return evictOldest(w.ignored, maxEntries, func(ignoredAt time.Time) time.Time { return ignoredAt })
+}
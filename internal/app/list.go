@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// Mapping between the container link and the host link.
+type LinkMapping struct {
+	ContainerID   string `json:"container_id,omitempty"`
+	ContainerName string `json:"container_name"`
+	ContainerLink string `json:"container_link"`
+	Index         int    `json:"ifindex,omitempty"`
+	// Current name of the host link.
+	Name string `json:"name,omitempty"`
+	// Name the host link is assigned by the program.
+	TargetName string `json:"target_name"`
+}
+
+// Returns the mappings of the container links, or nil if the container has no own network namespace.
+func containerLinkMappings(rt ContainerRuntime, inspect container.InspectResponse) ([]LinkMapping, error) {
+	sandboxKey, err := rt.ResolveNetns(inspect)
+	if err != nil {
+		return nil, nil
+	}
+
+	containerLinks, err := listContainerLinks(sandboxKey)
+	if err != nil {
+		return nil, fmt.Errorf("listing container links failed: %w", err)
+	}
+
+	mappings := make([]LinkMapping, 0, len(containerLinks))
+	for _, containerLink := range containerLinks {
+		mapping := LinkMapping{
+			ContainerID:   inspect.ID,
+			ContainerName: strings.TrimPrefix(inspect.Name, "/"),
+			ContainerLink: containerLink.Name,
+			Index:         containerLink.ParentIndex,
+			TargetName:    makeLinkName(inspect.Name, containerLink.Name),
+		}
+
+		link, err := nlLinkByIndex(containerLink.ParentIndex)
+		if err != nil {
+			linkLogger(mapping.ContainerID, inspect.Name, mapping.ContainerLink, mapping.Index, "").Errorf("netlink.LinkByIndex failed: %s", err)
+		} else {
+			mapping.Name = link.Attrs().Name
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// Returns the mappings of the links of the running containers, ordered by container name.
+func runningLinkMappings(ctx context.Context, rt ContainerRuntime) []LinkMapping {
+	var mappings []LinkMapping
+	for _, inspect := range inspectRunningContainers(ctx, rt, filters.NewArgs()) {
+		containerMappings, err := containerLinkMappings(rt, inspect)
+		if err != nil {
+			containerLogger(inspect.ID, inspect.Name).Errorf("Cannot list links for container: %s %s: %s", inspect.Name, inspect.ID, err)
+			continue
+		}
+		mappings = append(mappings, containerMappings...)
+	}
+	return mappings
+}
+
+// Prints the mappings as a table.
+func printLinkMappings(w io.Writer, mappings []LinkMapping) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tID\tLINK\tIFINDEX\tHOST LINK\tTARGET NAME\tSTATUS")
+
+	for _, m := range mappings {
+		fmt.Fprintf(tw, "%s\t%.12s\t%s\t%d\t%s\t%s\t%s\n",
+			m.ContainerName, m.ContainerID, m.ContainerLink, m.Index, m.Name, m.TargetName, m.Status())
+	}
+
+	return tw.Flush()
+}
+
+// Returns the status of the host link: renamed, missing, invalid (name cannot be made), or pending.
+func (m LinkMapping) Status() string {
+	switch {
+	case len(m.Name) == 0:
+		return "missing"
+	case len(m.TargetName) == 0:
+		return "invalid"
+	case m.Name != m.TargetName:
+		return "pending"
+	}
+	return "renamed"
+}
+
+// Returns the mappings of the host links not named according to the configuration.
+func mismatchedLinkMappings(mappings []LinkMapping) []LinkMapping {
+	mismatches := []LinkMapping{}
+	for _, m := range mappings {
+		if m.Status() != "renamed" {
+			mismatches = append(mismatches, m)
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintLinkMappings(t *testing.T) {
+	var b strings.Builder
+	err := printLinkMappings(&b, []LinkMapping{
+		{ContainerID: "0123456789abcdef", ContainerName: "web", ContainerLink: "eth0", Index: 10, Name: "vweb0", TargetName: "vweb0"},
+		{ContainerID: "fedcba9876543210", ContainerName: "db", ContainerLink: "eth1", Index: 11, Name: "veth123", TargetName: "vdb1"},
+		{ContainerID: "fedcba9876543210", ContainerName: "db", ContainerLink: "eth2", Index: 12, TargetName: "vdb2"},
+	})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, []string{"CONTAINER", "ID", "LINK", "IFINDEX", "HOST", "LINK", "TARGET", "NAME", "STATUS"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"web", "0123456789ab", "eth0", "10", "vweb0", "vweb0", "renamed"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"db", "fedcba987654", "eth1", "11", "veth123", "vdb1", "pending"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"db", "fedcba987654", "eth2", "12", "vdb2", "missing"}, strings.Fields(lines[3]))
+}
+
+func TestMismatchedLinkMappings(t *testing.T) {
+	mappings := []LinkMapping{
+		{ContainerName: "web", ContainerLink: "eth0", Index: 10, Name: "vweb0", TargetName: "vweb0"},
+		{ContainerName: "web", ContainerLink: "eth1", Index: 11, Name: "veth1", TargetName: "vweb1"},
+		{ContainerName: "db", ContainerLink: "eth0", Index: 12, TargetName: "vdb0"},
+	}
+
+	mismatches := mismatchedLinkMappings(mappings)
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "pending", mismatches[0].Status())
+	assert.Equal(t, "missing", mismatches[1].Status())
+
+	assert.Empty(t, mismatchedLinkMappings(mappings[:1]))
+}
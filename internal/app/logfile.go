@@ -0,0 +1,158 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Log file rotated once it grows over the maximum size, or gets older than the maximum age.
+// The rotated files are suffixed with ".1" (the most recent) to ".<max backups>".
+type RotatingFile struct {
+	mu   sync.Mutex
+	path string
+	// Zero disables the rotation by size.
+	maxSize int64
+	// Zero disables the rotation by age.
+	maxAge     time.Duration
+	maxBackups int
+
+	file     *os.File
+	size     int64
+	openTime time.Time
+}
+
+func newRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Opens the log file for appending. The age of the existing file is counted from its modification time.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openTime = time.Now()
+	if f.size > 0 {
+		f.openTime = info.ModTime()
+	}
+	return nil
+}
+
+// Writes the record, rotating the file before if needed.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && f.rotationDue(int64(len(p)), time.Now()) {
+		if err := f.rotate(); err != nil {
+			// Keep writing to the current file.
+			fmt.Fprintf(os.Stderr, "Cannot rotate log file: %s: %s\n", f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Returns whether the file is to be rotated before writing the record of the size.
+func (f *RotatingFile) rotationDue(recordSize int64, now time.Time) bool {
+	if f.maxSize > 0 && f.size+recordSize > f.maxSize {
+		return true
+	}
+	return f.maxAge > 0 && now.Sub(f.openTime) >= f.maxAge
+}
+
+// Shifts the backups, dropping the oldest one, and starts the new file.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", f.path, i)
+	}
+
+	if f.maxBackups > 0 {
+		os.Remove(backup(f.maxBackups))
+		for i := f.maxBackups - 1; i > 0; i-- {
+			os.Rename(backup(i), backup(i+1))
+		}
+		if err := os.Rename(f.path, backup(1)); err != nil {
+			f.open()
+			return err
+		}
+	} else if err := os.Remove(f.path); err != nil {
+		f.open()
+		return err
+	}
+
+	return f.open()
+}
+
+// Closes the file. Nil file is ignored.
+func (f *RotatingFile) Close() error {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
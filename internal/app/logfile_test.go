@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "docker-veth-namer.log")
+	f, err := newRotatingFile(path, 10, 0, 2)
+	require.NoError(t, err)
+	defer f.Close()
+
+	for _, record := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(record))
+		require.NoError(t, err)
+	}
+
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+
+	// Rotation by age.
+	f.maxSize = 0
+	f.maxAge = time.Hour
+	assert.False(t, f.rotationDue(1, f.openTime.Add(time.Minute)))
+	assert.True(t, f.rotationDue(1, f.openTime.Add(time.Hour)))
+}
@@ -0,0 +1,265 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var logFormats = []string{LogFormatText, LogFormatJSON}
+
+// Fields of the structured log records.
+const (
+	logFieldContainerID   = "container_id"
+	logFieldContainerName = "container_name"
+	logFieldContainerLink = "container_link"
+	// Index of the host link.
+	logFieldIndex = "ifindex"
+	// Current name of the host link.
+	logFieldLink = "link"
+	// Name assigned to the host link by Docker.
+	logFieldOriginalName = "original_name"
+	// Names of the host link before and after renaming.
+	logFieldOldName = "old_name"
+	logFieldNewName = "new_name"
+	// Action of the Docker event being handled.
+	logFieldEventAction = "event_action"
+	// Stable identifier of the message, passed as MESSAGE_ID to the journal.
+	logFieldMessageID = "message_id"
+	// Number of the suppressed repetitions of the record.
+	logFieldRepeated = "repeated"
+)
+
+var (
+	// Log level specified on the command line, overriding the configuration. Empty when not specified.
+	logLevelOverride string
+	// Log format specified on the command line, overriding the configuration. Empty when not specified.
+	logFormatOverride string
+	// Log file specified on the command line, overriding the configuration. Empty when not specified.
+	logFileOverride string
+	// Whether logging to journald is requested on the command line, overriding the configuration.
+	logJournaldOverride bool
+	// Whether colored log output is disabled on the command line, or by the NO_COLOR environment variable.
+	logNoColor bool
+
+	// Current log file. Nil when logging to stderr.
+	logFile *RotatingFile
+	// Current journald hook. Nil when not logging to journald.
+	journalHook *JournalHook
+)
+
+// Validates the log format.
+func parseLogFormat(format string) error {
+	if !slices.Contains(logFormats, format) {
+		return fmt.Errorf("unknown log format: %q, expected one of %v", format, logFormats)
+	}
+	return nil
+}
+
+// Returns whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	_, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS)
+	return err == nil
+}
+
+// Returns the text formatter: colored and human-oriented when the log is written to a terminal, plain otherwise.
+func textFormatter(out io.Writer) *log.TextFormatter {
+	if logNoColor || !isTerminal(out) {
+		return &log.TextFormatter{DisableColors: true}
+	}
+	return &log.TextFormatter{ForceColors: true, FullTimestamp: true, TimestampFormat: time.TimeOnly}
+}
+
+// Sets the log format from the command line, or from the configuration.
+// JSON records have stable field names, to be queried by log collectors.
+// Must be called after the log output is set, as the text format depends on it.
+func applyLogFormat() {
+	format := config.LogFormat
+	if len(logFormatOverride) > 0 {
+		format = logFormatOverride
+	}
+
+	switch format {
+	case LogFormatJSON:
+		log.SetFormatter(&log.JSONFormatter{
+			FieldMap: log.FieldMap{
+				log.FieldKeyTime:  "time",
+				log.FieldKeyLevel: "level",
+				log.FieldKeyMsg:   "msg",
+			},
+		})
+	default:
+		log.SetFormatter(textFormatter(log.StandardLogger().Out))
+	}
+}
+
+// Returns the logger with the fields identifying the container.
+func containerLogger(containerID string, containerName string) *log.Entry {
+	return log.WithFields(log.Fields{
+		logFieldContainerID:   containerID,
+		logFieldContainerName: containerName,
+	})
+}
+
+// Returns the logger with the fields identifying the link of the container.
+func linkLogger(containerID string, containerName string, containerLink string, index int, link string) *log.Entry {
+	return log.WithFields(log.Fields{
+		logFieldContainerID:   containerID,
+		logFieldContainerName: containerName,
+		logFieldContainerLink: containerLink,
+		logFieldIndex:         index,
+		logFieldLink:          link,
+	})
+}
+
+// Returns the logger with the fields identifying the tracked link of the container.
+func trackedLinkLogger(containerID string, containerName string, link LinkState) *log.Entry {
+	return linkLogger(containerID, containerName, link.ContainerLink, link.Index, link.Name).
+		WithField(logFieldOriginalName, link.OriginalName)
+}
+
+// Returns the fields of the host link renaming.
+func renameFields(oldName string, newName string) log.Fields {
+	return log.Fields{logFieldOldName: oldName, logFieldNewName: newName}
+}
+
+// Sets the log level from the command line, or from the configuration.
+func applyLogLevel() {
+	levelName := config.LogLevel
+	if len(logLevelOverride) > 0 {
+		levelName = logLevelOverride
+	}
+
+	// The level is validated on load.
+	level, err := log.ParseLevel(levelName)
+	if err != nil {
+		log.Errorf("Invalid log level: %s", err)
+		return
+	}
+	log.SetLevel(level)
+}
+
+// Directs the log to the file from the command line, or from the configuration. Empty path selects stderr.
+func applyLogOutput() {
+	path := config.LogFile
+	if len(logFileOverride) > 0 {
+		path = logFileOverride
+	}
+
+	maxSize := int64(config.LogFileMaxSize) << 20
+	if logFile != nil && logFile.path == path {
+		logFile.mu.Lock()
+		logFile.maxSize, logFile.maxAge, logFile.maxBackups = maxSize, config.LogFileMaxAge, config.LogFileMaxBackups
+		logFile.mu.Unlock()
+		applyLogJournal()
+		return
+	}
+
+	prev := logFile
+	logFile = nil
+	if len(path) == 0 {
+		log.SetOutput(os.Stderr)
+	} else {
+		var err error
+		logFile, err = newRotatingFile(path, maxSize, config.LogFileMaxAge, config.LogFileMaxBackups)
+		if err != nil {
+			log.SetOutput(os.Stderr)
+			log.Errorf("Cannot open log file, logging to stderr: %s: %s", path, err)
+		} else {
+			log.SetOutput(logFile)
+		}
+	}
+
+	if err := prev.Close(); err != nil {
+		log.Errorf("Cannot close log file: %s: %s", prev.path, err)
+	}
+
+	applyLogJournal()
+}
+
+// Serializes the changes of the hooks of the standard logger, as the hooks are copied before being replaced.
+var logHooksMu sync.Mutex
+
+// Adds the hook to the standard logger.
+func addLogHook(hook log.Hook) {
+	logHooksMu.Lock()
+	defer logHooksMu.Unlock()
+	log.AddHook(hook)
+}
+
+// Removes the hook from the standard logger.
+func removeLogHook(hook log.Hook) {
+	logHooksMu.Lock()
+	defer logHooksMu.Unlock()
+
+	hooks := make(log.LevelHooks)
+	for level, levelHooks := range log.StandardLogger().Hooks {
+		for _, h := range levelHooks {
+			if h != hook {
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+	log.StandardLogger().ReplaceHooks(hooks)
+}
+
+// Sends the log to journald when requested on the command line, or by the configuration.
+// The log is not written to stderr meanwhile, as it is usually collected by journald too.
+func applyLogJournal() {
+	enabled := config.LogJournald || logJournaldOverride
+
+	if enabled && journalHook == nil {
+		hook, err := newJournalHook(journalSocket)
+		if err != nil {
+			log.Errorf("Cannot connect to journald: %s: %s", journalSocket, err)
+			return
+		}
+		journalHook = hook
+		addLogHook(hook)
+	} else if !enabled && journalHook != nil {
+		removeLogHook(journalHook)
+		if err := journalHook.Close(); err != nil {
+			log.Errorf("Cannot close journald connection: %s", err)
+		}
+		journalHook = nil
+	}
+
+	if journalHook != nil && logFile == nil {
+		log.SetOutput(io.Discard)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogFormat(t *testing.T) {
+	assert.Error(t, parseLogFormat("xml"))
+
+	logFormatOverride = LogFormatJSON
+	defer func() {
+		logFormatOverride = ""
+		applyLogFormat()
+	}()
+	applyLogFormat()
+
+	var b bytes.Buffer
+	out := log.StandardLogger().Out
+	log.SetOutput(&b)
+	linkLogger("0123", "/web", "eth0", 12, "vweb0").WithFields(renameFields("veth1", "vweb0")).Info("Link renamed")
+	log.SetOutput(out)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(b.Bytes(), &record))
+	assert.Equal(t, "info", record["level"])
+	assert.Equal(t, "Link renamed", record["msg"])
+	assert.Equal(t, "0123", record["container_id"])
+	assert.Equal(t, "/web", record["container_name"])
+	assert.Equal(t, "eth0", record["container_link"])
+	assert.Equal(t, "vweb0", record["link"])
+	assert.EqualValues(t, 12, record["ifindex"])
+	assert.Equal(t, "veth1", record["old_name"])
+	assert.Equal(t, "vweb0", record["new_name"])
+	assert.Contains(t, record, "time")
+}
+
+func TestTextFormatter(t *testing.T) {
+	var b bytes.Buffer
+	assert.False(t, isTerminal(&b))
+	assert.True(t, textFormatter(&b).DisableColors)
+
+	logNoColor = true
+	defer func() { logNoColor = false }()
+	assert.True(t, textFormatter(os.Stderr).DisableColors)
+}
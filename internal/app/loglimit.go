@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Interval of flushing the summaries of the suppressed log records.
+const logLimiterFlushInterval = time.Minute
+
+// Identical log record, repeated within the interval.
+type repeatedLog struct {
+	// Start of the interval, when the record was logged.
+	since time.Time
+	// Number of the repetitions suppressed since then.
+	suppressed int
+	level      log.Level
+	fields     log.Fields
+}
+
+// Suppresses identical recurring log records within the interval, summarizing them with the count afterwards.
+// Prevents a single broken container from flooding the log on every resync.
+type LogLimiter struct {
+	mu      sync.Mutex
+	records map[string]*repeatedLog
+}
+
+var logLimiter = newLogLimiter()
+
+func newLogLimiter() *LogLimiter {
+	return &LogLimiter{records: make(map[string]*repeatedLog)}
+}
+
+// Returns whether the record should be logged, and the number of repetitions suppressed before it.
+// Zero interval disables the suppression.
+func (r *LogLimiter) Allow(message string, level log.Level, fields log.Fields, now time.Time, interval time.Duration) (bool, int) {
+	if interval <= 0 {
+		return true, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[message]
+	if ok && now.Sub(record.since) < interval {
+		record.suppressed++
+		return false, 0
+	}
+
+	suppressed := 0
+	if ok {
+		suppressed = record.suppressed
+	}
+	r.records[message] = &repeatedLog{since: now, level: level, fields: fields}
+	return true, suppressed
+}
+
+// Removes the records whose interval has passed, and returns the summaries of the suppressed ones by message.
+func (r *LogLimiter) Flush(now time.Time, interval time.Duration) map[string]repeatedLog {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := make(map[string]repeatedLog)
+	for message, record := range r.records {
+		if now.Sub(record.since) < interval {
+			continue
+		}
+		if record.suppressed > 0 {
+			summaries[message] = *record
+		}
+		delete(r.records, message)
+	}
+	return summaries
+}
+
+// Logs the record, unless the identical record was logged within log_repeat_interval.
+// The first record after the interval reports the number of the suppressed repetitions.
+func logLimited(logger *log.Entry, level log.Level, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	allowed, suppressed := logLimiter.Allow(message, level, logger.Data, time.Now(), config.LogRepeatInterval)
+	if !allowed {
+		return
+	}
+
+	if suppressed > 0 {
+		message = fmt.Sprintf("%s (repeated %d more times within %s)", message, suppressed, config.LogRepeatInterval)
+		logger = logger.WithField(logFieldRepeated, suppressed)
+	}
+	logger.Log(level, message)
+}
+
+// Logs the error, unless the identical error was logged within log_repeat_interval.
+func errorfLimited(logger *log.Entry, format string, args ...any) {
+	logLimited(logger, log.ErrorLevel, format, args...)
+}
+
+// Logs the warning, unless the identical warning was logged within log_repeat_interval.
+func warnfLimited(logger *log.Entry, format string, args ...any) {
+	logLimited(logger, log.WarnLevel, format, args...)
+}
+
+// Logs the summaries of the records suppressed within the passed intervals.
+func flushLimitedLogs() {
+	summaries := logLimiter.Flush(time.Now(), config.LogRepeatInterval)
+	for _, message := range slices.Sorted(maps.Keys(summaries)) {
+		summary := summaries[message]
+		log.WithFields(summary.fields).WithField(logFieldRepeated, summary.suppressed).
+			Logf(summary.level, "%s (repeated %d more times within %s)", message, summary.suppressed, config.LogRepeatInterval)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLimiter(t *testing.T) {
+	r := newLogLimiter()
+	now := time.Now()
+
+	allowed, suppressed := r.Allow("failed", log.ErrorLevel, nil, now, time.Hour)
+	assert.True(t, allowed)
+	assert.Zero(t, suppressed)
+
+	for i := range 3 {
+		allowed, _ = r.Allow("failed", log.ErrorLevel, nil, now.Add(time.Duration(i+1)*time.Minute), time.Hour)
+		assert.False(t, allowed)
+	}
+
+	// Other records are not affected.
+	allowed, _ = r.Allow("other", log.ErrorLevel, nil, now, time.Hour)
+	assert.True(t, allowed)
+
+	allowed, suppressed = r.Allow("failed", log.ErrorLevel, nil, now.Add(time.Hour), time.Hour)
+	assert.True(t, allowed)
+	assert.Equal(t, 3, suppressed)
+
+	// Suppression is disabled.
+	allowed, _ = r.Allow("failed", log.ErrorLevel, nil, now.Add(time.Hour), 0)
+	assert.True(t, allowed)
+}
+
+func TestLogLimiterFlush(t *testing.T) {
+	r := newLogLimiter()
+	now := time.Now()
+
+	r.Allow("failed", log.ErrorLevel, log.Fields{logFieldContainerName: "/web"}, now, time.Hour)
+	r.Allow("failed", log.ErrorLevel, nil, now.Add(time.Minute), time.Hour)
+	r.Allow("once", log.ErrorLevel, nil, now, time.Hour)
+
+	assert.Empty(t, r.Flush(now.Add(time.Minute), time.Hour))
+
+	summaries := r.Flush(now.Add(time.Hour), time.Hour)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 1, summaries["failed"].suppressed)
+	assert.Equal(t, "/web", summaries["failed"].fields[logFieldContainerName])
+	assert.Empty(t, r.records)
+}
+
+func TestErrorfLimited(t *testing.T) {
+	prevConfig, prevLimiter := config, logLimiter
+	defer func() { config, logLimiter = prevConfig, prevLimiter }()
+	config = defaultConfig()
+	logLimiter = newLogLimiter()
+
+	var b bytes.Buffer
+	out := log.StandardLogger().Out
+	log.SetOutput(&b)
+	defer log.SetOutput(out)
+
+	for range 3 {
+		errorfLimited(log.WithField(logFieldContainerName, "/web"), "Cannot list links for container: %s", "/web")
+	}
+	assert.Equal(t, 1, bytes.Count(b.Bytes(), []byte("Cannot list links")))
+
+	logLimiter.records["Cannot list links for container: /web"].since = time.Now().Add(-time.Hour)
+	flushLimitedLogs()
+	assert.Contains(t, b.String(), "Cannot list links for container: /web (repeated 2 more times within 1h0m0s)")
+	assert.Contains(t, b.String(), "repeated=2")
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// Container owning the host link.
+type LookupResult struct {
+	Index int `json:"ifindex"`
+	// Current name of the host link. Empty when the link is gone.
+	Name string `json:"name"`
+	// Name assigned by Docker.
+	OriginalName  string   `json:"original_name,omitempty"`
+	ContainerID   string   `json:"container_id"`
+	ContainerName string   `json:"container_name"`
+	ContainerLink string   `json:"container_link,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	Networks      []string `json:"networks,omitempty"`
+	// Whether the link is tracked by the daemon.
+	Tracked bool `json:"tracked"`
+	// Whether the container is found by the live inspection.
+	Running bool `json:"running"`
+	// Time of the renaming which assigned the name, when looked up in the timeline database.
+	AssignedAt time.Time `json:"assigned_at,omitzero"`
+}
+
+// Returns the daemon mappings: from the running daemon, or from the state file when the daemon is not reachable.
+func daemonMappings() []ContainerMapping {
+	var mappings []ContainerMapping
+	if len(config.ControlSocket) > 0 {
+		err := controlRequest(config.ControlSocket, "GET", "/mappings", &mappings)
+		if err == nil {
+			return mappings
+		}
+		log.Debugf("Daemon is not reachable, reading state file: %s", err)
+	}
+
+	ps, err := loadPersistentState(config.StateFile)
+	if err != nil {
+		log.Warnf("Cannot read state file: %s: %s", config.StateFile, err)
+		return nil
+	}
+	return ps.Containers
+}
+
+// Finds the tracked link by the index, or by the name when the index is zero.
+func findMappingLink(mappings []ContainerMapping, index int, name string) (ContainerMapping, LinkState, bool) {
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			if (index > 0 && link.Index == index) || (index == 0 && link.Name == name) {
+				return mapping, link, true
+			}
+		}
+	}
+	return ContainerMapping{}, LinkState{}, false
+}
+
+// Fills the result with the container data if the container owns the host link. Returns whether the link is owned.
+func lookupContainerLink(rt ContainerRuntime, result *LookupResult, inspect container.InspectResponse) bool {
+	containerMappings, err := containerLinkMappings(rt, inspect)
+	if err != nil {
+		containerLogger(inspect.ID, inspect.Name).Errorf("Cannot list links for container: %s %s: %s", inspect.Name, inspect.ID, err)
+		return false
+	}
+
+	index := slices.IndexFunc(containerMappings, func(m LinkMapping) bool { return m.Index == result.Index })
+	if index == -1 {
+		return false
+	}
+
+	result.ContainerID = inspect.ID
+	result.ContainerName = strings.TrimPrefix(inspect.Name, "/")
+	result.ContainerLink = containerMappings[index].ContainerLink
+	if inspect.Config != nil {
+		result.Image = inspect.Config.Image
+	}
+	if inspect.NetworkSettings != nil {
+		result.Networks = slices.Sorted(maps.Keys(inspect.NetworkSettings.Networks))
+	}
+	result.Running = true
+	return true
+}
+
+// Resolves the host link, specified by the name or by the index, to the owning container.
+// Live inspection of the running containers is combined with the daemon state.
+func lookupLink(ctx context.Context, rt ContainerRuntime, name string, index int) (LookupResult, error) {
+	var result LookupResult
+
+	var link netlink.Link
+	var err error
+	if index > 0 {
+		link, err = nlLinkByIndex(index)
+	} else {
+		link, err = nlLinkByName(name)
+	}
+	if err == nil {
+		result.Index = link.Attrs().Index
+		result.Name = link.Attrs().Name
+		result.OriginalName = linkops.PreservedName(link)
+	} else {
+		log.Debugf("Host link is not found: %s", err)
+		result.Index = index
+	}
+
+	mapping, trackedLink, tracked := findMappingLink(daemonMappings(), result.Index, name)
+	if tracked {
+		result.Index = trackedLink.Index
+		result.Tracked = true
+		result.ContainerID = mapping.ID
+		result.ContainerName = strings.TrimPrefix(mapping.Name, "/")
+		result.ContainerLink = trackedLink.ContainerLink
+		result.OriginalName = trackedLink.OriginalName
+	}
+
+	if err != nil && !tracked {
+		if index > 0 {
+			return result, fmt.Errorf("host link is not found: ifindex %d", index)
+		}
+		return result, fmt.Errorf("host link is not found: %s", name)
+	}
+
+	if err == nil {
+		// Check the tracked container first, as scanning all containers enters every network namespace.
+		found := false
+		if tracked {
+			inspect, err := inspectContainer(ctx, rt, mapping.ID)
+			if err == nil {
+				found = lookupContainerLink(rt, &result, inspect)
+			} else if !rt.IsNotFound(err) {
+				log.WithField(logFieldContainerID, mapping.ID).Errorf("cli.ContainerInspect failed for container ID %s: %s", mapping.ID, err)
+			}
+		}
+
+		for _, inspect := range inspectRunningContainers(ctx, rt, filters.NewArgs()) {
+			if found {
+				break
+			}
+			found = lookupContainerLink(rt, &result, inspect)
+		}
+	}
+
+	if !result.Tracked && !result.Running {
+		return result, fmt.Errorf("host link is not owned by any container: %s (ifindex %d)", result.Name, result.Index)
+	}
+	return result, nil
+}
+
+// Resolves the host link, specified by the name or by the index, to the container owning it at the time.
+// The assignments are read from the timeline database, so the containers gone since are resolved too.
+func lookupLinkAt(name string, index int, at time.Time) (LookupResult, error) {
+	if len(config.TimelineDB) == 0 {
+		return LookupResult{}, errors.New("timeline database is disabled in the configuration")
+	}
+
+	a, err := timelineOwner(config.TimelineDB, name, index, at)
+	if err != nil {
+		return LookupResult{}, err
+	}
+
+	return LookupResult{
+		Index:         a.Index,
+		Name:          a.Name,
+		OriginalName:  a.OriginalName,
+		ContainerID:   a.ContainerID,
+		ContainerName: strings.TrimPrefix(a.ContainerName, "/"),
+		ContainerLink: a.ContainerLink,
+		AssignedAt:    a.AssignedAt,
+	}, nil
+}
+
+// Prints the lookup result as a list of fields.
+func (r LookupResult) print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Host link:\t%s\n", r.Name)
+	fmt.Fprintf(tw, "Ifindex:\t%d\n", r.Index)
+	fmt.Fprintf(tw, "Original name:\t%s\n", r.OriginalName)
+	fmt.Fprintf(tw, "Container:\t%s\n", r.ContainerName)
+	fmt.Fprintf(tw, "Container ID:\t%s\n", r.ContainerID)
+	fmt.Fprintf(tw, "Container link:\t%s\n", r.ContainerLink)
+	fmt.Fprintf(tw, "Image:\t%s\n", r.Image)
+	fmt.Fprintf(tw, "Networks:\t%s\n", strings.Join(r.Networks, ", "))
+	fmt.Fprintf(tw, "Tracked:\t%t\n", r.Tracked)
+	fmt.Fprintf(tw, "Running:\t%t\n", r.Running)
+	if !r.AssignedAt.IsZero() {
+		fmt.Fprintf(tw, "Assigned at:\t%s\n", r.AssignedAt.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMappingLink(t *testing.T) {
+	mappings := []ContainerMapping{
+		{ID: "0123", Name: "/web", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}}},
+		{ID: "4567", Name: "/db", Links: []LinkState{
+			{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0"},
+			{Index: 14, ContainerLink: "eth1", OriginalName: "veth3", Name: "vdb1"},
+		}},
+	}
+
+	mapping, link, ok := findMappingLink(mappings, 14, "")
+	assert.True(t, ok)
+	assert.Equal(t, "4567", mapping.ID)
+	assert.Equal(t, "eth1", link.ContainerLink)
+
+	mapping, link, ok = findMappingLink(mappings, 0, "vweb0")
+	assert.True(t, ok)
+	assert.Equal(t, "0123", mapping.ID)
+	assert.Equal(t, 10, link.Index)
+
+	// Index takes precedence over the name.
+	_, _, ok = findMappingLink(mappings, 11, "vweb0")
+	assert.False(t, ok)
+
+	_, _, ok = findMappingLink(mappings, 0, "veth1")
+	assert.False(t, ok)
+}
+
+func TestLookupLinkAt(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config.TimelineDB = filepath.Join(t.TempDir(), "timeline.db")
+	t.Cleanup(closeTimeline)
+
+	web := LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0"}
+	recordAssignment("0123", "/web", web)
+	assigned := time.Now()
+	recordRelease(web)
+	released := time.Now()
+
+	result, err := lookupLinkAt("vweb0", 0, assigned)
+	require.NoError(t, err)
+	assert.Equal(t, "web", result.ContainerName)
+	assert.Equal(t, "veth1", result.OriginalName)
+	assert.False(t, result.AssignedAt.After(assigned))
+
+	_, err = lookupLinkAt("", 10, released)
+	assert.ErrorContains(t, err, "host link is not owned by any container since ")
+	assert.ErrorContains(t, err, " (release by web): ifindex 10")
+
+	_, err = lookupLinkAt("vdb0", 0, released)
+	assert.EqualError(t, err, "host link is not found in timeline: vdb0")
+
+	config.TimelineDB = ""
+	_, err = lookupLinkAt("vweb0", 0, assigned)
+	assert.EqualError(t, err, "timeline database is disabled in the configuration")
+}
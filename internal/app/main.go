@@ -0,0 +1,1573 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package app implements the docker-veth-namer command: the daemon renaming the host links, and its tools.
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// Number of attempts to find links of the container being set up.
+	linkRetryAttempts = 5
+	// Initial delay between attempts to find links of the container being set up.
+	linkRetryDelay = 100 * time.Millisecond
+
+	// Number of attempts to inspect the container on transient errors.
+	inspectRetryAttempts = 3
+	// Initial delay between attempts to inspect the container.
+	inspectRetryDelay = 200 * time.Millisecond
+)
+
+var (
+	errNoVethLinks = errors.New("no veth links found")
+
+	// Application version is set from Makefile via LD_FLAGS.
+	AppVersion string
+
+	dryRun bool
+
+	// Number of operations waiting for the next retry attempt.
+	pendingRetries atomic.Int64
+)
+
+// Returns any key/value from map.
+func mapKeyVal[K comparable, V any](m map[K]V) (k K, v V) {
+	for k, v := range m {
+		return k, v
+	}
+	return k, v
+}
+
+// Error which is not worth retrying.
+type PermanentError struct {
+	Err error
+}
+
+func (e PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Calls the operation until it succeeds, or the number of attempts is exhausted.
+// The delay between attempts is doubled after each failure. The last error is returned.
+// The operation may return PermanentError to stop retrying, in which case the wrapped error is returned.
+func retryWithBackoff(attempts int, delay time.Duration, op func() error) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = op()
+		if err == nil {
+			return nil
+		}
+
+		var permanentErr PermanentError
+		if errors.As(err, &permanentErr) {
+			return permanentErr.Err
+		}
+
+		if attempt < attempts {
+			log.Debugf("Attempt %d of %d failed, retrying in %s: %s", attempt, attempts, delay, err)
+			pendingRetries.Add(1)
+			time.Sleep(delay)
+			pendingRetries.Add(-1)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// Replaces substrings in the container name by the configured replacement rules.
+func applyReplacements(containerName string) string {
+	return naming.Replace(containerName, config.Replacements, nil)
+}
+
+// Makes the host link name by the configured naming strategy. Empty name is returned when the name cannot be made.
+func makeLinkName(containerName string, containerLinkName string) string {
+	linkName, _ := makeLinkNameFor(NameRequest{ContainerName: containerName, ContainerLink: containerLinkName})
+	return linkName
+}
+
+// Returns the rules of morphing the container names of the configuration.
+func namingOptions(c Config) naming.Options {
+	return naming.Options{
+		LinkNamePrefix:          c.LinkNamePrefix,
+		LinkIndexSeparator:      c.LinkIndexSeparator,
+		ContainerLinkPrefixes:   c.ContainerLinkPrefixes,
+		Replacements:            c.Replacements,
+		RemoveDuplicatedSymbols: c.RemoveDuplicatedSymbols,
+	}
+}
+
+// Makes the human-readable link name by the configured rules, recording the steps into the trace (which can be nil).
+func traceLinkName(containerName string, containerLinkName string, trace *naming.Trace) (string, error) {
+	return naming.LinkName(containerName, containerLinkName, namingOptions(config), trace)
+}
+
+// Renames the host link to match the container name and the container link index.
+// The labels are recorded along with the mapping. Returns the outcome of renaming, and the reason of the failure.
+func updateLinkName(link netlink.Link, containerID string, containerName string, containerLinkName string, labels LinkLabels) (RenameOutcome, error) {
+	req := NameRequest{
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		ContainerLink: containerLinkName,
+		LinkLabels:    labels,
+	}
+
+	// The link marked by the same configuration has the name which would be made for it.
+	linkName := link.Attrs().Name
+	var marker string
+	if config.OwnershipMarker {
+		marker = ownershipMarker(req, linkName)
+	}
+	if len(marker) == 0 || link.Attrs().Alias != marker {
+		var err error
+		linkName, err = makeLinkNameFor(req)
+		if err != nil {
+			reason := SkipNamingFailed
+			if errors.Is(err, naming.ErrLinkSuffixTooLong) {
+				reason = SkipNameTooLong
+			}
+			countSkip(reason, containerID, containerName, containerLinkName)
+			return RenameFailed, fmt.Errorf("host link name cannot be made: %w", err)
+		}
+	}
+
+	linkState := LinkState{
+		Index:         link.Attrs().Index,
+		ContainerLink: containerLinkName,
+		OriginalName:  link.Attrs().Name,
+		Name:          linkName,
+		LinkLabels:    labels,
+	}
+
+	logger := linkLogger(containerID, containerName, containerLinkName, linkState.Index, link.Attrs().Name)
+
+	if link.Attrs().Name == linkName {
+		if state.TracksLink(linkState.Index) {
+			logger.Debugf("Link was renamed already: %s %s: %s", containerName, containerLinkName, link.Attrs().Name)
+		} else {
+			// Renamed by a previous run, the original name may be preserved as the alternative name.
+			if preservedName := linkops.PreservedName(link); len(preservedName) > 0 {
+				linkState.OriginalName = preservedName
+			}
+			logger.WithField(logFieldOriginalName, linkState.OriginalName).
+				Infof("Link adopted: %s %s: %s", containerName, containerLinkName, link.Attrs().Name)
+		}
+		if len(marker) > 0 && link.Attrs().Alias != marker && !dryRun && !isPaused() {
+			markLink(link, marker)
+		}
+		state.SetLink(containerID, containerName, linkState)
+		return RenameUnchanged, nil
+	}
+
+	logger = logger.WithFields(renameFields(link.Attrs().Name, linkName))
+
+	// The throttled rename is tried again after the wait, making the name by the configuration in effect then.
+	if !dryRun && !isPaused() && !allowRename() {
+		return RenameThrottled, nil
+	}
+
+	if isPaused() {
+		countSkip(SkipPaused, containerID, containerName, containerLinkName)
+		logger.Infof("Renaming is paused, skipping: %s %s: %s => %s", containerName, containerLinkName, link.Attrs().Name, linkName)
+		return RenameSkipped, nil
+	}
+
+	if !dryRun {
+		hookRename := HookRename{
+			ContainerID:   containerID,
+			ContainerName: containerName,
+			ContainerLink: containerLinkName,
+			Index:         linkState.Index,
+			OldName:       link.Attrs().Name,
+			NewName:       linkName,
+		}
+
+		if err := runHook(HookPreRename, config.PreRenameHook, hookRename); err != nil {
+			countSkip(SkipVetoed, containerID, containerName, containerLinkName)
+			logger.Warnf("Rename is vetoed by the pre-rename hook: %s %s: %s => %s : %s", containerName, containerLinkName, link.Attrs().Name, linkName, err)
+			return RenameSkipped, nil
+		}
+
+		start := time.Now()
+		err := nlLinkSetName(link, linkName)
+		renameDuration.ObserveSince(start)
+
+		hookRename.Result, hookRename.Err = HookResultRenamed, err
+		if err != nil {
+			hookRename.Result = HookResultFailed
+		}
+		if err := runHook(HookPostRename, config.PostRenameHook, hookRename); err != nil {
+			logger.Errorf("Post-rename hook failed: %s %s: %s", containerName, containerLinkName, err)
+		}
+
+		if err != nil {
+			renameFailureCount.Add(1)
+			errorfLimited(logger.WithField(logFieldMessageID, MessageIDLinkRenameFailed),
+				"netlink.LinkSetName failed: %s %s: %s => %s : %s", containerName, containerLinkName, link.Attrs().Name, linkName, err)
+			recordRename(RenameApply, containerID, containerName, containerLinkName, linkState.Index, link.Attrs().Name, linkName, err)
+			return RenameFailed, fmt.Errorf("netlink.LinkSetName failed: %w", err)
+		}
+
+		preserveLinkName(link)
+		if config.OwnershipMarker {
+			markLink(link, ownershipMarker(req, linkName))
+		}
+	}
+
+	state.SetLink(containerID, containerName, linkState)
+	renameCount.Add(1)
+	recordRename(RenameApply, containerID, containerName, containerLinkName, linkState.Index, link.Attrs().Name, linkName, nil)
+	recordAssignment(containerID, containerName, linkState)
+	notify(NotificationMappingAdded, containerID, containerName, linkState)
+	recordLinkRename(containerID, containerName, linkState, "the program")
+
+	logger.WithFields(log.Fields{logFieldLink: linkName, logFieldOriginalName: linkState.OriginalName, logFieldMessageID: MessageIDLinkRenamed}).
+		Infof("Link renamed: %s %s: %s => %s", containerName, containerLinkName, link.Attrs().Name, linkName)
+
+	return RenameDone, nil
+}
+
+// Renames the host link by updateLinkName, taking the configuration read lock, which must not be held by the caller.
+// The throttled rename waits for the rate limit without holding the lock, so the configuration may be reloaded meanwhile,
+// and the link is looked up and its name is made again afterwards.
+func updateLinkNameThrottled(ctx context.Context, link netlink.Link, containerID string, containerName string, containerLinkName string, labels LinkLabels) (RenameOutcome, error) {
+	for {
+		configMu.RLock()
+		outcome, err := updateLinkName(link, containerID, containerName, containerLinkName, labels)
+		configMu.RUnlock()
+		if outcome != RenameThrottled {
+			return outcome, err
+		}
+
+		if err := waitRenameRateLimit(ctx); err != nil {
+			return RenameFailed, err
+		}
+		link, err = nlLinkByIndex(link.Attrs().Index)
+		if err != nil {
+			return RenameFailed, fmt.Errorf("netlink.LinkByIndex failed: %w", err)
+		}
+	}
+}
+
+// Renames net links for the container of the inspect record.
+// When waitForLinks is set, the container links are expected to appear shortly,
+// and the enumeration is retried while the sandbox contains no veth links.
+// Returns the counts of the renaming outcomes, and the failure reasons.
+// Failure to enumerate the links counts as a single failed link.
+// The configuration read lock is taken by the function, and must not be held by the caller.
+func renameContainerLinks(ctx context.Context, rt ContainerRuntime, inspect container.InspectResponse, waitForLinks bool) RenameSummary {
+	configMu.RLock()
+	summary, renames := containerLinkRenames(rt, inspect, waitForLinks)
+	configMu.RUnlock()
+
+	for _, r := range renames {
+		outcome, err := updateLinkNameThrottled(ctx, r.link, inspect.ID, inspect.Name, r.containerLink, r.labels)
+		if err != nil {
+			summary.Fail(inspect.Name, r.containerLink, err.Error())
+		} else {
+			summary.Count(outcome)
+		}
+	}
+
+	return summary
+}
+
+// Host link of the container to be renamed.
+type linkRename struct {
+	link          netlink.Link
+	containerLink string
+	labels        LinkLabels
+}
+
+// Returns the host links of the container to be renamed, and the summary of the failures to find them.
+func containerLinkRenames(rt ContainerRuntime, inspect container.InspectResponse, waitForLinks bool) (RenameSummary, []linkRename) {
+	var summary RenameSummary
+	var renames []linkRename
+
+	logger := containerLogger(inspect.ID, inspect.Name)
+
+	if len(inspect.Name) == 0 {
+		countSkip(SkipEmptyName, inspect.ID, inspect.Name, "")
+		errorfLimited(logger, "Cannot make host link name: container name must not be empty: %s", inspect.ID)
+		summary.Fail(inspect.ID, "", "container name must not be empty")
+		return summary, nil
+	}
+
+	sandboxKey, err := rt.ResolveNetns(inspect)
+	switch {
+	case errors.Is(err, dockerwatch.ErrHostNetwork):
+		countSkip(SkipHostNetwork, inspect.ID, inspect.Name, "")
+		logger.Debugf("Container is running in host network mode, skipping: %s %s", inspect.Name, inspect.ID)
+		summary.ContainersSkipped++
+		return summary, nil
+	case errors.Is(err, dockerwatch.ErrNoneNetwork):
+		countSkip(SkipNoneNetwork, inspect.ID, inspect.Name, "")
+		logger.Debugf("Container is running in none network mode, skipping: %s %s", inspect.Name, inspect.ID)
+		summary.ContainersSkipped++
+		return summary, nil
+	case errors.Is(err, dockerwatch.ErrNoSandbox):
+		countSkip(SkipNoSandbox, inspect.ID, inspect.Name, "")
+		errorfLimited(logger, "Sandbox is not defined for container: %s %s", inspect.Name, inspect.ID)
+		summary.Fail(inspect.Name, "", err.Error())
+		return summary, nil
+	case errors.Is(err, dockerwatch.ErrDefaultNamespace):
+		countSkip(SkipDefaultNamespace, inspect.ID, inspect.Name, "")
+		errorfLimited(logger, "Container uses default namespace, this is not supported: %s %s", inspect.Name, inspect.ID)
+		summary.Fail(inspect.Name, "", err.Error())
+		return summary, nil
+	}
+
+	var containerLinks []linkops.VEth
+	err = retryWithBackoff(linkRetryAttempts, linkRetryDelay, func() error {
+		var err error
+		containerLinks, err = listContainerLinks(sandboxKey)
+		if err != nil {
+			return fmt.Errorf("listing container links failed: %w", err)
+		}
+		if waitForLinks && len(containerLinks) == 0 {
+			return errNoVethLinks
+		}
+		return nil
+	})
+	if errors.Is(err, errNoVethLinks) {
+		// Container may be connected to networks of other kinds only, e.g. macvlan.
+		countSkip(SkipNoVethLinks, inspect.ID, inspect.Name, "")
+		logger.Debugf("No veth links found for container: %s %s", inspect.Name, inspect.ID)
+		summary.ContainersSkipped++
+		return summary, nil
+	} else if err != nil {
+		errorfLimited(logger, "Cannot list links for container: %s %s: %s", inspect.Name, inspect.ID, err)
+		summary.Fail(inspect.Name, "", fmt.Sprintf("cannot list links: %s", err))
+		return summary, nil
+	}
+
+	var image, pod, podNamespace string
+	if inspect.Config != nil {
+		image = inspect.Config.Image
+		pod = inspect.Config.Labels[kubernetesPodNameLabel]
+		podNamespace = inspect.Config.Labels[kubernetesPodNamespaceLabel]
+	}
+
+	for _, containerLink := range containerLinks {
+		if len(containerLink.Name) == 0 {
+			errorfLimited(logger.WithField(logFieldIndex, containerLink.ParentIndex),
+				"Cannot make host link name: container link suffix must not be empty: %s %d", inspect.ID, containerLink.ParentIndex)
+			summary.Fail(inspect.Name, "", "container link name must not be empty")
+			continue
+		}
+
+		// The host side of the veth may be not visible yet, while being set up.
+		var link netlink.Link
+		err := retryWithBackoff(linkRetryAttempts, linkRetryDelay, func() error {
+			var err error
+			link, err = nlLinkByIndex(containerLink.ParentIndex)
+			return err
+		})
+		if err != nil {
+			errorfLimited(logger.WithFields(log.Fields{logFieldContainerLink: containerLink.Name, logFieldIndex: containerLink.ParentIndex}),
+				"netlink.LinkByIndex failed: %s", err)
+			summary.Fail(inspect.Name, containerLink.Name, fmt.Sprintf("netlink.LinkByIndex failed: %s", err))
+			continue
+		}
+
+		labels := LinkLabels{
+			Image:        image,
+			Network:      dockerwatch.LinkNetwork(inspect, containerLink.HardwareAddr),
+			Pod:          pod,
+			PodNamespace: podNamespace,
+		}
+		renames = append(renames, linkRename{link: link, containerLink: containerLink.Name, labels: labels})
+	}
+
+	return summary, renames
+}
+
+// Drops the mappings of host links which are no longer connected to the container.
+// Optionally restores the original name of the host link, if it still exists.
+func handleNetworkDisconnect(ctx context.Context, rt ContainerRuntime, containerID string) {
+	trackedLinks := state.Links(containerID)
+	if len(trackedLinks) == 0 {
+		log.WithField(logFieldContainerID, containerID).Debugf("No links are tracked for container ID: %s", containerID)
+		return
+	}
+
+	// Collect peer indexes of the links remaining in the container.
+	// Stopped or removed container has no sandbox, consequently no links.
+	connected := make(map[int]bool)
+	inspect, err := inspectContainer(ctx, rt, containerID)
+	if err != nil && !rt.IsNotFound(err) {
+		log.WithField(logFieldContainerID, containerID).Errorf("cli.ContainerInspect failed for container ID %s: %s", containerID, err)
+		return
+	}
+
+	containerName := inspect.Name
+	if len(containerName) == 0 {
+		containerName = containerID
+	}
+
+	var sandboxKey string
+	if inspect.NetworkSettings != nil {
+		sandboxKey = inspect.NetworkSettings.NetworkSettingsBase.SandboxKey
+	}
+	if inspect.State != nil && inspect.State.Running && len(sandboxKey) > 0 {
+		containerLinks, err := listContainerLinks(sandboxKey)
+		if err != nil {
+			containerLogger(containerID, containerName).
+				Errorf("Listing container links failed for container: %s %s: %s", containerName, containerID, err)
+			return
+		}
+
+		for _, containerLink := range containerLinks {
+			connected[containerLink.ParentIndex] = true
+		}
+	}
+
+	for _, trackedLink := range trackedLinks {
+		if connected[trackedLink.Index] {
+			continue
+		}
+
+		state.RemoveLink(containerID, trackedLink.Index)
+		recordRelease(trackedLink)
+		notify(NotificationMappingRemoved, containerID, containerName, trackedLink)
+		trackedLinkLogger(containerID, containerName, trackedLink).Infof("Link mapping removed: %s %s: %s", containerName, trackedLink.ContainerLink, trackedLink.Name)
+
+		if config.RestoreNameOnDisconnect {
+			restoreLinkName(containerID, containerName, trackedLink)
+		}
+	}
+}
+
+// Drops the mappings of all host links of the stopped or removed container.
+func handleContainerExit(containerID string, containerName string) {
+	cs, ok := state.RemoveContainer(containerID)
+	if !ok {
+		return
+	}
+
+	if len(containerName) == 0 {
+		containerName = cs.Name
+	}
+
+	for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+		trackedLink := *cs.Links[index]
+		recordRelease(trackedLink)
+		notify(NotificationMappingRemoved, containerID, containerName, trackedLink)
+		trackedLinkLogger(containerID, containerName, trackedLink).Infof("Link mapping removed: %s %s: %s", containerName, trackedLink.ContainerLink, trackedLink.Name)
+	}
+}
+
+// Stops tracking the links which do not exist anymore, or were renamed by someone else,
+// e.g. the links restored from the state file, which belonged to containers stopped meanwhile.
+func dropStaleLinks() {
+	for _, cs := range state.Containers() {
+		for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+			trackedLink := cs.Links[index]
+
+			link, err := nlLinkByIndex(index)
+			if err == nil && link.Attrs().Name == trackedLink.Name {
+				continue
+			}
+
+			trackedLinkLogger(cs.ID, cs.Name, *trackedLink).Debugf("Link mapping is stale, dropping: %s %s: %s", cs.Name, trackedLink.ContainerLink, trackedLink.Name)
+			state.RemoveLink(cs.ID, index)
+			recordRelease(*trackedLink)
+		}
+	}
+}
+
+// Renames the host link back to its original name, if the link still exists and was not renamed by someone else.
+func restoreLinkName(containerID string, containerName string, trackedLink LinkState) {
+	if len(trackedLink.OriginalName) == 0 || trackedLink.OriginalName == trackedLink.Name {
+		return
+	}
+
+	logger := trackedLinkLogger(containerID, containerName, trackedLink).
+		WithFields(renameFields(trackedLink.Name, trackedLink.OriginalName))
+
+	link, err := nlLinkByIndex(trackedLink.Index)
+	if err != nil {
+		// The link is removed along with the endpoint.
+		logger.Debugf("Link is gone: %d %s", trackedLink.Index, trackedLink.Name)
+		return
+	}
+
+	if link.Attrs().Name != trackedLink.Name {
+		logger.Debugf("Link was renamed by someone else, not restoring: %s => %s", trackedLink.Name, link.Attrs().Name)
+		return
+	}
+
+	if isPaused() {
+		logger.Infof("Renaming is paused, not restoring: %s => %s", trackedLink.Name, trackedLink.OriginalName)
+		return
+	}
+
+	if !dryRun {
+		// The name cannot be assigned while it is used as the alternative name.
+		if slices.Contains(link.Attrs().AltNames, trackedLink.OriginalName) {
+			err := nlLinkDelAltName(link, trackedLink.OriginalName)
+			if err != nil {
+				logger.WithField(logFieldMessageID, MessageIDLinkRestoreFailed).
+					Errorf("netlink.LinkDelAltName failed: %s %s : %s", trackedLink.Name, trackedLink.OriginalName, err)
+				recordRename(RenameRestore, containerID, containerName, trackedLink.ContainerLink, trackedLink.Index, trackedLink.Name, trackedLink.OriginalName, err)
+				return
+			}
+		}
+
+		err := nlLinkSetName(link, trackedLink.OriginalName)
+		if err != nil {
+			logger.WithField(logFieldMessageID, MessageIDLinkRestoreFailed).
+				Errorf("netlink.LinkSetName failed: %s => %s : %s", trackedLink.Name, trackedLink.OriginalName, err)
+			recordRename(RenameRestore, containerID, containerName, trackedLink.ContainerLink, trackedLink.Index, trackedLink.Name, trackedLink.OriginalName, err)
+			return
+		}
+
+		unmarkLink(link)
+	}
+
+	recordRename(RenameRestore, containerID, containerName, trackedLink.ContainerLink, trackedLink.Index, trackedLink.Name, trackedLink.OriginalName, nil)
+
+	logger.WithFields(log.Fields{logFieldLink: trackedLink.OriginalName, logFieldMessageID: MessageIDLinkRestored}).
+		Infof("Link name restored: %s => %s", trackedLink.Name, trackedLink.OriginalName)
+}
+
+// Renames all tracked host links back to their original names, and stops tracking them.
+func revertAllLinks() {
+	for _, cs := range state.Containers() {
+		for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+			restoreLinkName(cs.ID, cs.Name, *cs.Links[index])
+			recordRelease(*cs.Links[index])
+		}
+		state.RemoveContainer(cs.ID)
+	}
+}
+
+// Returns the context with the timeout. Zero timeout means no timeout.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Inspects the container, retrying on transient Docker API errors, e.g. when the daemon is under load.
+func inspectContainer(ctx context.Context, rt ContainerRuntime, containerID string) (container.InspectResponse, error) {
+	if inspect, ok := inspectCache.Inspect(containerID, time.Now(), config.InspectCacheTTL); ok {
+		return inspect, nil
+	}
+
+	epoch := inspectCache.Epoch()
+	var inspect container.InspectResponse
+	err := retryWithBackoff(inspectRetryAttempts, inspectRetryDelay, func() error {
+		if err := waitDockerRateLimit(ctx); err != nil {
+			return PermanentError{err}
+		}
+
+		inspectCtx, cancel := withTimeout(ctx, config.DockerInspectTimeout)
+		defer cancel()
+
+		var err error
+		start := time.Now()
+		inspect, err = rt.Inspect(inspectCtx, containerID)
+		inspectDuration.ObserveSince(start)
+		if err != nil && (rt.IsNotFound(err) || ctx.Err() != nil) {
+			return PermanentError{err}
+		}
+		return err
+	})
+	if err == nil {
+		inspectCache.StoreInspect(containerID, inspect, epoch, time.Now())
+	}
+	return inspect, err
+}
+
+// Inspects running containers by startup_workers goroutines, passing each one to the function as soon as it is inspected.
+// The function is called concurrently, and the call returns once all the containers are passed.
+// The configuration read lock is held by the Docker requests only, and must not be held by the caller.
+func forEachRunningContainer(ctx context.Context, rt ContainerRuntime, filterArgs filters.Args, fn func(inspect container.InspectResponse)) {
+	configMu.RLock()
+	containers, err := listContainers(ctx, rt, filterArgs)
+	workers := config.StartupWorkers
+	configMu.RUnlock()
+	if err != nil {
+		log.Errorf("cli.ContainerList failed: %s", err)
+		return
+	}
+
+	var group errgroup.Group
+	group.SetLimit(max(workers, 1))
+	for _, container := range containers {
+		group.Go(func() error {
+			configMu.RLock()
+			inspect, err := inspectContainer(ctx, rt, container.ID)
+			configMu.RUnlock()
+			if err != nil {
+				errorfLimited(log.WithField(logFieldContainerID, container.ID), "cli.ContainerInspect failed for container ID %s: %s", container.ID, err)
+				return nil
+			}
+
+			fn(inspect)
+			return nil
+		})
+	}
+	group.Wait()
+}
+
+// Lists containers matching the filters.
+func listContainers(ctx context.Context, rt ContainerRuntime, filterArgs filters.Args) ([]container.Summary, error) {
+	if err := waitDockerRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	listCtx, cancel := withTimeout(ctx, config.DockerListTimeout)
+	defer cancel()
+	return rt.ListContainers(listCtx, filterArgs)
+}
+
+// Inspects running containers, sorted by name.
+func inspectRunningContainers(ctx context.Context, rt ContainerRuntime, filterArgs filters.Args) []container.InspectResponse {
+	var mu sync.Mutex
+	var inspects []container.InspectResponse
+	forEachRunningContainer(ctx, rt, filterArgs, func(inspect container.InspectResponse) {
+		mu.Lock()
+		inspects = append(inspects, inspect)
+		mu.Unlock()
+	})
+
+	// Sort containers by name to have predictable results between multiple runs,
+	// in case of rename failures.
+	slices.SortFunc(inspects, func(a, b container.InspectResponse) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return inspects
+}
+
+// Iterates over running containers matching the filters, updating the corresponding host link names.
+// When the dispatcher is provided, the containers are processed by its workers, otherwise by startup_workers
+// goroutines, renaming each one as soon as it is inspected unless ordered_startup is set.
+// Each container is processed by a single worker, and the function waits for completion.
+func processRunningContainers(ctx context.Context, rt ContainerRuntime, filterArgs filters.Args, dispatcher *Dispatcher) {
+	// May be called concurrently with the configuration reload.
+	configMu.RLock()
+	streaming := dispatcher == nil && !config.OrderedStartup
+	workers := config.StartupWorkers
+	configMu.RUnlock()
+
+	if streaming {
+		// Each container is renamed as soon as it is inspected, not waiting for the others.
+		forEachRunningContainer(ctx, rt, filterArgs, func(inspect container.InspectResponse) {
+			renameContainerLinks(ctx, rt, inspect, false)
+		})
+		return
+	}
+
+	inspects := inspectRunningContainers(ctx, rt, filterArgs)
+
+	if dispatcher == nil {
+		var group errgroup.Group
+		group.SetLimit(max(workers, 1))
+		for _, inspect := range inspects {
+			group.Go(func() error {
+				renameContainerLinks(ctx, rt, inspect, false)
+				return nil
+			})
+		}
+		group.Wait()
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, inspect := range inspects {
+		wg.Add(1)
+		dispatcher.Submit(ctx, inspect.ID, func(ctx context.Context) {
+			defer wg.Done()
+			renameContainerLinks(ctx, rt, inspect, false)
+		})
+	}
+	wg.Wait()
+}
+
+// Returns Docker container list filters built from the command flags.
+func containerFilterArgs(cCtx *cli.Context) filters.Args {
+	filterArgs := filters.NewArgs()
+	for flag, filter := range map[string]string{
+		"label":   "label",
+		"name":    "name",
+		"network": "network",
+		"image":   "ancestor",
+	} {
+		for _, value := range cCtx.StringSlice(flag) {
+			filterArgs.Add(filter, value)
+		}
+	}
+	return filterArgs
+}
+
+// Returns the function overriding the configuration keys by the command line options.
+func parseConfigFlags(ctx *cli.Context) (func(c *Config), error) {
+	var replacements []map[string]string
+	for _, replacement := range ctx.StringSlice("replacement") {
+		needle, value, ok := strings.Cut(replacement, "=")
+		if !ok {
+			return nil, fmt.Errorf("replacement must be in form from=to: %q", replacement)
+		}
+		replacements = append(replacements, map[string]string{needle: value})
+	}
+
+	return func(c *Config) {
+		if ctx.IsSet("separator") {
+			c.LinkIndexSeparator = ctx.String("separator")
+		}
+		if ctx.IsSet("prefix") {
+			c.LinkNamePrefix = ctx.String("prefix")
+		}
+		if ctx.IsSet("strip-link-prefix") {
+			c.ContainerLinkPrefixes = ctx.StringSlice("strip-link-prefix")
+		}
+		if ctx.IsSet("replacement") {
+			c.Replacements = replacements
+		}
+		if ctx.IsSet("ordered-startup") {
+			c.OrderedStartup = ctx.Bool("ordered-startup")
+		}
+	}, nil
+}
+
+// Runs the command line application with the arguments of the process, and exits on failure.
+func Main() {
+	defer reportPanic()
+
+	app := &cli.App{
+		Usage: "Tool for automatic renaming of Docker-created veth links",
+
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "log-level",
+				EnvVars: []string{"DVN_LOG_LEVEL"},
+				Usage:   "Set log level: trace, debug, info, warn, or error. Overrides the configuration file",
+			},
+			&cli.StringFlag{
+				Name:    "log-format",
+				EnvVars: []string{"DVN_LOG_FORMAT"},
+				Usage:   "Set log format: text, or json. Overrides the configuration file",
+			},
+			&cli.PathFlag{
+				Name:    "log-file",
+				EnvVars: []string{"DVN_LOG_FILE"},
+				Usage:   "Write log to the `file` with rotation, instead of stderr. Overrides the configuration file",
+			},
+			&cli.BoolFlag{
+				Name:    "log-journald",
+				EnvVars: []string{"DVN_LOG_JOURNALD"},
+				Usage:   "Send log to journald with structured fields, instead of stderr. Overrides the configuration file",
+			},
+			&cli.BoolFlag{
+				Name:    "no-color",
+				EnvVars: []string{"DVN_NO_COLOR"},
+				Usage:   "Disable colored log output, which is used when stderr is a terminal",
+			},
+			&cli.BoolFlag{
+				Name:    "trace-netlink",
+				EnvVars: []string{traceNetlinkEnv},
+				Usage:   "Log every netlink request and response with the link attributes, to debug kernel and driver behavior",
+			},
+			&cli.BoolFlag{
+				Name:    "verbose",
+				Aliases: []string{"vv"},
+				EnvVars: []string{"DVN_VERBOSE"},
+				Usage:   "Use verbose logging, same as --log-level trace",
+			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Aliases: []string{"n"},
+				EnvVars: []string{"DVN_DRY_RUN"},
+				Usage:   "Display the expected link name changes, but do not make actual renaming",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				EnvVars: []string{"DVN_OUTPUT"},
+				Value:   OutputTable,
+				Usage:   "Format of the command results: table, json, or yaml",
+			},
+			&cli.StringFlag{
+				Name:    "separator",
+				EnvVars: []string{"DVN_SEPARATOR"},
+				Usage:   "Separator in front of the link index. Overrides the configuration file",
+			},
+			&cli.StringFlag{
+				Name:    "prefix",
+				EnvVars: []string{"DVN_PREFIX"},
+				Usage:   "Prefix of the host link names. Overrides the configuration file",
+			},
+			&cli.StringSliceFlag{
+				Name:    "strip-link-prefix",
+				EnvVars: []string{"DVN_STRIP_LINK_PREFIX"},
+				Usage:   "Container link `prefix` to be removed, may be repeated. Overrides the configuration file",
+			},
+			&cli.StringSliceFlag{
+				Name:    "replacement",
+				EnvVars: []string{"DVN_REPLACEMENT"},
+				Usage:   "Replacement in form `from=to`, may be repeated. Overrides the configuration file",
+			},
+			&cli.BoolFlag{
+				Name:    "ordered-startup",
+				EnvVars: []string{"DVN_ORDERED_STARTUP"},
+				Usage:   "Rename running containers on startup in order of their names, once all of them are inspected. Overrides the configuration file",
+			},
+			&cli.PathFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				EnvVars: []string{"DVN_CONFIG"},
+				Value:   "/etc/docker-veth-namer.yml",
+				Usage:   "Specify path to the configuration file, or its HTTP(S) URL. The default file is optional",
+			},
+			&cli.PathFlag{
+				Name:    "config-public-key",
+				EnvVars: []string{"DVN_CONFIG_PUBLIC_KEY"},
+				Usage:   "Verify the remote configuration by the Ed25519 public key in the PEM `file`",
+			},
+			&cli.PathFlag{
+				Name:    "config-cache",
+				EnvVars: []string{"DVN_CONFIG_CACHE"},
+				Value:   remoteConfigCachePath,
+				Usage:   "Cache the remote configuration in the `file`, used when the URL is not reachable",
+			},
+			&cli.BoolFlag{
+				Name:    "no-config",
+				EnvVars: []string{"DVN_NO_CONFIG"},
+				Usage:   "Ignore the configuration file, and use the defaults",
+			},
+		},
+
+		Before: func(ctx *cli.Context) error {
+			// Set log level. The default configuration applies while the configuration file is loaded.
+			if ctx.IsSet("log-level") {
+				logLevelOverride = ctx.String("log-level")
+				if _, err := log.ParseLevel(logLevelOverride); err != nil {
+					return err
+				}
+			} else if ctx.Bool("verbose") {
+				logLevelOverride = log.TraceLevel.String()
+			}
+			if ctx.IsSet("log-format") {
+				logFormatOverride = ctx.String("log-format")
+				if err := parseLogFormat(logFormatOverride); err != nil {
+					return err
+				}
+			}
+			logFileOverride = ctx.Path("log-file")
+			logJournaldOverride = ctx.Bool("log-journald")
+			logNoColor = ctx.Bool("no-color") || len(os.Getenv("NO_COLOR")) > 0
+			config = defaultConfig()
+			applyLogLevel()
+			applyLogFormat()
+
+			if ctx.Bool("trace-netlink") {
+				enableNetlinkTrace()
+			}
+
+			// Set dry run flag.
+			dryRun = ctx.Bool("dry-run")
+
+			// Set output format.
+			var err error
+			outputFormat, err = parseOutputFormat(ctx.String("output"))
+			if err != nil {
+				return err
+			}
+
+			// Set config.
+			configFlags, err = parseConfigFlags(ctx)
+			if err != nil {
+				return err
+			}
+
+			configFilePath = ctx.Path("config")
+			remoteConfigPublicKeyPath = ctx.Path("config-public-key")
+			remoteConfigCachePath = ctx.Path("config-cache")
+			if ctx.Bool("no-config") {
+				configFilePath = ""
+			} else if !ctx.IsSet("config") {
+				// The default configuration file is optional.
+				if _, err := os.Stat(configFilePath); errors.Is(err, fs.ErrNotExist) {
+					log.Debugf("Configuration file is not found, using defaults: %s", configFilePath)
+					configFilePath = ""
+				}
+			}
+			config, err = loadConfig(configFilePath)
+			if err != nil {
+				// Diagnostic commands report the invalid configuration themselves,
+				// and the configuration is generated without one.
+				if !slices.Contains(configTolerantCommands, ctx.Args().First()) {
+					return err
+				}
+				configErr = err
+				config = defaultConfig()
+			}
+
+			applyLogLevel()
+			applyLogOutput()
+			applyLogFormat()
+			applyErrorReport()
+			applyDockerRateLimit()
+			applyRenameRateLimit()
+
+			return nil
+		},
+
+		Commands: []*cli.Command{
+			{
+				Name:  "version",
+				Usage: "Print program version",
+				Action: func(cCtx *cli.Context) error {
+					println(AppVersion)
+
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "Print status of the running daemon",
+				Action: func(cCtx *cli.Context) error {
+					return printStatus(config.ControlSocket)
+				},
+			},
+			{
+				Name:  "facts",
+				Usage: "Print the mappings and the status of the daemon as JSON facts for Ansible or Salt",
+				Action: func(cCtx *cli.Context) error {
+					return printFacts(os.Stdout)
+				},
+			},
+			{
+				Name:  "metrics",
+				Usage: "Print metrics and mappings of the running daemon in the Prometheus text format, and exit",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "influx",
+						EnvVars: []string{"DVN_METRICS_INFLUX"},
+						Usage:   "Print metrics and host link counters in the InfluxDB line protocol, for the exec input of Telegraf",
+					},
+					&cli.BoolFlag{
+						Name:    "execd",
+						EnvVars: []string{"DVN_METRICS_EXECD"},
+						Usage:   "Keep running, and print metrics in the InfluxDB line protocol on each line read from stdin, for the execd input of Telegraf",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					if cCtx.Bool("execd") {
+						return runInfluxExecd(os.Stdin, os.Stdout, config.ControlSocket)
+					}
+					if cCtx.Bool("influx") {
+						return printInflux(os.Stdout, config.ControlSocket)
+					}
+					return printMetrics(os.Stdout, config.ControlSocket)
+				},
+			},
+			{
+				Name:  "healthcheck",
+				Usage: "Check health of the running daemon, and exit with non-zero status if it is not healthy",
+				Action: func(cCtx *cli.Context) error {
+					return runHealthcheck(os.Stdout, config.ControlSocket)
+				},
+			},
+			{
+				Name:  "nagios",
+				Usage: "Check the daemon and the host links as a Nagios/Icinga plugin, with perfdata",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:    "warning-unnamed",
+						EnvVars: []string{"DVN_NAGIOS_WARNING_UNNAMED"},
+						Value:   0,
+						Usage:   "Warn when more than `count` host links are not named as expected, negative to disable",
+					},
+					&cli.IntFlag{
+						Name:    "critical-unnamed",
+						EnvVars: []string{"DVN_NAGIOS_CRITICAL_UNNAMED"},
+						Value:   2,
+						Usage:   "Critical when more than `count` host links are not named as expected, negative to disable",
+					},
+					&cli.IntFlag{
+						Name:    "warning-failed",
+						EnvVars: []string{"DVN_NAGIOS_WARNING_FAILED"},
+						Value:   0,
+						Usage:   "Warn when more than `count` renames failed within the window, negative to disable",
+					},
+					&cli.IntFlag{
+						Name:    "critical-failed",
+						EnvVars: []string{"DVN_NAGIOS_CRITICAL_FAILED"},
+						Value:   5,
+						Usage:   "Critical when more than `count` renames failed within the window, negative to disable",
+					},
+					&cli.DurationFlag{
+						Name:    "window",
+						EnvVars: []string{"DVN_NAGIOS_WINDOW"},
+						Value:   time.Hour,
+						Usage:   "Count failed renames within the `duration`",
+					},
+					&cli.DurationFlag{
+						Name:    "warning-stale",
+						EnvVars: []string{"DVN_NAGIOS_WARNING_STALE"},
+						Usage:   "Warn when no Docker event was received for the `duration`, zero to disable",
+					},
+					&cli.DurationFlag{
+						Name:    "critical-stale",
+						EnvVars: []string{"DVN_NAGIOS_CRITICAL_STALE"},
+						Usage:   "Critical when no Docker event was received for the `duration`, zero to disable",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					return runNagios(os.Stdout, cCtx.Duration("window"), NagiosThresholds{
+						WarningUnnamed:  cCtx.Int("warning-unnamed"),
+						CriticalUnnamed: cCtx.Int("critical-unnamed"),
+						WarningFailed:   cCtx.Int("warning-failed"),
+						CriticalFailed:  cCtx.Int("critical-failed"),
+						WarningStale:    cCtx.Duration("warning-stale"),
+						CriticalStale:   cCtx.Duration("critical-stale"),
+					})
+				},
+			},
+			{
+				Name:      "check",
+				Usage:     "Validate the configuration file, and exit with non-zero status on problems",
+				ArgsUsage: "[container-name[:link-name]...]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "strict",
+						EnvVars: []string{"DVN_CHECK_STRICT"},
+						Usage:   "Treat warnings as problems",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					return runCheck(configErr, cCtx.Args().Slice(), cCtx.Bool("strict"))
+				},
+			},
+			{
+				Name:  "generate-config",
+				Usage: "Print the default configuration file with comments",
+				Flags: []cli.Flag{
+					&cli.PathFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						EnvVars: []string{"DVN_GENERATE_CONFIG_FILE"},
+						Usage:   "Write the configuration to the `file` instead of stdout",
+					},
+					&cli.BoolFlag{
+						Name:    "force",
+						EnvVars: []string{"DVN_GENERATE_CONFIG_FORCE"},
+						Usage:   "Overwrite the existing file",
+					},
+					&cli.BoolFlag{
+						Name:    "from-containers",
+						EnvVars: []string{"DVN_GENERATE_CONFIG_FROM_CONTAINERS"},
+						Usage:   "Suggest replacements for long words in names of currently running containers",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					var suggested []map[string]string
+					if cCtx.Bool("from-containers") {
+						rt, err := newContainerRuntime()
+						if err != nil {
+							log.Fatalf("Failed to connect to Docker API: %s", err)
+						}
+						defer rt.Close()
+
+						names, err := runningContainerNames(context.Background(), rt)
+						if err != nil {
+							return err
+						}
+
+						defaults, err := parseDefaultConfigFile()
+						if err != nil {
+							return err
+						}
+						suggested = suggestReplacements(names, defaults.Replacements)
+					}
+
+					return writeGeneratedConfig(os.Stdout, cCtx.Path("file"), generateConfig(suggested), cCtx.Bool("force"))
+				},
+			},
+			{
+				Name:  "plan",
+				Usage: "Write the renames of host links of currently running containers to be reviewed, and applied by the apply command",
+				Flags: []cli.Flag{
+					&cli.PathFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						EnvVars: []string{"DVN_PLAN_FILE"},
+						Usage:   "Write the plan to the `file` instead of stdout",
+					},
+					&cli.BoolFlag{
+						Name:    "force",
+						EnvVars: []string{"DVN_PLAN_FORCE"},
+						Usage:   "Overwrite the existing file",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					plan := makePlan(runningLinkMappings(context.Background(), rt))
+					if err := writePlan(os.Stdout, cCtx.Path("file"), plan, cCtx.Bool("force")); err != nil {
+						return err
+					}
+
+					if len(cCtx.Path("file")) == 0 {
+						return nil
+					}
+					return printOutput(os.Stdout, plan, plan.print)
+				},
+			},
+			{
+				Name:      "apply",
+				Usage:     "Rename host links exactly as written by the plan command, refusing if the environment drifted",
+				ArgsUsage: "<plan-file>",
+				Action: func(cCtx *cli.Context) error {
+					if cCtx.NArg() != 1 {
+						return fmt.Errorf("expected arguments: %s", cCtx.Command.ArgsUsage)
+					}
+
+					plan, err := readPlan(cCtx.Args().First())
+					if err != nil {
+						return err
+					}
+
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					summary, err := applyPlan(context.Background(), rt, plan)
+					if err != nil {
+						return err
+					}
+
+					result := OneshotResult{RenameSummary: summary, Containers: state.Mappings()}
+					if err := printOutput(os.Stdout, result, result.printSummary); err != nil {
+						return err
+					}
+
+					if result.Failed > 0 {
+						return fmt.Errorf("renaming failed for %d links", result.Failed)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "doctor",
+				Usage: "Diagnose the environment: Docker connectivity, capabilities, configuration, conflicting renaming",
+				Action: func(cCtx *cli.Context) error {
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					return runDoctor(context.Background(), rt, configErr)
+				},
+			},
+			{
+				Name:  "selftest",
+				Usage: "Run the renaming pipeline against a throwaway veth pair, to validate the kernel and capability prerequisites",
+				Action: func(cCtx *cli.Context) error {
+					return runSelftest(os.Stdout)
+				},
+			},
+			{
+				Name:  "history",
+				Usage: "Print recent rename operations of the running daemon, or from the audit log",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "container",
+						EnvVars: []string{"DVN_HISTORY_CONTAINER"},
+						Usage:   "Print operations of the container with the `name` or ID only",
+					},
+					&cli.IntFlag{
+						Name:    "limit",
+						EnvVars: []string{"DVN_HISTORY_LIMIT"},
+						Value:   50,
+						Usage:   "Print at most `N` most recent operations. Zero prints all",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					return printHistory(os.Stdout, cCtx.String("container"), cCtx.Int("limit"))
+				},
+			},
+			{
+				Name:  "watch",
+				Usage: "Show containers, link mappings, and recent events of the running daemon interactively",
+				Action: func(cCtx *cli.Context) error {
+					return runWatch(config.ControlSocket)
+				},
+			},
+			{
+				Name:  "pause",
+				Usage: "Pause renaming by the running daemon (maintenance mode)",
+				Action: func(cCtx *cli.Context) error {
+					return requestPause(true)
+				},
+			},
+			{
+				Name:  "resume",
+				Usage: "Resume renaming by the running daemon",
+				Action: func(cCtx *cli.Context) error {
+					return requestPause(false)
+				},
+			},
+			{
+				Name:  "resync",
+				Usage: "Make the running daemon process all running containers",
+				Action: func(cCtx *cli.Context) error {
+					return controlCommand(config.ControlSocket, "/resync")
+				},
+			},
+			{
+				Name:  "reload",
+				Usage: "Make the running daemon reload the configuration file",
+				Action: func(cCtx *cli.Context) error {
+					return controlCommand(config.ControlSocket, "/reload")
+				},
+			},
+			{
+				Name:      "oneshot",
+				Usage:     "Update veth links for currently running containers, and exit immediately",
+				ArgsUsage: "[container...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "label",
+						EnvVars: []string{"DVN_ONESHOT_LABEL"},
+						Usage:   "Process only containers with the label, in form key or key=value",
+					},
+					&cli.StringSliceFlag{
+						Name:    "name",
+						EnvVars: []string{"DVN_ONESHOT_NAME"},
+						Usage:   "Process only containers with the `name` matching",
+					},
+					&cli.StringSliceFlag{
+						Name:    "network",
+						EnvVars: []string{"DVN_ONESHOT_NETWORK"},
+						Usage:   "Process only containers connected to the `network`",
+					},
+					&cli.StringSliceFlag{
+						Name:    "image",
+						EnvVars: []string{"DVN_ONESHOT_IMAGE"},
+						Usage:   "Process only containers created from the `image` or its descendants",
+					},
+					&cli.BoolFlag{
+						Name:    "fail-fast",
+						EnvVars: []string{"DVN_ONESHOT_FAIL_FAST"},
+						Usage:   "Stop on the first failure",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					filterArgs := containerFilterArgs(cCtx)
+					if cCtx.NArg() > 0 && filterArgs.Len() > 0 {
+						return errors.New("container arguments cannot be combined with filter flags")
+					}
+
+					setupNotificationSinks(false)
+
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					log.Debug("Connected to Docker API")
+
+					result := runOneshot(context.Background(), rt, cCtx.Args().Slice(), filterArgs, cCtx.Bool("fail-fast"))
+					if err := printOutput(os.Stdout, result, result.printSummary); err != nil {
+						return err
+					}
+
+					if result.Failed > 0 {
+						return fmt.Errorf("renaming failed for %d links", result.Failed)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "preview",
+				Usage:     "Print the host link name for the container link, without touching Docker or network links",
+				ArgsUsage: "<container-name> [link-name]",
+				Action: func(cCtx *cli.Context) error {
+					if cCtx.NArg() < 1 || cCtx.NArg() > 2 {
+						return fmt.Errorf("expected arguments: %s", cCtx.Command.ArgsUsage)
+					}
+
+					containerName := cCtx.Args().Get(0)
+					containerLinkName := "eth0"
+					if cCtx.NArg() == 2 {
+						containerLinkName = cCtx.Args().Get(1)
+					}
+
+					linkName := makeLinkName(containerName, containerLinkName)
+					if len(linkName) == 0 {
+						return fmt.Errorf("cannot make host link name: %s %s", containerName, containerLinkName)
+					}
+
+					preview := LinkMapping{ContainerName: containerName, ContainerLink: containerLinkName, TargetName: linkName}
+					return printOutput(os.Stdout, preview, func(w io.Writer) error {
+						_, err := fmt.Fprintln(w, linkName)
+						return err
+					})
+				},
+			},
+			{
+				Name:      "explain",
+				Usage:     "Print step by step how the host link name is made for the container link",
+				ArgsUsage: "<container-name> [link-name]",
+				Action: func(cCtx *cli.Context) error {
+					if cCtx.NArg() < 1 || cCtx.NArg() > 2 {
+						return fmt.Errorf("expected arguments: %s", cCtx.Command.ArgsUsage)
+					}
+
+					containerLinkName := "eth0"
+					if cCtx.NArg() == 2 {
+						containerLinkName = cCtx.Args().Get(1)
+					}
+
+					trace := explainLinkName(cCtx.Args().Get(0), containerLinkName)
+					return printOutput(os.Stdout, trace, func(w io.Writer) error {
+						return printNameTrace(w, trace)
+					})
+				},
+			},
+			{
+				Name:      "revert",
+				Usage:     "Rename host links back to the original names, and exit immediately",
+				ArgsUsage: "[container...]",
+				Action: func(cCtx *cli.Context) error {
+					// The running daemon reverts the links itself, to not race with its renames.
+					err := requestRevert(config.ControlSocket, cCtx.Args().Slice())
+					if !errors.Is(err, errDaemonNotRunning) {
+						return err
+					}
+
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					return revertLinks(context.Background(), rt, cCtx.Args().Slice())
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "Print the mapping between container links and host links of currently running containers",
+				Action: func(cCtx *cli.Context) error {
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					log.Debug("Connected to Docker API")
+
+					mappings := runningLinkMappings(context.Background(), rt)
+					return printOutput(os.Stdout, mappings, func(w io.Writer) error {
+						return printLinkMappings(w, mappings)
+					})
+				},
+			},
+			{
+				Name:  "verify",
+				Usage: "Check that host links of currently running containers are named according to the configuration",
+				Action: func(cCtx *cli.Context) error {
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					mappings := runningLinkMappings(context.Background(), rt)
+					mismatches := mismatchedLinkMappings(mappings)
+					err = printOutput(os.Stdout, mismatches, func(w io.Writer) error {
+						if len(mismatches) == 0 {
+							_, err := fmt.Fprintf(w, "OK: %d host links are named as expected\n", len(mappings))
+							return err
+						}
+						return printLinkMappings(w, mismatches)
+					})
+					if err != nil {
+						return err
+					}
+
+					if len(mismatches) > 0 {
+						return fmt.Errorf("%d of %d host links are not named as expected", len(mismatches), len(mappings))
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "lookup",
+				Usage:     "Print the container owning the host link",
+				ArgsUsage: "<host-link-name>",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:    "ifindex",
+						EnvVars: []string{"DVN_LOOKUP_IFINDEX"},
+						Usage:   "Look up the host link by the interface `index` instead of the name",
+					},
+					&cli.StringFlag{
+						Name:    "at",
+						EnvVars: []string{"DVN_LOOKUP_AT"},
+						Usage:   "Look up the container owning the host link at the RFC 3339 `time`, in the timeline database",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					index := cCtx.Int("ifindex")
+					if index < 0 || cCtx.NArg() > 1 || (index > 0) == (cCtx.NArg() == 1) {
+						return fmt.Errorf("expected arguments: %s, or --ifindex", cCtx.Command.ArgsUsage)
+					}
+
+					if cCtx.IsSet("at") {
+						at, err := time.Parse(time.RFC3339, cCtx.String("at"))
+						if err != nil {
+							return fmt.Errorf("invalid time: %w", err)
+						}
+
+						result, err := lookupLinkAt(cCtx.Args().First(), index, at)
+						if err != nil {
+							return err
+						}
+						return printOutput(os.Stdout, result, result.print)
+					}
+
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+					defer rt.Close()
+
+					result, err := lookupLink(context.Background(), rt, cCtx.Args().First(), index)
+					if err != nil {
+						return err
+					}
+					return printOutput(os.Stdout, result, result.print)
+				},
+			},
+			{
+				Name:  "capture-map",
+				Usage: "Print the name assignments of the host links by the interface index and the time range, from the audit log",
+				Action: func(cCtx *cli.Context) error {
+					return printCaptureMap(os.Stdout)
+				},
+			},
+			{
+				Name:  "capture-annotate",
+				Usage: "Annotate the interface list read from stdin, e.g. the output of \"tcpdump -D\", with the containers owning the host links",
+				Action: func(cCtx *cli.Context) error {
+					return annotateInterfaces(os.Stdin, os.Stdout, daemonMappings())
+				},
+			},
+			{
+				Name:  "listen",
+				Usage: "Starts listening to Docker events",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "api-listen",
+						EnvVars: []string{"DVN_LISTEN_API_LISTEN"},
+						Usage:   "Serve the REST API at the TCP `address`, e.g. 127.0.0.1:9470",
+					},
+					&cli.StringFlag{
+						Name:    "grpc-listen",
+						EnvVars: []string{"DVN_LISTEN_GRPC_LISTEN"},
+						Usage:   "Serve the gRPC API at the TCP `address`, e.g. 127.0.0.1:9471",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					apiListenAddress = cCtx.String("api-listen")
+					grpcListenAddress = cCtx.String("grpc-listen")
+
+					// The D-Bus name is acquired upfront to answer the method calls.
+					setupNotificationSinks(true)
+
+					rt, err := newContainerRuntime()
+					if err != nil {
+						log.Fatalf("Failed to connect to Docker API: %s", err)
+					}
+
+					log.Debug("Connected to Docker API")
+
+					// Stop gracefully on termination signals.
+					ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+					defer stop()
+
+					listenToDockerEvents(ctx, rt)
+
+					log.Info("Shutting down")
+
+					if config.RevertOnExit {
+						revertAllLinks()
+					}
+
+					return nil
+				},
+			},
+		},
+
+		DefaultCommand: "listen",
+		Copyright:      "2026 Aleksei Ilin",
+		Version:        AppVersion,
+	}
+
+	err := app.Run(os.Args)
+	logFile.Close()
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Fatal(err)
+	}
+}
@@ -18,11 +18,13 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 // SOFTWARE.
 
-package main
+package app
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +99,35 @@ func TestReplaceMorphemes(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryWithBackoff(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(3, time.Millisecond, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = retryWithBackoff(3, time.Millisecond, func() error {
+		calls++
+		return fmt.Errorf("attempt %d", calls)
+	})
+	assert.EqualError(t, err, "attempt 3")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryWithBackoffPermanentError(t *testing.T) {
+	calls := 0
+	permanentErr := errors.New("permanent")
+	err := retryWithBackoff(3, time.Millisecond, func() error {
+		calls++
+		return PermanentError{permanentErr}
+	})
+	assert.Equal(t, permanentErr, err)
+	assert.Equal(t, 1, calls)
+}
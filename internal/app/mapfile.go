@@ -0,0 +1,98 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Host link mapping written to the mapping file, one per renamed host link.
+type MappingRecord struct {
+	Name          string `json:"name"`
+	OriginalName  string `json:"original_name"`
+	Index         int    `json:"ifindex"`
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	ContainerLink string `json:"container_link"`
+	Image         string `json:"image"`
+	Network       string `json:"network"`
+}
+
+// Column names of the CSV mapping file.
+var mappingRecordColumns = []string{"name", "original_name", "ifindex", "container_id", "container_name", "container_link", "image", "network"}
+
+// Returns the record of each host link, ordered by the link name.
+func mappingRecords(mappings []ContainerMapping) []MappingRecord {
+	records := []MappingRecord{}
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			records = append(records, MappingRecord{
+				Name:          link.Name,
+				OriginalName:  link.OriginalName,
+				Index:         link.Index,
+				ContainerID:   mapping.ID,
+				ContainerName: strings.TrimPrefix(mapping.Name, "/"),
+				ContainerLink: link.ContainerLink,
+				Image:         link.Image,
+				Network:       link.Network,
+			})
+		}
+	}
+
+	slices.SortFunc(records, func(a, b MappingRecord) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return records
+}
+
+// Writes the host link mappings to the file atomically, so readers never see a partial file.
+// The file is written as CSV with the header line when its extension is ".csv", or as a JSON array otherwise.
+func saveMappingFile(path string, mappings []ContainerMapping) error {
+	records := mappingRecords(mappings)
+
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, data, 0o644)
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write(mappingRecordColumns)
+	for _, r := range records {
+		w.Write([]string{r.Name, r.OriginalName, strconv.Itoa(r.Index), r.ContainerID, r.ContainerName, r.ContainerLink, r.Image, r.Network})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	// The trailing new line is added on write.
+	return writeFileAtomic(path, bytes.TrimSuffix(b.Bytes(), []byte("\n")), 0o644)
+}
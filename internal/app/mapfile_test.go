@@ -0,0 +1,74 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveMappingFile(t *testing.T) {
+	mappings := []ContainerMapping{
+		{ID: "0123", Name: "/web", Links: []LinkState{
+			{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vweb0", LinkLabels: LinkLabels{Image: "nginx", Network: "frontend"}},
+		}},
+		{ID: "4567", Name: "/db", Links: []LinkState{
+			{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0", LinkLabels: LinkLabels{Image: "mariadb", Network: "back,end"}},
+		}},
+	}
+
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "map.json")
+	require.NoError(t, saveMappingFile(jsonPath, mappings))
+	data, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+
+	var records []MappingRecord
+	require.NoError(t, json.Unmarshal(data, &records))
+	assert.Equal(t, []MappingRecord{
+		{Name: "vdb0", OriginalName: "veth2", Index: 12, ContainerID: "4567", ContainerName: "db", ContainerLink: "eth0", Image: "mariadb", Network: "back,end"},
+		{Name: "vweb0", OriginalName: "veth1", Index: 10, ContainerID: "0123", ContainerName: "web", ContainerLink: "eth0", Image: "nginx", Network: "frontend"},
+	}, records)
+
+	info, err := os.Stat(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	csvPath := filepath.Join(dir, "map.csv")
+	require.NoError(t, saveMappingFile(csvPath, mappings))
+	data, err = os.ReadFile(csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, `name,original_name,ifindex,container_id,container_name,container_link,image,network
+vdb0,veth2,12,4567,db,eth0,mariadb,"back,end"
+vweb0,veth1,10,0123,web,eth0,nginx,frontend
+`, string(data))
+
+	require.NoError(t, saveMappingFile(jsonPath, nil))
+	data, err = os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// Prefix of the ownership marker, set as the alias of the renamed host links.
+const ownershipMarkerPrefix = "docker-veth-namer:"
+
+// Returns the ownership marker of the host link named by the current configuration:
+// the prefix, the container ID, and the hash of the naming configuration along with the naming input and output.
+// The marker of a link matches as long as the same name would be made for it.
+func ownershipMarker(req NameRequest, linkName string) string {
+	input, _ := json.Marshal(struct {
+		Strategy string      `json:"strategy"`
+		Command  string      `json:"command,omitempty"`
+		Module   string      `json:"module,omitempty"`
+		Options  any         `json:"options"`
+		Request  NameRequest `json:"request"`
+		Name     string      `json:"name"`
+	}{config.NamingStrategy, config.NamingCommand, config.NamingModule, namingOptions(config), req, linkName})
+	sum := sha256.Sum256(input)
+
+	id := req.ContainerID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return ownershipMarkerPrefix + id + ":" + hex.EncodeToString(sum[:8])
+}
+
+// Returns whether the alias is the ownership marker, or empty, so it can be replaced.
+// Aliases set by others are kept.
+func isOwnAlias(alias string) bool {
+	return len(alias) == 0 || strings.HasPrefix(alias, ownershipMarkerPrefix)
+}
+
+// Sets the ownership marker as the alias of the host link, unless the link has a foreign alias.
+func markLink(link netlink.Link, marker string) {
+	alias := link.Attrs().Alias
+	if alias == marker || !isOwnAlias(alias) {
+		return
+	}
+
+	if err := nlLinkSetAlias(link, marker); err != nil {
+		log.WithFields(log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: link.Attrs().Name}).
+			Debugf("netlink.LinkSetAlias failed: %s : %s", marker, err)
+	}
+}
+
+// Drops the ownership marker of the host link, if set.
+func unmarkLink(link netlink.Link) {
+	alias := link.Attrs().Alias
+	if len(alias) == 0 || !isOwnAlias(alias) {
+		return
+	}
+
+	if err := nlLinkSetAlias(link, ""); err != nil {
+		log.WithFields(log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: link.Attrs().Name}).
+			Debugf("netlink.LinkSetAlias failed: %s", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+// Morphs the names, counting the calls.
+type countingStrategy struct {
+	calls int
+}
+
+func (s *countingStrategy) LinkName(req NameRequest, trace *naming.Trace) (string, error) {
+	s.calls++
+	return MorphStrategy{}.LinkName(req, trace)
+}
+
+func TestOwnershipMarker(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+
+	req := NameRequest{ContainerID: "1234567890abcdef", ContainerName: "/web", ContainerLink: "eth0"}
+	marker := ownershipMarker(req, "vweb0")
+	assert.True(t, strings.HasPrefix(marker, "docker-veth-namer:1234567890ab:"))
+	assert.Equal(t, marker, ownershipMarker(req, "vweb0"))
+	assert.NotEqual(t, marker, ownershipMarker(req, "vweb1"))
+
+	config.LinkNamePrefix = "x"
+	assert.NotEqual(t, marker, ownershipMarker(req, "vweb0"))
+}
+
+func TestRenameContainerLinksMarker(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	config.NamingStrategy = "counting"
+	config.OwnershipMarker = true
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	strategy := &countingStrategy{}
+	namingStrategies["counting"] = strategy
+	t.Cleanup(func() { delete(namingStrategies, "counting") })
+
+	f := useFakeNetlink(t)
+	f.addVeth(10, "veth1a2b3c4", "/var/run/docker/netns/web", "eth0", "")
+	f.addVeth(11, "veth5d6e7f8", "/var/run/docker/netns/web", "eth1", "")
+	// The alias set by others is kept.
+	f.links[11] = netlink.LinkAttrs{Index: 11, Name: "veth5d6e7f8", Alias: "uplink"}
+	inspect := bridgeContainer("1234", "/web", "/var/run/docker/netns/web", "")
+
+	summary := renameContainerLinks(context.Background(), newFakeRuntime(), inspect, false)
+	assert.Equal(t, 2, summary.Renamed)
+	assert.Equal(t, 2, strategy.calls)
+	assert.True(t, strings.HasPrefix(f.links[10].Alias, ownershipMarkerPrefix))
+	assert.Equal(t, "uplink", f.links[11].Alias)
+
+	// The name of the marked link is not made again.
+	inspectCache.Clear()
+	summary = renameContainerLinks(context.Background(), newFakeRuntime(), inspect, false)
+	assert.Equal(t, 2, summary.Unchanged)
+	assert.Equal(t, 3, strategy.calls)
+
+	// The marker of another configuration does not match.
+	config.LinkIndexSeparator = "-"
+	inspectCache.Clear()
+	summary = renameContainerLinks(context.Background(), newFakeRuntime(), inspect, false)
+	assert.Equal(t, 2, summary.Renamed)
+	assert.Equal(t, 5, strategy.calls)
+	assert.Equal(t, "vweb-0", f.links[10].Name)
+}
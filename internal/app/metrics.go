@@ -0,0 +1,398 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Timeout of the metrics requests.
+const metricsRequestTimeout = 5 * time.Second
+
+// Serves the metrics in the Prometheus text exposition format.
+type MetricsServer struct {
+	server *http.Server
+}
+
+// Starts serving the metrics, and the health of the event loop, at the TCP address.
+func newMetricsServer(address string, l *EventLoop) (*MetricsServer, error) {
+	listener, err := listenTCP(address)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		daemon := l.daemonMetrics(r.Context())
+		if err := writeMetrics(w, state.Mappings(), &daemon); err != nil {
+			log.Debugf("Cannot write metrics: %s", err)
+		}
+	})
+
+	mux.HandleFunc("GET /healthz", healthHandler(l))
+	mux.HandleFunc("GET /events", eventStreamHandler(l))
+
+	if config.WebUI {
+		mux.HandleFunc("GET /{$}", dashboardHandler(l))
+	}
+
+	s := &MetricsServer{
+		server: &http.Server{Handler: mux, ReadHeaderTimeout: metricsRequestTimeout},
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Metrics server failed: %s", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Stops serving. Nil server is ignored.
+func (s *MetricsServer) Close() {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsRequestTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Errorf("Metrics server shutdown failed: %s", err)
+	}
+}
+
+// Escapes the label value per the text exposition format.
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Internal indicators of the daemon, revealing saturation or stalls.
+type DaemonMetrics struct {
+	// Whether the event loop responds to requests. Other loop indicators are zero otherwise.
+	EventLoopAlive     bool   `json:"event_loop_alive"`
+	PendingTasks       int    `json:"pending_tasks"`
+	QueuedContainers   int    `json:"queued_containers"`
+	PendingRetries     int64  `json:"pending_retries"`
+	EventQueueDepth    int    `json:"event_queue_depth"`
+	EventQueueCapacity int    `json:"event_queue_capacity"`
+	EventsDropped      uint64 `json:"events_dropped"`
+	EventReadsDeferred uint64 `json:"event_reads_deferred"`
+	// Seconds since the last processed Docker event. Negative if no events were processed.
+	LastEventAgeSeconds float64 `json:"last_event_age_seconds"`
+	Goroutines          int     `json:"goroutines"`
+}
+
+// Returns the internal indicators, querying the event loop.
+func (l *EventLoop) daemonMetrics(ctx context.Context) DaemonMetrics {
+	status, alive, _ := l.queryStatus(ctx)
+	m := DaemonMetrics{
+		EventLoopAlive:      alive,
+		PendingTasks:        status.PendingTasks,
+		QueuedContainers:    status.QueuedContainers,
+		PendingRetries:      pendingRetries.Load(),
+		EventQueueDepth:     status.EventQueueDepth,
+		EventQueueCapacity:  status.EventQueueCapacity,
+		EventsDropped:       status.EventsDropped,
+		EventReadsDeferred:  status.EventReadsDeferred,
+		LastEventAgeSeconds: -1,
+		Goroutines:          runtime.NumGoroutine(),
+	}
+	if !status.LastEventTime.IsZero() {
+		m.LastEventAgeSeconds = time.Since(status.LastEventTime).Seconds()
+	}
+	return m
+}
+
+// Writes the internal indicators in the text exposition format.
+func (m DaemonMetrics) write(w io.Writer) {
+	gauge := func(name string, help string, value string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, value)
+	}
+	counter := func(name string, help string, value uint64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+	}
+
+	alive := 0
+	if m.EventLoopAlive {
+		alive = 1
+	}
+	gauge("dvn_event_loop_up", "Whether the event loop responds to requests.", strconv.Itoa(alive))
+	gauge("dvn_pending_tasks", "Number of the event processing tasks queued and not started yet.", strconv.Itoa(m.PendingTasks))
+	gauge("dvn_queued_containers", "Number of the containers with pending tasks waiting for a free worker.", strconv.Itoa(m.QueuedContainers))
+	gauge("dvn_pending_retries", "Number of the operations waiting for the next retry attempt.", strconv.FormatInt(m.PendingRetries, 10))
+	gauge("dvn_event_queue_depth", "Number of the Docker events awaiting the processing.", strconv.Itoa(m.EventQueueDepth))
+	gauge("dvn_event_queue_capacity", "Maximum number of the Docker events awaiting the processing.", strconv.Itoa(m.EventQueueCapacity))
+	counter("dvn_events_dropped_total", "Number of the Docker events dropped as the event queue was full.", m.EventsDropped)
+	counter("dvn_event_reads_deferred_total", "Number of times reading the Docker events was deferred as the event queue was full.", m.EventReadsDeferred)
+	if m.LastEventAgeSeconds >= 0 {
+		gauge("dvn_last_event_age_seconds", "Time since the last processed Docker event.", strconv.FormatFloat(m.LastEventAgeSeconds, 'f', 3, 64))
+	}
+	gauge("dvn_goroutines", "Number of the goroutines.", strconv.Itoa(m.Goroutines))
+}
+
+// Writes the metrics of the tracked links, and the internal indicators when given.
+// The info metric allows to join the per-interface metrics, e.g. of node_exporter, with the container identity.
+func writeMetrics(w io.Writer, mappings []ContainerMapping, daemon *DaemonMetrics) error {
+	fmt.Fprintln(w, "# HELP dvn_interface_info Host link renamed by docker-veth-namer, labeled with the owning container.")
+	fmt.Fprintln(w, "# TYPE dvn_interface_info gauge")
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			fmt.Fprintf(w, "dvn_interface_info{device=\"%s\",original_device=\"%s\",ifindex=\"%d\",container=\"%s\",container_id=\"%s\",container_link=\"%s\",image=\"%s\",network=\"%s\"} 1\n",
+				metricLabelReplacer.Replace(link.Name),
+				metricLabelReplacer.Replace(link.OriginalName),
+				link.Index,
+				metricLabelReplacer.Replace(strings.TrimPrefix(mapping.Name, "/")),
+				metricLabelReplacer.Replace(mapping.ID),
+				metricLabelReplacer.Replace(link.ContainerLink),
+				metricLabelReplacer.Replace(link.Image),
+				metricLabelReplacer.Replace(link.Network))
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "# HELP dvn_tracked_links Number of host links tracked by docker-veth-namer.\n# TYPE dvn_tracked_links gauge\ndvn_tracked_links %d\n",
+		countLinks(mappings))
+	if err != nil {
+		return err
+	}
+
+	inspectDuration.write(w, "dvn_inspect_duration_seconds", "Duration of the Docker container inspection attempts.")
+	enumerationDuration.write(w, "dvn_netns_enumeration_duration_seconds", "Duration of the container network namespace link enumeration.")
+	renameDuration.write(w, "dvn_rename_duration_seconds", "Duration of the netlink host link renaming.")
+	skipCounter.write(w, "dvn_skipped_total", "reason", "Number of the containers and links skipped, by reason.")
+	cacheHits.write(w, "dvn_cache_hits_total", "cache", "Number of the lookups served from the inspect cache, by cache.")
+	cacheMisses.write(w, "dvn_cache_misses_total", "cache", "Number of the lookups missing the inspect cache, by cache.")
+	if daemon != nil {
+		daemon.write(w)
+	}
+	return nil
+}
+
+// Current values of the metrics, for the structured output.
+type MetricsSnapshot struct {
+	TrackedLinks int `json:"tracked_links"`
+	// Durations of the processing pipeline stages: inspect, netns_enumeration, and rename.
+	Durations map[string]HistogramSnapshot `json:"durations"`
+	// Numbers of the skipped containers and links, by reason.
+	Skipped map[string]uint64 `json:"skipped"`
+	// Numbers of the cache lookups, by cache: inspect, and links.
+	CacheHits   map[string]uint64  `json:"cache_hits"`
+	CacheMisses map[string]uint64  `json:"cache_misses"`
+	Mappings    []ContainerMapping `json:"mappings"`
+	Daemon      *DaemonMetrics     `json:"daemon,omitempty"`
+}
+
+// Returns the current values of the metrics.
+func metricsSnapshot(mappings []ContainerMapping) MetricsSnapshot {
+	return MetricsSnapshot{
+		TrackedLinks: countLinks(mappings),
+		Durations: map[string]HistogramSnapshot{
+			"inspect":           inspectDuration.snapshot(),
+			"netns_enumeration": enumerationDuration.snapshot(),
+			"rename":            renameDuration.snapshot(),
+		},
+		Skipped:     skipCounter.snapshot(),
+		CacheHits:   cacheHits.snapshot(),
+		CacheMisses: cacheMisses.snapshot(),
+		Mappings:    mappings,
+	}
+}
+
+// Prints the metrics of the running daemon, queried via the control socket:
+// in the Prometheus text exposition format, or as a structured document with --output json or yaml.
+func printMetrics(w io.Writer, path string) error {
+	if len(path) == 0 {
+		return errors.New("control socket is disabled in the configuration")
+	}
+
+	var snapshot MetricsSnapshot
+	if outputFormat != OutputTable {
+		if err := controlRequest(path, http.MethodGet, "/metrics?format=json", &snapshot); err != nil {
+			return err
+		}
+	}
+
+	return printOutput(w, snapshot, func(w io.Writer) error {
+		return controlRequestText(path, "/metrics", w)
+	})
+}
+
+// Returns the number of links in the mappings.
+func countLinks(mappings []ContainerMapping) int {
+	count := 0
+	for _, mapping := range mappings {
+		count += len(mapping.Links)
+	}
+	return count
+}
+
+// Upper bounds of the duration histogram buckets, in seconds.
+var durationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Cumulative histogram of durations, in the Prometheus sense.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+// Records the duration.
+func (h *Histogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seconds := d.Seconds()
+	for i, bound := range h.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Records the time passed since the start.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start))
+}
+
+// Count and sum of the observed durations.
+type HistogramSnapshot struct {
+	Count      uint64  `json:"count"`
+	SumSeconds float64 `json:"sum_seconds"`
+}
+
+// Returns the count and sum of the observed durations.
+func (h *Histogram) snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return HistogramSnapshot{Count: h.count, SumSeconds: h.sum}
+}
+
+// Writes the histogram in the text exposition format.
+func (h *Histogram) write(w io.Writer, name string, help string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// Durations of the processing pipeline stages.
+var (
+	inspectDuration     = newHistogram(durationBuckets)
+	enumerationDuration = newHistogram(durationBuckets)
+	renameDuration      = newHistogram(durationBuckets)
+)
+
+// Reasons of skipping containers and links.
+const (
+	SkipHostNetwork      = "host_network"
+	SkipNoneNetwork      = "none_network"
+	SkipNoSandbox        = "no_sandbox"
+	SkipDefaultNamespace = "default_namespace"
+	SkipNoVethLinks      = "no_veth_links"
+	SkipNotRunning       = "not_running"
+	SkipEmptyName        = "empty_name"
+	SkipNameTooLong      = "name_too_long"
+	SkipPaused           = "paused"
+	SkipVetoed           = "vetoed"
+	SkipNamingFailed     = "naming_failed"
+)
+
+// Counters labeled with a single label value.
+type CounterVec struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func newCounterVec() *CounterVec {
+	return &CounterVec{counts: make(map[string]uint64)}
+}
+
+// Increments the counter of the label value.
+func (c *CounterVec) Inc(value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[value]++
+}
+
+// Returns the counter of the label value.
+func (c *CounterVec) Get(value string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[value]
+}
+
+// Returns a copy of the counters.
+func (c *CounterVec) snapshot() map[string]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return maps.Clone(c.counts)
+}
+
+// Writes the counters in the text exposition format, sorted by the label value.
+func (c *CounterVec) write(w io.Writer, name string, label string, help string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, value := range slices.Sorted(maps.Keys(c.counts)) {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, metricLabelReplacer.Replace(value), c.counts[value])
+	}
+}
+
+// Numbers of the skipped containers and links, by reason.
+var skipCounter = newCounterVec()
+
+// Numbers of the cache lookups, by cache.
+var (
+	cacheHits   = newCounterVec()
+	cacheMisses = newCounterVec()
+)
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	mappings := []ContainerMapping{
+		{ID: "4567", Name: "/db", Links: []LinkState{
+			{Index: 12, ContainerLink: "eth0", OriginalName: "veth2", Name: "vdb0", LinkLabels: LinkLabels{Image: "postgres:16", Network: "backend"}},
+			{Index: 14, ContainerLink: "eth1", OriginalName: "veth3", Name: "vdb1", LinkLabels: LinkLabels{Image: `odd"image\`}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeMetrics(&buf, mappings, nil))
+	assert.Contains(t, buf.String(),
+		`dvn_interface_info{device="vdb0",original_device="veth2",ifindex="12",container="db",container_id="4567",container_link="eth0",image="postgres:16",network="backend"} 1`)
+	assert.Contains(t, buf.String(), `image="odd\"image\\",network=""} 1`)
+	assert.Contains(t, buf.String(), "dvn_tracked_links 2\n")
+}
+
+func TestDaemonMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeMetrics(&buf, nil, &DaemonMetrics{
+		EventLoopAlive:      true,
+		QueuedContainers:    3,
+		EventsDropped:       2,
+		LastEventAgeSeconds: -1,
+		Goroutines:          10,
+	}))
+	assert.Contains(t, buf.String(), "dvn_event_loop_up 1\n")
+	assert.Contains(t, buf.String(), "dvn_queued_containers 3\n")
+	assert.Contains(t, buf.String(), "# TYPE dvn_events_dropped_total counter\ndvn_events_dropped_total 2\n")
+	assert.Contains(t, buf.String(), "dvn_goroutines 10\n")
+	assert.NotContains(t, buf.String(), "dvn_last_event_age_seconds")
+
+	buf.Reset()
+	(DaemonMetrics{LastEventAgeSeconds: 1.5}).write(&buf)
+	assert.Contains(t, buf.String(), "dvn_event_loop_up 0\n")
+	assert.Contains(t, buf.String(), "dvn_last_event_age_seconds 1.500\n")
+}
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram([]float64{0.01, 0.1, 1})
+	h.Observe(5 * time.Millisecond)
+	h.Observe(50 * time.Millisecond)
+	h.Observe(2 * time.Second)
+
+	var buf bytes.Buffer
+	h.write(&buf, "dvn_test_seconds", "Test.")
+	assert.Equal(t, `# HELP dvn_test_seconds Test.
+# TYPE dvn_test_seconds histogram
+dvn_test_seconds_bucket{le="0.01"} 1
+dvn_test_seconds_bucket{le="0.1"} 2
+dvn_test_seconds_bucket{le="1"} 2
+dvn_test_seconds_bucket{le="+Inf"} 3
+dvn_test_seconds_sum 2.055
+dvn_test_seconds_count 3
+`, buf.String())
+	assert.Equal(t, HistogramSnapshot{Count: 3, SumSeconds: 2.055}, h.snapshot())
+}
+
+func TestCounterVec(t *testing.T) {
+	c := newCounterVec()
+	c.Inc(SkipPaused)
+	c.Inc(SkipHostNetwork)
+	c.Inc(SkipPaused)
+	assert.EqualValues(t, 2, c.Get(SkipPaused))
+
+	var buf bytes.Buffer
+	c.write(&buf, "dvn_skipped_total", "reason", "Test.")
+	assert.Equal(t, `# HELP dvn_skipped_total Test.
+# TYPE dvn_skipped_total counter
+dvn_skipped_total{reason="host_network"} 1
+dvn_skipped_total{reason="paused"} 2
+`, buf.String())
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Default port of the MQTT broker.
+	mqttDefaultPort = 1883
+	// Keep alive interval announced to the broker.
+	mqttKeepAlive = 60 * time.Second
+	// Time allowed to complete the work in progress on disconnecting, in milliseconds.
+	mqttDisconnectQuiesce = 250
+)
+
+// Timeout of connecting to the MQTT broker, and of publishing a message.
+var mqttTimeout = 5 * time.Second
+
+// Publishes notifications to the MQTT broker. The current mapping of each container link is published
+// as a retained message to the topic "<prefix>/<container name>/<container link>", and cleared when the mapping is removed.
+// All notifications are also published to the topic "<prefix>/events" without retaining.
+type MQTTSink struct {
+	broker  *url.URL
+	prefix  string
+	client  mqtt.Client
+	timeout time.Duration
+	queue   chan Notification
+}
+
+func newMQTTSink(broker string, prefix string) (*MQTTSink, error) {
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if len(u.Port()) == 0 {
+		host = net.JoinHostPort(u.Hostname(), strconv.Itoa(mqttDefaultPort))
+	}
+
+	hostname, _ := os.Hostname()
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://" + host).
+		SetClientID("docker-veth-namer-" + hostname).
+		SetCleanSession(true).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(mqttTimeout).
+		SetWriteTimeout(mqttTimeout).
+		// Connected on demand by the sink, the notifications being dropped while the broker is not reachable.
+		SetAutoReconnect(false).
+		SetOnConnectHandler(func(mqtt.Client) {
+			log.Infof("Connected to MQTT broker: %s", u.Redacted())
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Debugf("MQTT connection lost: %s: %s", u.Redacted(), err)
+		})
+	if user := u.User; user != nil {
+		opts.SetUsername(user.Username())
+		if password, ok := user.Password(); ok {
+			opts.SetPassword(password)
+		}
+	}
+
+	s := &MQTTSink{
+		broker:  u,
+		prefix:  strings.TrimSuffix(prefix, "/"),
+		client:  mqtt.NewClient(opts),
+		timeout: mqttTimeout,
+		queue:   make(chan Notification, notificationQueueSize),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *MQTTSink) Notify(n Notification) {
+	select {
+	case s.queue <- n:
+	default:
+		log.Errorf("MQTT queue is full, notification dropped: %s %s", s.broker.Redacted(), n.Type)
+	}
+}
+
+func (s *MQTTSink) Close() {
+	close(s.queue)
+}
+
+// Delivers queued notifications in order of appearance, connecting to the broker on demand.
+func (s *MQTTSink) run() {
+	defer func() {
+		if s.client.IsConnected() {
+			s.client.Disconnect(mqttDisconnectQuiesce)
+		}
+	}()
+
+	for n := range s.queue {
+		for _, message := range s.messages(n) {
+			if err := s.publish(message); err != nil {
+				log.Errorf("MQTT publish failed: %s: %s", s.broker.Redacted(), err)
+				break
+			}
+		}
+	}
+}
+
+// Message published to the MQTT broker.
+type MQTTMessage struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Returns the messages to be published for the notification.
+func (s *MQTTSink) messages(n Notification) []MQTTMessage {
+	body, err := json.Marshal(n)
+	if err != nil {
+		log.Errorf("json.Marshal to bytes failed: %s", err)
+		return nil
+	}
+
+	messages := []MQTTMessage{{Topic: s.prefix + "/events", Payload: body}}
+
+	link := n.ContainerLink
+	if len(link) == 0 {
+		link = strconv.Itoa(n.Index)
+	}
+	topic := s.prefix + "/" + mqttTopicLevel(strings.TrimPrefix(n.ContainerName, "/")) + "/" + mqttTopicLevel(link)
+
+	switch n.Type {
+	case NotificationMappingAdded:
+		messages = append(messages, MQTTMessage{Topic: topic, Payload: body, Retain: true})
+	case NotificationMappingRemoved:
+		// Empty retained message removes the retained one from the broker.
+		messages = append(messages, MQTTMessage{Topic: topic, Payload: []byte{}, Retain: true})
+	}
+	return messages
+}
+
+// Replaces the symbols not allowed in the topic level: the level separator and the wildcards.
+func mqttTopicLevel(s string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(s)
+}
+
+// Publishes the message with QoS 0, connecting to the broker if not connected.
+func (s *MQTTSink) publish(message MQTTMessage) error {
+	if !s.client.IsConnectionOpen() {
+		if err := s.wait(s.client.Connect()); err != nil {
+			return err
+		}
+	}
+
+	return s.wait(s.client.Publish(message.Topic, 0, message.Retain, message.Payload))
+}
+
+// Waits for the operation to complete, and returns its error.
+func (s *MQTTSink) wait(token mqtt.Token) error {
+	if !token.WaitTimeout(s.timeout) {
+		return errors.New("timed out")
+	}
+	return token.Error()
+}
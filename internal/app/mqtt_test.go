@@ -0,0 +1,137 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Accepts the MQTT connection, and answers the CONNECT packet with the return code. Returns the connection and the packet.
+func acceptMQTT(t *testing.T, listener net.Listener, returnCode byte) (net.Conn, *packets.ConnectPacket) {
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	packet, err := packets.ReadPacket(conn)
+	require.NoError(t, err)
+	connect, ok := packet.(*packets.ConnectPacket)
+	require.True(t, ok, "unexpected packet: %s", packet)
+
+	connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	connack.ReturnCode = returnCode
+	require.NoError(t, connack.Write(conn))
+	return conn, connect
+}
+
+func TestMQTTSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := newMQTTSink("mqtt://user:secret@"+listener.Addr().String(), "dvn/")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	sink.Notify(Notification{Type: NotificationMappingAdded, ContainerName: "/web", ContainerLink: "eth0", Index: 42, Name: "vweb0"})
+	sink.Notify(Notification{Type: NotificationMappingRemoved, ContainerName: "/web", ContainerLink: "eth0", Index: 42, Name: "vweb0"})
+
+	conn, connect := acceptMQTT(t, listener, packets.Accepted)
+	assert.Equal(t, byte(4), connect.ProtocolVersion)
+	assert.True(t, connect.CleanSession)
+	assert.Equal(t, "user", connect.Username)
+	assert.Equal(t, []byte("secret"), connect.Password)
+
+	var messages []*packets.PublishPacket
+	for len(messages) < 4 {
+		packet, err := packets.ReadPacket(conn)
+		require.NoError(t, err)
+		if publish, ok := packet.(*packets.PublishPacket); ok {
+			messages = append(messages, publish)
+		}
+	}
+
+	assert.Equal(t, "dvn/events", messages[0].TopicName)
+	assert.False(t, messages[0].Retain)
+	assert.Equal(t, byte(0), messages[0].Qos)
+
+	assert.Equal(t, "dvn/web/eth0", messages[1].TopicName)
+	assert.True(t, messages[1].Retain)
+	var n Notification
+	require.NoError(t, json.Unmarshal(messages[1].Payload, &n))
+	assert.Equal(t, "vweb0", n.Name)
+
+	assert.Equal(t, "dvn/events", messages[2].TopicName)
+
+	assert.Equal(t, "dvn/web/eth0", messages[3].TopicName)
+	assert.True(t, messages[3].Retain)
+	assert.Empty(t, messages[3].Payload)
+}
+
+func TestMQTTSinkErrors(t *testing.T) {
+	defer func(timeout time.Duration) { mqttTimeout = timeout }(mqttTimeout)
+	mqttTimeout = 200 * time.Millisecond
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := newMQTTSink("mqtt://"+listener.Addr().String(), "dvn")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// The refused connection fails the publishing.
+	done := make(chan error, 1)
+	go func() { done <- sink.publish(MQTTMessage{Topic: "dvn/events"}) }()
+	acceptMQTT(t, listener, packets.ErrRefusedNotAuthorised)
+	assert.Error(t, <-done)
+
+	// So does the broker not answering the connection.
+	go func() { done <- sink.publish(MQTTMessage{Topic: "dvn/events"}) }()
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(2 * mqttTimeout):
+		t.Fatal("publishing is not timed out")
+	}
+}
+
+func TestMQTTConfigValidation(t *testing.T) {
+	c := defaultConfig()
+	c.MQTTBroker = "mqtt://broker.lan"
+	assert.NoError(t, validateConfig(c))
+
+	c.MQTTBroker = "broker.lan:1883"
+	assert.ErrorContains(t, validateConfig(c), "mqtt_broker")
+
+	c.MQTTBroker = ""
+	c.MQTTTopicPrefix = "dvn/#"
+	assert.ErrorContains(t, validateConfig(c), "mqtt_topic_prefix")
+}
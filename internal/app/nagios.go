@@ -0,0 +1,263 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// Exit statuses of monitoring plugins, as defined by the Nagios plugin API.
+const (
+	NagiosOK       = 0
+	NagiosWarning  = 1
+	NagiosCritical = 2
+	NagiosUnknown  = 3
+)
+
+var nagiosStatusNames = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// Thresholds of the nagios command. A threshold is exceeded when the value is greater than it;
+// a negative threshold is disabled.
+type NagiosThresholds struct {
+	WarningUnnamed  int
+	CriticalUnnamed int
+	WarningFailed   int
+	CriticalFailed  int
+	// Zero disables the staleness checks.
+	WarningStale  time.Duration
+	CriticalStale time.Duration
+}
+
+// Observations collected for the nagios command.
+type NagiosState struct {
+	// Nil if the daemon health was queried successfully.
+	HealthErr error
+	Health    Health
+	// Nil if the links were enumerated successfully.
+	LinksErr error
+	Links    int
+	Unnamed  int
+	// Nil if the rename history was read successfully.
+	FailedErr error
+	Failed    int
+	Now       time.Time
+}
+
+// Result of the nagios command: the exit status and the single line of plugin output.
+type NagiosResult struct {
+	Status int
+	Output string
+}
+
+// Returns the status of a value against the warning and critical thresholds.
+func nagiosThreshold(value, warning, critical int) int {
+	if critical >= 0 && value > critical {
+		return NagiosCritical
+	}
+	if warning >= 0 && value > warning {
+		return NagiosWarning
+	}
+	return NagiosOK
+}
+
+// Formats a threshold for the perfdata, empty if it is disabled.
+func nagiosPerfThreshold(threshold int) string {
+	if threshold < 0 {
+		return ""
+	}
+	return fmt.Sprint(threshold)
+}
+
+// Evaluates the state against the thresholds.
+// The worst status of all checks wins, with UNKNOWN ranking below CRITICAL.
+func evaluateNagios(state NagiosState, t NagiosThresholds) NagiosResult {
+	severity := map[int]int{NagiosOK: 0, NagiosWarning: 1, NagiosUnknown: 2, NagiosCritical: 3}
+	status := NagiosOK
+	raise := func(s int) {
+		if severity[s] > severity[status] {
+			status = s
+		}
+	}
+
+	var messages, perfdata []string
+
+	if errors.Is(state.HealthErr, errDaemonNotRunning) {
+		raise(NagiosCritical)
+		messages = append(messages, state.HealthErr.Error())
+	} else if state.HealthErr != nil {
+		raise(NagiosCritical)
+		messages = append(messages, fmt.Sprintf("daemon is not reachable: %s", state.HealthErr))
+	} else {
+		if !state.Health.Healthy {
+			raise(NagiosCritical)
+			problems := "daemon is not healthy"
+			if len(state.Health.Problems) > 0 {
+				problems += ": " + strings.Join(state.Health.Problems, ", ")
+			}
+			messages = append(messages, problems)
+		}
+
+		if !state.Health.LastEventTime.IsZero() {
+			age := state.Now.Sub(state.Health.LastEventTime)
+			if age < 0 {
+				age = 0
+			}
+			switch {
+			case t.CriticalStale > 0 && age > t.CriticalStale:
+				raise(NagiosCritical)
+				messages = append(messages, fmt.Sprintf("last event %s ago", age.Round(time.Second)))
+			case t.WarningStale > 0 && age > t.WarningStale:
+				raise(NagiosWarning)
+				messages = append(messages, fmt.Sprintf("last event %s ago", age.Round(time.Second)))
+			}
+
+			staleThreshold := func(d time.Duration) string {
+				if d <= 0 {
+					return ""
+				}
+				return fmt.Sprint(int64(d.Seconds()))
+			}
+			perfdata = append(perfdata, fmt.Sprintf("last_event_age=%ds;%s;%s;0", int64(age.Seconds()),
+				staleThreshold(t.WarningStale), staleThreshold(t.CriticalStale)))
+		}
+	}
+
+	if state.LinksErr != nil {
+		raise(NagiosUnknown)
+		messages = append(messages, fmt.Sprintf("cannot enumerate links: %s", state.LinksErr))
+	} else {
+		if s := nagiosThreshold(state.Unnamed, t.WarningUnnamed, t.CriticalUnnamed); s != NagiosOK {
+			raise(s)
+			messages = append(messages, fmt.Sprintf("%d of %d host links are not named as expected", state.Unnamed, state.Links))
+		}
+		perfdata = append(perfdata,
+			fmt.Sprintf("unnamed_links=%d;%s;%s;0", state.Unnamed, nagiosPerfThreshold(t.WarningUnnamed), nagiosPerfThreshold(t.CriticalUnnamed)),
+			fmt.Sprintf("links=%d;;;0", state.Links))
+	}
+
+	if state.FailedErr != nil {
+		raise(NagiosUnknown)
+		messages = append(messages, fmt.Sprintf("cannot read rename history: %s", state.FailedErr))
+	} else {
+		if s := nagiosThreshold(state.Failed, t.WarningFailed, t.CriticalFailed); s != NagiosOK {
+			raise(s)
+			messages = append(messages, fmt.Sprintf("%d failed renames", state.Failed))
+		}
+		perfdata = append(perfdata,
+			fmt.Sprintf("failed_renames=%d;%s;%s;0", state.Failed, nagiosPerfThreshold(t.WarningFailed), nagiosPerfThreshold(t.CriticalFailed)))
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, fmt.Sprintf("%d host links are named as expected", state.Links))
+	}
+
+	output := fmt.Sprintf("DOCKER-VETH-NAMER %s - %s", nagiosStatusNames[status], strings.Join(messages, "; "))
+	if len(perfdata) > 0 {
+		output += " | " + strings.Join(perfdata, " ")
+	}
+	return NagiosResult{Status: status, Output: output}
+}
+
+// Returns the number of failed rename operations since the time: from the running daemon,
+// or from the audit log when the daemon is not reachable.
+func countFailedRenames(since time.Time) (int, error) {
+	var records []RenameRecord
+
+	var err error
+	if len(config.ControlSocket) > 0 {
+		err = controlRequest(config.ControlSocket, "GET", "/history", &records)
+	} else {
+		err = errors.New("control socket is disabled in the configuration")
+	}
+
+	if err != nil {
+		if len(config.AuditLogFile) == 0 {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+				err = errDaemonNotRunning
+			}
+			return 0, fmt.Errorf("audit log is disabled: %w", err)
+		}
+
+		log.Debugf("Daemon is not reachable, reading audit log: %s", err)
+		records, err = readAuditLog(config.AuditLogFile)
+		if err != nil {
+			return 0, fmt.Errorf("cannot read audit log: %w", err)
+		}
+	}
+
+	failed := 0
+	for _, r := range records {
+		if len(r.Error) > 0 && !r.Time.Before(since) {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// Counts the host links of running containers, and those not named as expected.
+func countUnnamedLinks(ctx context.Context) (links int, unnamed int, err error) {
+	rt, err := newContainerRuntime()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rt.Close()
+
+	pingCtx, cancel := withTimeout(ctx, config.DockerListTimeout)
+	err = rt.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	mappings := runningLinkMappings(ctx, rt)
+	return len(mappings), len(mismatchedLinkMappings(mappings)), nil
+}
+
+// Collects the state of the daemon and the host links for the nagios command.
+func collectNagiosState(ctx context.Context, window time.Duration) NagiosState {
+	state := NagiosState{Now: time.Now()}
+	state.Health, state.HealthErr = queryHealth(config.ControlSocket)
+	if errors.Is(state.HealthErr, fs.ErrNotExist) || errors.Is(state.HealthErr, syscall.ECONNREFUSED) {
+		state.HealthErr = errDaemonNotRunning
+	}
+	state.Links, state.Unnamed, state.LinksErr = countUnnamedLinks(ctx)
+	state.Failed, state.FailedErr = countFailedRenames(state.Now.Add(-window))
+	return state
+}
+
+// Prints the plugin output, and exits with the plugin status.
+func runNagios(w io.Writer, window time.Duration, t NagiosThresholds) error {
+	result := evaluateNagios(collectNagiosState(context.Background(), window), t)
+	fmt.Fprintln(w, result.Output)
+	if result.Status != NagiosOK {
+		return cli.Exit("", result.Status)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateNagios(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+	thresholds := NagiosThresholds{
+		WarningUnnamed: 0, CriticalUnnamed: 2,
+		WarningFailed: 0, CriticalFailed: 5,
+		WarningStale: time.Hour, CriticalStale: 0,
+	}
+	healthy := Health{Healthy: true, EventLoopAlive: true, DockerConnected: true, LastEventTime: now.Add(-42 * time.Second)}
+
+	result := evaluateNagios(NagiosState{Health: healthy, Links: 12, Now: now}, thresholds)
+	assert.Equal(t, NagiosOK, result.Status)
+	assert.Equal(t, "DOCKER-VETH-NAMER OK - 12 host links are named as expected | "+
+		"last_event_age=42s;3600;;0 unnamed_links=0;0;2;0 links=12;;;0 failed_renames=0;0;5;0", result.Output)
+
+	result = evaluateNagios(NagiosState{Health: healthy, Links: 12, Unnamed: 1, Failed: 6, Now: now}, thresholds)
+	assert.Equal(t, NagiosCritical, result.Status)
+	assert.Equal(t, "DOCKER-VETH-NAMER CRITICAL - 1 of 12 host links are not named as expected; 6 failed renames | "+
+		"last_event_age=42s;3600;;0 unnamed_links=1;0;2;0 links=12;;;0 failed_renames=6;0;5;0", result.Output)
+
+	stale := healthy
+	stale.LastEventTime = now.Add(-2 * time.Hour)
+	result = evaluateNagios(NagiosState{Health: stale, Links: 12, Now: now}, thresholds)
+	assert.Equal(t, NagiosWarning, result.Status)
+	assert.Contains(t, result.Output, "WARNING - last event 2h0m0s ago |")
+
+	result = evaluateNagios(NagiosState{Health: healthy, LinksErr: errors.New("no docker"), Now: now}, thresholds)
+	assert.Equal(t, NagiosUnknown, result.Status)
+	assert.Equal(t, "DOCKER-VETH-NAMER UNKNOWN - cannot enumerate links: no docker | "+
+		"last_event_age=42s;3600;;0 failed_renames=0;0;5;0", result.Output)
+
+	result = evaluateNagios(NagiosState{HealthErr: errors.New("connection refused"), LinksErr: errors.New("no docker"), Now: now}, thresholds)
+	assert.Equal(t, NagiosCritical, result.Status)
+	assert.Equal(t, "DOCKER-VETH-NAMER CRITICAL - daemon is not reachable: connection refused; cannot enumerate links: no docker | "+
+		"failed_renames=0;0;5;0", result.Output)
+
+	thresholds.CriticalUnnamed = -1
+	result = evaluateNagios(NagiosState{Health: healthy, Links: 12, Unnamed: 5, Now: now}, thresholds)
+	assert.Equal(t, NagiosWarning, result.Status)
+	assert.Contains(t, result.Output, "unnamed_links=5;0;;0")
+}
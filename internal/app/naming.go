@@ -0,0 +1,157 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	NamingStrategyMorph = "morph"
+	NamingStrategyExec  = "exec"
+	NamingStrategyWasm  = "wasm"
+)
+
+// Container link to make the host link name for. Passed to the naming command as JSON.
+type NameRequest struct {
+	ContainerID   string `json:"container_id,omitempty"`
+	ContainerName string `json:"container_name"`
+	ContainerLink string `json:"container_link"`
+	LinkLabels
+	// Maximum length of the host link name in bytes.
+	MaxLength int `json:"max_length"`
+}
+
+// Makes the host link names for the container links.
+// Implementations are called concurrently, and must read the configuration on each call to follow its reloads.
+type NamingStrategy interface {
+	// Returns the host link name, recording the steps into the trace (which can be nil).
+	LinkName(req NameRequest, trace *naming.Trace) (string, error)
+}
+
+// Implemented by the naming strategies making the host link name of the container name and link only.
+// Network connect events of the known containers are processed without inspecting them for such strategies.
+type nameOnlyStrategy interface {
+	nameOnly()
+}
+
+// Naming strategies by name, selected by the configuration.
+var namingStrategies = make(map[string]NamingStrategy)
+
+// Registers the compiled-in naming strategy. Must be called on initialization.
+func registerNamingStrategy(name string, strategy NamingStrategy) {
+	if _, ok := namingStrategies[name]; ok {
+		panic("naming strategy is registered already: " + name)
+	}
+	namingStrategies[name] = strategy
+}
+
+func init() {
+	registerNamingStrategy(NamingStrategyMorph, MorphStrategy{})
+	registerNamingStrategy(NamingStrategyExec, ExecStrategy{})
+	registerNamingStrategy(NamingStrategyWasm, &WasmStrategy{})
+}
+
+// Returns the sorted names of the registered naming strategies.
+func namingStrategyNames() []string {
+	var names []string
+	for name := range namingStrategies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Returns the configured naming strategy, or the morph strategy when none is configured, e.g. in tests.
+func namingStrategy() NamingStrategy {
+	if strategy, ok := namingStrategies[config.NamingStrategy]; ok {
+		return strategy
+	}
+	return MorphStrategy{}
+}
+
+// Makes the host link name by the configured naming strategy. The failure is logged.
+func makeLinkNameFor(req NameRequest) (string, error) {
+	req.MaxLength = naming.MaxLength
+	linkName, err := namingStrategy().LinkName(req, nil)
+	if err != nil {
+		errorfLimited(log.WithFields(log.Fields{logFieldContainerName: req.ContainerName, logFieldContainerLink: req.ContainerLink}),
+			"Cannot make host link name: %s %s: %s", req.ContainerName, req.ContainerLink, err)
+		return "", err
+	}
+	return linkName, nil
+}
+
+// Morphs the container name by the replacement rules, and appends the container link suffix, see traceLinkName.
+type MorphStrategy struct{}
+
+func (MorphStrategy) LinkName(req NameRequest, trace *naming.Trace) (string, error) {
+	return traceLinkName(req.ContainerName, req.ContainerLink, trace)
+}
+
+func (MorphStrategy) nameOnly() {}
+
+// Runs the naming command with the container link as JSON on stdin, and takes the host link name from its stdout.
+type ExecStrategy struct{}
+
+func (ExecStrategy) LinkName(req NameRequest, trace *naming.Trace) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.HookTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := shellCommand(ctx, config.NamingCommand)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		trace.Step(fmt.Sprintf("Naming command %q failed: %s", config.NamingCommand, err), "")
+		if message := bytes.TrimSpace(stderr.Bytes()); len(message) > 0 {
+			return "", fmt.Errorf("naming command failed: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("naming command failed: %w", err)
+	}
+
+	linkName := strings.TrimSpace(string(output))
+	trace.Step(fmt.Sprintf("Run naming command %q with the container link as JSON on stdin", config.NamingCommand), linkName)
+
+	if !isValidMadeName(linkName, req.MaxLength) {
+		return "", fmt.Errorf("naming command returned invalid host link name: %q", linkName)
+	}
+	return linkName, nil
+}
+
+// Returns whether the host link name made by the external naming logic is valid.
+func isValidMadeName(linkName string, maxLength int) bool {
+	return len(linkName) > 0 && len(linkName) <= maxLength && linkName != "." && linkName != ".." && naming.IsValidText(linkName)
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecNamingStrategy(t *testing.T) {
+	prevConfig := config
+	defer func() { config = prevConfig }()
+	config = defaultConfig()
+	config.NamingStrategy = NamingStrategyExec
+
+	config.NamingCommand = `grep -q '"container_name":"/web","container_link":"eth1","image":"nginx","max_length":15' && echo " web-nginx-1 "`
+	linkName, err := makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth1", LinkLabels: LinkLabels{Image: "nginx"}})
+	require.NoError(t, err)
+	assert.Equal(t, "web-nginx-1", linkName)
+
+	trace := explainLinkName("/web", "eth1")
+	assert.Empty(t, trace.LinkName)
+	assert.Len(t, trace.Steps, 1)
+
+	config.NamingCommand = "echo 'no such container' >&2; exit 3"
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	assert.ErrorContains(t, err, "no such container")
+
+	for _, output := range []string{"", "a-very-long-link-name", "web/0", "web 0", ".."} {
+		config.NamingCommand = "echo '" + output + "'"
+		_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+		assert.ErrorContains(t, err, "invalid host link name", output)
+	}
+}
+
+func TestMorphNamingStrategy(t *testing.T) {
+	prevConfig := config
+	defer func() { config = prevConfig }()
+	config = defaultConfig()
+
+	linkName, err := makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	require.NoError(t, err)
+	assert.Equal(t, "vwebeth0", linkName)
+
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "a-very-long-link-name"})
+	assert.ErrorIs(t, err, naming.ErrLinkSuffixTooLong)
+}
+
+func TestNamingStrategyValidation(t *testing.T) {
+	c := defaultConfig()
+	c.NamingStrategy = "random"
+	assert.ErrorContains(t, validateConfig(c), "naming_strategy must be one of exec, morph, wasm")
+
+	c.NamingStrategy = NamingStrategyExec
+	assert.ErrorContains(t, validateConfig(c), "naming_command")
+
+	c.NamingCommand = "/usr/local/bin/name-link"
+	assert.NoError(t, validateConfig(c))
+
+	c.NamingStrategy = NamingStrategyWasm
+	assert.ErrorContains(t, validateConfig(c), "naming_module must be set")
+
+	c.NamingModule = "/usr/local/lib/name-link.wasm"
+	assert.NoError(t, validateConfig(c))
+
+	c.NamingModuleMemoryLimit = 0
+	c.NamingModuleTimeout = 0
+	assert.ErrorContains(t, validateConfig(c), "naming_module_memory_limit must be between 1 and 4096")
+	assert.ErrorContains(t, validateConfig(c), "naming_module_timeout must be positive")
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Timeout of connecting to the NATS server, and of flushing a message.
+var natsTimeout = 5 * time.Second
+
+// Publishes notifications to the NATS server, to the subject "<prefix>.<host name>.<notification type>".
+// The message is the notification as a JSON document, with the host name added.
+// Subscribers may receive the notifications of all hosts by a wildcard, e.g. "<prefix>.*.mapping_added".
+type NATSSink struct {
+	server  *url.URL
+	prefix  string
+	host    string
+	timeout time.Duration
+	queue   chan Notification
+	// Connected on demand. Owned by the delivering goroutine.
+	conn *nats.Conn
+}
+
+// Notification published to the NATS server.
+type NATSNotification struct {
+	Host string `json:"host"`
+	Notification
+}
+
+func newNATSSink(server string, prefix string) (*NATSSink, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	s := &NATSSink{
+		server:  u,
+		prefix:  strings.TrimSuffix(prefix, "."),
+		host:    host,
+		timeout: natsTimeout,
+		queue:   make(chan Notification, notificationQueueSize),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *NATSSink) Notify(n Notification) {
+	select {
+	case s.queue <- n:
+	default:
+		log.Errorf("NATS queue is full, notification dropped: %s %s", s.server.Redacted(), n.Type)
+	}
+}
+
+func (s *NATSSink) Close() {
+	close(s.queue)
+}
+
+// Delivers queued notifications in order of appearance, connecting to the server on demand.
+func (s *NATSSink) run() {
+	defer s.disconnect()
+
+	for n := range s.queue {
+		body, err := json.Marshal(NATSNotification{Host: s.host, Notification: n})
+		if err != nil {
+			log.Errorf("json.Marshal to bytes failed: %s", err)
+			continue
+		}
+
+		subject := s.prefix + "." + natsToken(s.host) + "." + n.Type
+		if err := s.publish(subject, body); err != nil {
+			log.Errorf("NATS publish failed: %s: %s", s.server.Redacted(), err)
+		}
+	}
+}
+
+// Replaces the symbols not allowed in the subject token: the token separator, the wildcards, and whitespace.
+func natsToken(s string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_", "\t", "_").Replace(s)
+}
+
+// Publishes the message, connecting to the server if not connected, and waits for the server to receive it.
+func (s *NATSSink) publish(subject string, payload []byte) error {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.conn.Publish(subject, payload); err != nil {
+		return err
+	}
+	return s.conn.FlushTimeout(s.timeout)
+}
+
+// Connects to the server. The connection is reestablished by the client when lost,
+// the messages published meanwhile failing instead of being buffered.
+func (s *NATSSink) connect() error {
+	server := s.server.Redacted()
+	conn, err := nats.Connect(s.server.String(),
+		nats.Name("docker-veth-namer"),
+		nats.Timeout(s.timeout),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Debugf("NATS connection lost: %s: %s", server, err)
+			}
+		}),
+		nats.ReconnectHandler(func(*nats.Conn) {
+			log.Infof("Reconnected to NATS server: %s", server)
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			log.Errorf("NATS server reported error: %s: %s", server, err)
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.conn = conn
+	log.Infof("Connected to NATS server: %s", server)
+	return nil
+}
+
+// Closes the connection to the server, if connected.
+func (s *NATSSink) disconnect() {
+	if s.conn == nil {
+		return
+	}
+
+	s.conn.Close()
+	s.conn = nil
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Accepts the NATS connection, and answers the CONNECT command by the reply.
+// Returns the connection, its reader, and the connect options.
+func acceptNATS(t *testing.T, listener net.Listener, reply string) (net.Conn, *bufio.Reader, map[string]any) {
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	r := bufio.NewReader(conn)
+
+	_, err = io.WriteString(conn, "INFO {\"server_id\":\"test\",\"proto\":1,\"max_payload\":1048576}\r\n")
+	require.NoError(t, err)
+
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "CONNECT "))
+	var options map[string]any
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &options))
+
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "PING\r\n", line)
+	_, err = io.WriteString(conn, reply)
+	require.NoError(t, err)
+
+	return conn, r, options
+}
+
+func TestNATSSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := newNATSSink("nats://user:secret@"+listener.Addr().String(), "dvn.")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	sink.Notify(Notification{Type: NotificationMappingAdded, ContainerName: "/web", ContainerLink: "eth0", Index: 42, Name: "vweb0"})
+
+	conn, r, options := acceptNATS(t, listener, "PONG\r\n")
+	assert.Equal(t, "user", options["user"])
+	assert.Equal(t, "secret", options["pass"])
+	assert.Equal(t, "docker-veth-namer", options["name"])
+
+	// The message is flushed by the ping.
+	var published bool
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\r\n")
+		if line == "PING" {
+			_, err = io.WriteString(conn, "PONG\r\n")
+			require.NoError(t, err)
+			if published {
+				break
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		require.Len(t, fields, 3)
+		require.Equal(t, "PUB", fields[0])
+		host, _ := os.Hostname()
+		assert.Equal(t, "dvn."+natsToken(host)+".mapping_added", fields[1])
+
+		length, err := strconv.Atoi(fields[2])
+		require.NoError(t, err)
+		payload := make([]byte, length+2)
+		_, err = io.ReadFull(r, payload)
+		require.NoError(t, err)
+
+		var n NATSNotification
+		require.NoError(t, json.Unmarshal(payload[:length], &n))
+		assert.Equal(t, host, n.Host)
+		assert.Equal(t, "vweb0", n.Name)
+		assert.Equal(t, "/web", n.ContainerName)
+		published = true
+	}
+}
+
+func TestNATSSinkErrors(t *testing.T) {
+	defer func(timeout time.Duration) { natsTimeout = timeout }(natsTimeout)
+	natsTimeout = 200 * time.Millisecond
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := newNATSSink("nats://token@"+listener.Addr().String(), "dvn")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// The refused connection fails the publishing.
+	done := make(chan error, 1)
+	go func() { done <- sink.publish("dvn.test", []byte("{}")) }()
+	_, _, options := acceptNATS(t, listener, "-ERR 'Authorization Violation'\r\n")
+	assert.Equal(t, "token", options["auth_token"])
+	assert.ErrorContains(t, <-done, "Authorization Violation")
+
+	// So does the server not answering the connection.
+	go func() { done <- sink.publish("dvn.test", []byte("{}")) }()
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("publishing is not timed out")
+	}
+
+	// And the flush not answered by the server.
+	go func() { done <- sink.publish("dvn.test", []byte("{}")) }()
+	acceptNATS(t, listener, "PONG\r\n")
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "timeout")
+	case <-time.After(2 * time.Second):
+		t.Fatal("publishing is not timed out")
+	}
+}
+
+func TestNATSToken(t *testing.T) {
+	assert.Equal(t, "node1_example_com", natsToken("node1.example.com"))
+	assert.Equal(t, "a_b_c", natsToken("a*b>c"))
+}
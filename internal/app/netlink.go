@@ -0,0 +1,188 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	nlmsg "github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// Link requests to the kernel, made within the network namespace of the program unless stated otherwise.
+// The requests are traced by the nl* functions, which go through the current implementation.
+type Netlink interface {
+	LinkList() ([]netlink.Link, error)
+	LinkByIndex(index int) (netlink.Link, error)
+	LinkByName(name string) (netlink.Link, error)
+	LinkSetName(link netlink.Link, name string) error
+	LinkAddAltName(link netlink.Link, name string) error
+	LinkDelAltName(link netlink.Link, name string) error
+	LinkSetAlias(link netlink.Link, alias string) error
+	// Lists the veth links within the network namespace of the path.
+	NamespaceVEths(path string) ([]linkops.VEth, error)
+}
+
+// Timeout of the requests made through the shared netlink socket.
+const netlinkSocketTimeout = 10 * time.Second
+
+// Netlink of the running kernel. The requests within the network namespace of the program share a single socket,
+// opened on the first request, instead of opening a socket per request.
+type kernelNetlink struct {
+	once sync.Once
+	h    *netlink.Handle
+}
+
+// Current netlink implementation, replaced by the in-memory one in the tests.
+var nl Netlink = &kernelNetlink{}
+
+// Returns whether the link request failed as the link does not exist.
+func isLinkNotFound(err error) bool {
+	var notFound netlink.LinkNotFoundError
+	return errors.As(err, &notFound) || errors.Is(err, unix.ENODEV)
+}
+
+// Returns the handle of the shared socket. When the socket cannot be opened,
+// the handle opening a socket per request is returned.
+func (k *kernelNetlink) handle() *netlink.Handle {
+	k.once.Do(func() {
+		h, err := netlink.NewHandle(unix.NETLINK_ROUTE)
+		if err == nil {
+			err = h.SetSocketTimeout(netlinkSocketTimeout)
+		}
+		if err != nil {
+			log.Debugf("Cannot open shared netlink socket, opening a socket per request: %s", err)
+			if h != nil {
+				h.Close()
+			}
+			h = &netlink.Handle{}
+		}
+		k.h = h
+	})
+	return k.h
+}
+
+func (k *kernelNetlink) LinkList() ([]netlink.Link, error) {
+	return k.handle().LinkList()
+}
+
+func (k *kernelNetlink) LinkByIndex(index int) (netlink.Link, error) {
+	return k.handle().LinkByIndex(index)
+}
+
+func (k *kernelNetlink) LinkByName(name string) (netlink.Link, error) {
+	return k.handle().LinkByName(name)
+}
+
+func (k *kernelNetlink) LinkSetName(link netlink.Link, name string) error {
+	return k.handle().LinkSetName(link, name)
+}
+
+func (k *kernelNetlink) LinkAddAltName(link netlink.Link, name string) error {
+	return k.handle().LinkAddAltName(link, name)
+}
+
+func (k *kernelNetlink) LinkDelAltName(link netlink.Link, name string) error {
+	return k.handle().LinkDelAltName(link, name)
+}
+
+func (k *kernelNetlink) LinkSetAlias(link netlink.Link, alias string) error {
+	return k.handle().LinkSetAlias(link, alias)
+}
+
+// Lists the links through a netlink socket opened within the network namespace.
+// The socket is opened by a locked OS thread switched to the namespace with setns,
+// which is switched back right after, so no other goroutine runs within the namespace.
+func (*kernelNetlink) NamespaceVEths(path string) ([]linkops.VEth, error) {
+	ns, err := netns.GetFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("netns.GetFromPath failed: %w", err)
+	}
+	defer ns.Close()
+
+	s, err := nlmsg.GetNetlinkSocketAt(ns, netns.None(), unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("nlmsg.GetNetlinkSocketAt failed: %w", err)
+	}
+	defer s.Close()
+
+	links, err := listVEthLinks(s)
+	if err != nil {
+		return nil, fmt.Errorf("netlink.LinkList failed: %w", err)
+	}
+	return linkops.VEths(links), nil
+}
+
+// Lists the veth links through the socket, requesting the kernel to filter the links by kind.
+// Kernels lacking the dump filtering list all links, which are filtered by kind by the caller as well.
+func listVEthLinks(s *nlmsg.NetlinkSocket) ([]netlink.Link, error) {
+	timeout := unix.NsecToTimeval(netlinkSocketTimeout.Nanoseconds())
+	if err := s.SetSendTimeout(&timeout); err != nil {
+		return nil, err
+	}
+	if err := s.SetReceiveTimeout(&timeout); err != nil {
+		return nil, err
+	}
+
+	req := nlmsg.NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	req.Sockets = map[int]*nlmsg.SocketHandle{unix.NETLINK_ROUTE: {Socket: s}}
+	req.AddData(nlmsg.NewIfInfomsg(unix.AF_UNSPEC))
+	linkInfo := nlmsg.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	linkInfo.AddRtAttr(nlmsg.IFLA_INFO_KIND, nlmsg.NonZeroTerminated("veth"))
+	req.AddData(linkInfo)
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]netlink.Link, 0, len(msgs))
+	for _, msg := range msgs {
+		link, err := netlink.LinkDeserialize(nil, msg)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// Lists veth links within the network namespace of the container sandbox.
+func listContainerLinks(sandboxKey string) ([]linkops.VEth, error) {
+	if links, ok := inspectCache.Links(sandboxKey, time.Now(), config.InspectCacheTTL); ok {
+		return links, nil
+	}
+
+	epoch := inspectCache.Epoch()
+	start := time.Now()
+	links, err := nlNamespaceVEths(sandboxKey)
+	enumerationDuration.ObserveSince(start)
+	if err == nil {
+		inspectCache.StoreLinks(sandboxKey, links, epoch, time.Now())
+	}
+	return links, err
+}
@@ -0,0 +1,278 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Netlink keeping the host links and the container network namespaces in memory.
+// The links returned are copies, so they keep the names they had when requested, as with the kernel.
+type fakeNetlink struct {
+	links      map[int]netlink.LinkAttrs
+	namespaces map[string][]linkops.VEth
+}
+
+func newFakeNetlink() *fakeNetlink {
+	return &fakeNetlink{links: map[int]netlink.LinkAttrs{}, namespaces: map[string][]linkops.VEth{}}
+}
+
+// Replaces the netlink implementation until the end of the test.
+func useFakeNetlink(t *testing.T) *fakeNetlink {
+	f := newFakeNetlink()
+	prev := nl
+	nl = f
+	// The links of the namespaces cached by other tests are stale.
+	inspectCache.Clear()
+	t.Cleanup(func() {
+		nl = prev
+		inspectCache.Clear()
+	})
+	return f
+}
+
+// Adds the veth link at the host, and its peer in the container network namespace.
+func (f *fakeNetlink) addVeth(index int, name string, namespace string, containerLink string, hardwareAddr string) {
+	f.links[index] = netlink.LinkAttrs{Index: index, Name: name}
+	f.namespaces[namespace] = append(f.namespaces[namespace], linkops.VEth{Name: containerLink, ParentIndex: index, HardwareAddr: hardwareAddr})
+}
+
+func (f *fakeNetlink) link(attrs netlink.LinkAttrs) netlink.Link {
+	attrs.AltNames = slices.Clone(attrs.AltNames)
+	return &netlink.Veth{LinkAttrs: attrs}
+}
+
+// Returns whether the name or the alternative name is used by any link.
+func (f *fakeNetlink) nameUsed(name string) bool {
+	for _, attrs := range f.links {
+		if attrs.Name == name || slices.Contains(attrs.AltNames, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeNetlink) LinkList() ([]netlink.Link, error) {
+	var links []netlink.Link
+	for _, attrs := range f.links {
+		links = append(links, f.link(attrs))
+	}
+	return links, nil
+}
+
+func (f *fakeNetlink) LinkByIndex(index int) (netlink.Link, error) {
+	attrs, ok := f.links[index]
+	if !ok {
+		return nil, unix.ENODEV
+	}
+	return f.link(attrs), nil
+}
+
+func (f *fakeNetlink) LinkByName(name string) (netlink.Link, error) {
+	for _, attrs := range f.links {
+		if attrs.Name == name || slices.Contains(attrs.AltNames, name) {
+			return f.link(attrs), nil
+		}
+	}
+	return nil, unix.ENODEV
+}
+
+func (f *fakeNetlink) LinkSetName(link netlink.Link, name string) error {
+	attrs, ok := f.links[link.Attrs().Index]
+	switch {
+	case !ok:
+		return unix.ENODEV
+	case len(name) >= unix.IFNAMSIZ:
+		return unix.EINVAL
+	case attrs.Name == name:
+		return nil
+	case f.nameUsed(name):
+		return unix.EEXIST
+	}
+	attrs.Name = name
+	f.links[attrs.Index] = attrs
+	return nil
+}
+
+func (f *fakeNetlink) LinkAddAltName(link netlink.Link, name string) error {
+	attrs, ok := f.links[link.Attrs().Index]
+	switch {
+	case !ok:
+		return unix.ENODEV
+	case slices.Contains(attrs.AltNames, name):
+		return unix.EEXIST
+	}
+	// The kernel allows the current name of the same link as the alternative one.
+	if attrs.Name != name && f.nameUsed(name) {
+		return unix.EEXIST
+	}
+	attrs.AltNames = append(attrs.AltNames, name)
+	f.links[attrs.Index] = attrs
+	return nil
+}
+
+func (f *fakeNetlink) LinkDelAltName(link netlink.Link, name string) error {
+	attrs, ok := f.links[link.Attrs().Index]
+	if !ok {
+		return unix.ENODEV
+	}
+	i := slices.Index(attrs.AltNames, name)
+	if i == -1 {
+		return unix.ENOENT
+	}
+	attrs.AltNames = slices.Delete(attrs.AltNames, i, i+1)
+	f.links[attrs.Index] = attrs
+	return nil
+}
+
+func (f *fakeNetlink) LinkSetAlias(link netlink.Link, alias string) error {
+	attrs, ok := f.links[link.Attrs().Index]
+	if !ok {
+		return unix.ENODEV
+	}
+	attrs.Alias = alias
+	f.links[attrs.Index] = attrs
+	return nil
+}
+
+func (f *fakeNetlink) NamespaceVEths(path string) ([]linkops.VEth, error) {
+	veths, ok := f.namespaces[path]
+	if !ok {
+		return nil, fmt.Errorf("network namespace does not exist: %s", path)
+	}
+	return veths, nil
+}
+
+// Returns the inspect record of the running container attached to the bridge network.
+func bridgeContainer(id string, name string, sandboxKey string, hardwareAddr string) container.InspectResponse {
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         id,
+			Name:       name,
+			State:      &container.State{Running: true},
+			HostConfig: &container.HostConfig{NetworkMode: "bridge"},
+		},
+		Config: &container.Config{Image: "nginx"},
+		NetworkSettings: &container.NetworkSettings{
+			NetworkSettingsBase: container.NetworkSettingsBase{SandboxKey: sandboxKey},
+			Networks:            map[string]*network.EndpointSettings{"bridge": {MacAddress: hardwareAddr}},
+		},
+	}
+}
+
+func TestRenameContainerLinks(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	f := useFakeNetlink(t)
+	f.addVeth(10, "veth1a2b3c4", "/var/run/docker/netns/web", "eth0", "02:42:ac:11:00:02")
+	f.addVeth(11, "veth5d6e7f8", "/var/run/docker/netns/web", "eth1", "02:42:ac:11:00:03")
+	// Another link holds the name of the second container link already.
+	f.links[12] = netlink.LinkAttrs{Index: 12, Name: "vweb1"}
+
+	rt := newFakeRuntime()
+	inspect := bridgeContainer("1234", "/web", "/var/run/docker/netns/web", "02:42:ac:11:00:02")
+
+	summary := renameContainerLinks(context.Background(), rt, inspect, false)
+	assert.Equal(t, 1, summary.Renamed)
+	assert.Equal(t, 1, summary.Failed)
+
+	assert.Equal(t, "vweb0", f.links[10].Name)
+	assert.Equal(t, []string{"veth1a2b3c4"}, f.links[10].AltNames)
+	assert.Equal(t, "veth5d6e7f8", f.links[11].Name)
+
+	mappings := state.Mappings()
+	require.Len(t, mappings, 1)
+	require.Len(t, mappings[0].Links, 1)
+	assert.Equal(t, LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "veth1a2b3c4", Name: "vweb0",
+		LinkLabels: LinkLabels{Image: "nginx", Network: "bridge"}}, mappings[0].Links[0])
+
+	// The renamed link is left as is on the next pass.
+	delete(f.links, 12)
+	summary = renameContainerLinks(context.Background(), rt, inspect, false)
+	assert.Equal(t, 1, summary.Renamed)
+	assert.Equal(t, 1, summary.Unchanged)
+	assert.Equal(t, "vweb1", f.links[11].Name)
+}
+
+func TestRenameContainerLinksAdopts(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	// Renamed by a previous run, which preserved the original name.
+	f := useFakeNetlink(t)
+	f.addVeth(10, "vweb0", "/var/run/docker/netns/web", "eth0", "")
+	f.links[10] = netlink.LinkAttrs{Index: 10, Name: "vweb0", AltNames: []string{"veth1a2b3c4"}}
+
+	summary := renameContainerLinks(context.Background(), newFakeRuntime(), bridgeContainer("1234", "/web", "/var/run/docker/netns/web", ""), false)
+	assert.Equal(t, 1, summary.Unchanged)
+
+	links := state.Links("1234")
+	require.Len(t, links, 1)
+	assert.Equal(t, "veth1a2b3c4", links[0].OriginalName)
+}
+
+func TestRenameContainerLinksNamespaceFailure(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+
+	useFakeNetlink(t)
+	summary := renameContainerLinks(context.Background(), newFakeRuntime(), bridgeContainer("1234", "/web", "/var/run/docker/netns/missing", ""), false)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 0, summary.Renamed)
+}
+
+func TestRestoreLinkName(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	config = defaultConfig()
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	f := useFakeNetlink(t)
+	f.links[10] = netlink.LinkAttrs{Index: 10, Name: "vweb0", AltNames: []string{"veth1a2b3c4"}, Alias: ownershipMarkerPrefix + "1234:0123456789abcdef"}
+	trackedLink := LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "veth1a2b3c4", Name: "vweb0"}
+	state.SetLink("1234", "/web", trackedLink)
+
+	restoreLinkName("1234", "/web", trackedLink)
+	assert.Equal(t, "veth1a2b3c4", f.links[10].Name)
+	assert.Empty(t, f.links[10].AltNames)
+	assert.Empty(t, f.links[10].Alias)
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Environment variable enabling the netlink tracing.
+const traceNetlinkEnv = "DVN_TRACE_NETLINK"
+
+// Log fields of the netlink tracing.
+const (
+	logFieldNetlinkOp = "netlink_op"
+	logFieldDuration  = "duration"
+)
+
+// Whether every netlink request is logged with its attributes and result.
+var traceNetlink bool
+
+// Enables the netlink tracing.
+func enableNetlinkTrace() {
+	traceNetlink = true
+}
+
+// Returns the attributes of the link for the trace.
+func netlinkLinkFields(link netlink.Link) log.Fields {
+	attrs := link.Attrs()
+	return log.Fields{
+		logFieldIndex:   attrs.Index,
+		logFieldLink:    attrs.Name,
+		"link_type":     link.Type(),
+		"parent_index":  attrs.ParentIndex,
+		"alt_names":     attrs.AltNames,
+		"flags":         attrs.Flags.String(),
+		"oper_state":    attrs.OperState.String(),
+		"hardware_addr": attrs.HardwareAddr.String(),
+		"netns_id":      attrs.NetNsID,
+	}
+}
+
+// Logs the netlink request with the fields, its duration, and its result.
+func traceNetlinkRequest(op string, fields log.Fields, start time.Time, err error) {
+	entry := log.WithFields(fields).WithFields(log.Fields{
+		logFieldNetlinkOp: op,
+		logFieldDuration:  time.Since(start).String(),
+	})
+	if err != nil {
+		entry.Infof("netlink %s failed: %s", op, err)
+	} else {
+		entry.Infof("netlink %s", op)
+	}
+}
+
+// Logs the link notification received from the kernel.
+func traceLinkUpdate(update netlink.LinkUpdate) {
+	op := "RTM_NEWLINK"
+	if update.Header.Type == unix.RTM_DELLINK {
+		op = "RTM_DELLINK"
+	}
+
+	fields := log.Fields{logFieldIndex: update.Index}
+	if update.Link != nil {
+		fields = netlinkLinkFields(update.Link)
+	}
+	log.WithFields(fields).WithField(logFieldNetlinkOp, op).Infof("netlink %s", op)
+}
+
+// Lists the links, tracing the response.
+func nlLinkList() ([]netlink.Link, error) {
+	start := time.Now()
+	links, err := nl.LinkList()
+	if traceNetlink {
+		traceNetlinkRequest("LinkList", log.Fields{"count": len(links)}, start, err)
+		for _, link := range links {
+			log.WithFields(netlinkLinkFields(link)).WithField(logFieldNetlinkOp, "LinkList").Info("netlink LinkList response")
+		}
+	}
+	return links, err
+}
+
+// Lists the veth links within the network namespace of the path, tracing the response.
+func nlNamespaceVEths(path string) ([]linkops.VEth, error) {
+	start := time.Now()
+	veths, err := nl.NamespaceVEths(path)
+	if traceNetlink {
+		traceNetlinkRequest("NamespaceVEths", log.Fields{"netns": path, "count": len(veths)}, start, err)
+		for _, veth := range veths {
+			log.WithFields(log.Fields{
+				logFieldLink:    veth.Name,
+				"netns":         path,
+				"parent_index":  veth.ParentIndex,
+				"hardware_addr": veth.HardwareAddr,
+			}).WithField(logFieldNetlinkOp, "NamespaceVEths").Info("netlink NamespaceVEths response")
+		}
+	}
+	return veths, err
+}
+
+// Returns the link by the index, tracing the response.
+func nlLinkByIndex(index int) (netlink.Link, error) {
+	start := time.Now()
+	link, err := nl.LinkByIndex(index)
+	if traceNetlink {
+		fields := log.Fields{logFieldIndex: index}
+		if err == nil {
+			fields = netlinkLinkFields(link)
+		}
+		traceNetlinkRequest("LinkByIndex", fields, start, err)
+	}
+	return link, err
+}
+
+// Returns the link by the name, tracing the response.
+func nlLinkByName(name string) (netlink.Link, error) {
+	start := time.Now()
+	link, err := nl.LinkByName(name)
+	if traceNetlink {
+		fields := log.Fields{logFieldLink: name}
+		if err == nil {
+			fields = netlinkLinkFields(link)
+		}
+		traceNetlinkRequest("LinkByName", fields, start, err)
+	}
+	return link, err
+}
+
+// Renames the link, tracing the request.
+func nlLinkSetName(link netlink.Link, name string) error {
+	start := time.Now()
+	err := nl.LinkSetName(link, name)
+	if traceNetlink {
+		traceNetlinkRequest("LinkSetName", log.Fields{
+			logFieldIndex:   link.Attrs().Index,
+			logFieldOldName: link.Attrs().Name,
+			logFieldNewName: name,
+		}, start, err)
+	}
+	return err
+}
+
+// Adds the alternative name to the link, tracing the request.
+func nlLinkAddAltName(link netlink.Link, name string) error {
+	start := time.Now()
+	err := nl.LinkAddAltName(link, name)
+	if traceNetlink {
+		traceNetlinkRequest("LinkAddAltName", log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: link.Attrs().Name, "alt_name": name}, start, err)
+	}
+	return err
+}
+
+// Deletes the alternative name of the link, tracing the request.
+func nlLinkDelAltName(link netlink.Link, name string) error {
+	start := time.Now()
+	err := nl.LinkDelAltName(link, name)
+	if traceNetlink {
+		traceNetlinkRequest("LinkDelAltName", log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: link.Attrs().Name, "alt_name": name}, start, err)
+	}
+	return err
+}
+
+// Sets the alias of the link, tracing the request.
+func nlLinkSetAlias(link netlink.Link, alias string) error {
+	start := time.Now()
+	err := nl.LinkSetAlias(link, alias)
+	if traceNetlink {
+		traceNetlinkRequest("LinkSetAlias", log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: link.Attrs().Name, "alias": alias}, start, err)
+	}
+	return err
+}
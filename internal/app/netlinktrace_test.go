@@ -0,0 +1,78 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestNetlinkLinkFields(t *testing.T) {
+	mac, err := net.ParseMAC("02:42:ac:11:00:02")
+	require.NoError(t, err)
+
+	link := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{
+		Index:        12,
+		Name:         "vweb0",
+		ParentIndex:  4,
+		AltNames:     []string{"veth1a2b3c4"},
+		Flags:        net.FlagUp,
+		HardwareAddr: mac,
+	}}
+
+	fields := netlinkLinkFields(link)
+	assert.Equal(t, 12, fields[logFieldIndex])
+	assert.Equal(t, "vweb0", fields[logFieldLink])
+	assert.Equal(t, "veth", fields["link_type"])
+	assert.Equal(t, 4, fields["parent_index"])
+	assert.Equal(t, []string{"veth1a2b3c4"}, fields["alt_names"])
+	assert.Equal(t, "up", fields["flags"])
+	assert.Equal(t, "02:42:ac:11:00:02", fields["hardware_addr"])
+}
+
+func TestTraceNetlinkRequest(t *testing.T) {
+	var b bytes.Buffer
+	out, formatter := log.StandardLogger().Out, log.StandardLogger().Formatter
+	log.SetOutput(&b)
+	log.SetFormatter(&log.JSONFormatter{})
+	defer func() {
+		log.SetOutput(out)
+		log.SetFormatter(formatter)
+	}()
+
+	traceNetlinkRequest("LinkSetName", log.Fields{logFieldIndex: 12}, time.Now(), errors.New("file exists"))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(b.Bytes(), &record))
+	assert.Equal(t, "netlink LinkSetName failed: file exists", record["msg"])
+	assert.Equal(t, "LinkSetName", record[logFieldNetlinkOp])
+	assert.EqualValues(t, 12, record[logFieldIndex])
+	assert.Contains(t, record, logFieldDuration)
+}
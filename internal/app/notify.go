@@ -0,0 +1,231 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	NotificationMappingAdded   = "mapping_added"
+	NotificationMappingRemoved = "mapping_removed"
+
+	// Number of notifications queued for a sink before new ones are dropped.
+	notificationQueueSize = 256
+	// Timeout of the webhook HTTP request.
+	webhookTimeout = 5 * time.Second
+	// Number of recent notifications kept for the control socket clients.
+	notificationHistorySize = 100
+)
+
+// Change of the host link mapping.
+type Notification struct {
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	ContainerLink string    `json:"container_link"`
+	Index         int       `json:"ifindex"`
+	OriginalName  string    `json:"original_name"`
+	Name          string    `json:"name"`
+}
+
+// Receiver of the mapping notifications.
+// Implementations must not block the caller.
+type NotificationSink interface {
+	Notify(n Notification)
+	// Releases the sink resources. Notifications queued already may still be delivered.
+	Close()
+}
+
+// Receiver of the current link mappings, publishing them to an external system in background.
+type MappingPublisher interface {
+	// Schedules publishing of the mappings. Must not block the caller.
+	Publish(mappings []ContainerMapping)
+	// Stops publishing. The mappings queued already may be dropped.
+	Close()
+}
+
+// Sends the mappings to the channel of capacity 1, replacing the mappings not yet taken from it.
+func offerMappings(updates chan []ContainerMapping, mappings []ContainerMapping) {
+	for {
+		select {
+		case updates <- mappings:
+			return
+		default:
+		}
+
+		select {
+		case <-updates:
+		default:
+		}
+	}
+}
+
+// Sinks receiving the mapping notifications.
+var notificationSinks []NotificationSink
+
+// Recent notifications, kept regardless of the configured sinks.
+var notificationHistory = newHistorySink(notificationHistorySize)
+
+// Makes notification sinks according to the configuration, replacing the existing ones.
+// The D-Bus sink answers the mapping queries when serving, as done by the listen command.
+func setupNotificationSinks(dbusServe bool) {
+	for _, sink := range notificationSinks {
+		sink.Close()
+	}
+
+	notificationSinks = nil
+	for _, url := range config.NotificationWebhooks {
+		notificationSinks = append(notificationSinks, newWebhookSink(url))
+	}
+
+	if len(config.MQTTBroker) > 0 {
+		sink, err := newMQTTSink(config.MQTTBroker, config.MQTTTopicPrefix)
+		if err != nil {
+			log.Errorf("Cannot publish notifications to MQTT broker: %s", err)
+		} else {
+			notificationSinks = append(notificationSinks, sink)
+		}
+	}
+
+	if len(config.NATSURL) > 0 {
+		sink, err := newNATSSink(config.NATSURL, config.NATSSubjectPrefix)
+		if err != nil {
+			log.Errorf("Cannot publish notifications to NATS server: %s", err)
+		} else {
+			notificationSinks = append(notificationSinks, sink)
+		}
+	}
+
+	if config.DBus {
+		notificationSinks = append(notificationSinks, newDBusSink(dbusServe))
+	}
+}
+
+// Sends the notification to all configured sinks.
+func notify(notificationType string, containerID string, containerName string, link LinkState) {
+	n := Notification{
+		Type:          notificationType,
+		Time:          time.Now(),
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		ContainerLink: link.ContainerLink,
+		Index:         link.Index,
+		OriginalName:  link.OriginalName,
+		Name:          link.Name,
+	}
+
+	notificationHistory.Notify(n)
+	eventBroker.Publish(notificationStreamEvent(n))
+	for _, sink := range notificationSinks {
+		sink.Notify(n)
+	}
+}
+
+// Keeps the recent notifications in memory.
+type HistorySink struct {
+	mu            sync.Mutex
+	size          int
+	notifications []Notification
+}
+
+func newHistorySink(size int) *HistorySink {
+	return &HistorySink{size: size}
+}
+
+func (s *HistorySink) Notify(n Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.notifications) == s.size {
+		s.notifications = slices.Delete(s.notifications, 0, 1)
+	}
+	s.notifications = append(s.notifications, n)
+}
+
+func (s *HistorySink) Close() {}
+
+// Returns a copy of the recent notifications, oldest first.
+func (s *HistorySink) Notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.notifications)
+}
+
+// Posts notifications as JSON documents to the URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan Notification
+}
+
+func newWebhookSink(url string) *WebhookSink {
+	s := &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+		queue:  make(chan Notification, notificationQueueSize),
+	}
+	go s.run()
+	return s
+}
+
+func (s *WebhookSink) Notify(n Notification) {
+	select {
+	case s.queue <- n:
+	default:
+		log.Errorf("Webhook queue is full, notification dropped: %s %s", s.url, n.Type)
+	}
+}
+
+func (s *WebhookSink) Close() {
+	close(s.queue)
+}
+
+// Delivers queued notifications in order of appearance.
+func (s *WebhookSink) run() {
+	for n := range s.queue {
+		body, err := json.Marshal(n)
+		if err != nil {
+			log.Errorf("json.Marshal to bytes failed: %s", err)
+			continue
+		}
+
+		resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Errorf("Webhook request failed: %s: %s", s.url, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			log.Errorf("Webhook request failed: %s: %s", s.url, resp.Status)
+		}
+	}
+}
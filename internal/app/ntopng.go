@@ -0,0 +1,196 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Timeout of the ntopng REST API request.
+	ntopngTimeout = 10 * time.Second
+	// Interval of setting all aliases again, e.g. after ntopng restarts, or starts monitoring more interfaces.
+	ntopngResyncInterval = 10 * time.Minute
+)
+
+// Reply envelope of the ntopng REST API.
+type NtopngReply[T any] struct {
+	RC    int    `json:"rc"`
+	RCStr string `json:"rc_str"`
+	Rsp   T      `json:"rsp"`
+}
+
+// Interface monitored by ntopng.
+type NtopngInterface struct {
+	ID   int    `json:"ifid"`
+	Name string `json:"ifname"`
+}
+
+// Sets the custom names of the host links monitored by ntopng to "<container name>:<container link>"
+// via the ntopng REST API. The aliases are cleared when the mappings are removed.
+type NtopngPublisher struct {
+	url    string
+	header http.Header
+	client *http.Client
+	// Receives the latest mappings, older ones not yet published are replaced.
+	updates chan []ContainerMapping
+	done    chan struct{}
+
+	// Aliases set, by the interface name.
+	published map[string]string
+	// Aliases to be set, by the interface name.
+	desired map[string]string
+}
+
+func newNtopngPublisher(rawURL string, token string) (*NtopngPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	if len(token) > 0 {
+		header.Set("Authorization", "Token "+token)
+	} else if u.User != nil {
+		password, _ := u.User.Password()
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(u.User.Username()+":"+password)))
+	}
+	u.User = nil
+
+	p := &NtopngPublisher{
+		url:     strings.TrimSuffix(u.String(), "/"),
+		header:  header,
+		client:  &http.Client{Timeout: ntopngTimeout},
+		updates: make(chan []ContainerMapping, 1),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *NtopngPublisher) Publish(mappings []ContainerMapping) {
+	offerMappings(p.updates, mappings)
+}
+
+func (p *NtopngPublisher) Close() {
+	close(p.done)
+}
+
+// Sets the aliases on the mapping changes, and all of them periodically.
+func (p *NtopngPublisher) run() {
+	ticker := time.NewTicker(ntopngResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+
+		case mappings := <-p.updates:
+			p.desired = ntopngAliases(mappings)
+			p.sync()
+
+		case <-ticker.C:
+			p.published = nil
+			p.sync()
+		}
+	}
+}
+
+// Returns the aliases by the interface name. ntopng may know the host link by its original name,
+// if it started monitoring the link before renaming.
+func ntopngAliases(mappings []ContainerMapping) map[string]string {
+	aliases := map[string]string{}
+	for _, record := range mappingRecords(mappings) {
+		alias := record.ContainerName + ":" + record.ContainerLink
+		aliases[record.Name] = alias
+		if len(record.OriginalName) > 0 {
+			aliases[record.OriginalName] = alias
+		}
+	}
+	return aliases
+}
+
+// Sets the changed aliases of the interfaces monitored by ntopng, and clears the removed ones.
+// The aliases failed to be set are retried on the next sync.
+func (p *NtopngPublisher) sync() {
+	if p.desired == nil {
+		return
+	}
+	if p.published == nil {
+		p.published = map[string]string{}
+	}
+
+	interfaces, err := p.interfaces()
+	if err != nil {
+		log.Errorf("Cannot list ntopng interfaces: %s", err)
+		return
+	}
+
+	for _, iface := range interfaces {
+		alias, ok := p.desired[iface.Name]
+		published, wasPublished := p.published[iface.Name]
+		if (ok && wasPublished && published == alias) || (!ok && !wasPublished) {
+			continue
+		}
+
+		if err := p.setAlias(iface.ID, alias); err != nil {
+			log.Errorf("Cannot set ntopng interface alias: %s => %q: %s", iface.Name, alias, err)
+			continue
+		}
+		if ok {
+			p.published[iface.Name] = alias
+		} else {
+			delete(p.published, iface.Name)
+		}
+	}
+}
+
+// Returns the interfaces monitored by ntopng.
+func (p *NtopngPublisher) interfaces() ([]NtopngInterface, error) {
+	var reply NtopngReply[[]NtopngInterface]
+	if err := jsonRequest(p.client, http.MethodGet, p.url+"/lua/rest/v2/get/ntopng/interfaces.lua", p.header, nil, &reply); err != nil {
+		return nil, err
+	}
+	if reply.RC != 0 {
+		return nil, fmt.Errorf("ntopng responded: %s", reply.RCStr)
+	}
+	return reply.Rsp, nil
+}
+
+// Sets the custom name of the interface. Empty alias clears it.
+func (p *NtopngPublisher) setAlias(ifid int, alias string) error {
+	request := map[string]any{"ifid": ifid, "custom_name": alias}
+	var reply NtopngReply[any]
+	if err := jsonRequest(p.client, http.MethodPost, p.url+"/lua/rest/v2/set/interface/alias.lua", p.header, request, &reply); err != nil {
+		return err
+	}
+	if reply.RC != 0 {
+		return fmt.Errorf("ntopng responded: %s", reply.RCStr)
+	}
+	return nil
+}
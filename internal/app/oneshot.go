@@ -0,0 +1,178 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	log "github.com/sirupsen/logrus"
+)
+
+// Outcome of the host link renaming.
+type RenameOutcome int
+
+const (
+	RenameDone RenameOutcome = iota
+	// The link has the expected name already.
+	RenameUnchanged
+	// Renaming is paused.
+	RenameSkipped
+	RenameFailed
+	// The rename is postponed by the rate limit, to be tried again after waitRenameRateLimit.
+	RenameThrottled
+)
+
+// Failure of the container or container link processing.
+type RenameFailure struct {
+	// Container name, or ID when the name is not known.
+	Container string `json:"container"`
+	// Empty when the container as a whole failed.
+	ContainerLink string `json:"container_link,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// Counts of the host link renaming outcomes.
+type RenameSummary struct {
+	Renamed   int `json:"renamed"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+	// Containers without own network namespace or veth links.
+	ContainersSkipped int             `json:"containers_skipped"`
+	Failures          []RenameFailure `json:"failures,omitempty"`
+}
+
+// Counts the outcome.
+func (s *RenameSummary) Count(outcome RenameOutcome) {
+	switch outcome {
+	case RenameDone:
+		s.Renamed++
+	case RenameUnchanged:
+		s.Unchanged++
+	case RenameSkipped:
+		s.Skipped++
+	case RenameFailed:
+		s.Failed++
+	}
+}
+
+// Counts the failure with the reason.
+func (s *RenameSummary) Fail(container string, containerLink string, reason string) {
+	s.Failed++
+	s.Failures = append(s.Failures, RenameFailure{
+		Container:     strings.TrimPrefix(container, "/"),
+		ContainerLink: containerLink,
+		Reason:        reason,
+	})
+}
+
+// Adds the counts of the other summary.
+func (s *RenameSummary) Add(other RenameSummary) {
+	s.Renamed += other.Renamed
+	s.Unchanged += other.Unchanged
+	s.Skipped += other.Skipped
+	s.Failed += other.Failed
+	s.ContainersSkipped += other.ContainersSkipped
+	s.Failures = append(s.Failures, other.Failures...)
+}
+
+// Result of the oneshot run.
+type OneshotResult struct {
+	// Number of the running containers inspected.
+	Inspected int `json:"inspected"`
+	RenameSummary
+	// Whether the run was stopped on the first failure.
+	Aborted    bool               `json:"aborted"`
+	Containers []ContainerMapping `json:"containers"`
+}
+
+// Prints the end-of-run summary.
+func (r OneshotResult) printSummary(w io.Writer) error {
+	aborted := ""
+	if r.Aborted {
+		aborted = " (stopped on the first failure)"
+	}
+	fmt.Fprintf(w, "Containers inspected: %d, skipped: %d\n", r.Inspected, r.ContainersSkipped)
+	_, err := fmt.Fprintf(w, "Links renamed: %d, unchanged: %d, skipped: %d, failed: %d%s\n",
+		r.Renamed, r.Unchanged, r.Skipped, r.Failed, aborted)
+	if err != nil || len(r.Failures) == 0 {
+		return err
+	}
+
+	fmt.Fprintln(w, "Failures:")
+	for _, f := range r.Failures {
+		if len(f.ContainerLink) > 0 {
+			_, err = fmt.Fprintf(w, "  %s %s: %s\n", f.Container, f.ContainerLink, f.Reason)
+		} else {
+			_, err = fmt.Fprintf(w, "  %s: %s\n", f.Container, f.Reason)
+		}
+	}
+	return err
+}
+
+// Updates host link names for the containers specified by names or IDs,
+// or for all running containers matching the filters when none specified.
+// With failFast, the run stops on the first failure.
+func runOneshot(ctx context.Context, rt ContainerRuntime, containers []string, filterArgs filters.Args, failFast bool) OneshotResult {
+	var result OneshotResult
+
+	var inspects []container.InspectResponse
+	if len(containers) == 0 {
+		inspects = inspectRunningContainers(ctx, rt, filterArgs)
+	}
+
+	for _, nameOrID := range containers {
+		inspect, err := inspectContainer(ctx, rt, nameOrID)
+		if err != nil {
+			log.Errorf("cli.ContainerInspect failed for container %s: %s", nameOrID, err)
+			result.Fail(nameOrID, "", fmt.Sprintf("cli.ContainerInspect failed: %s", err))
+		} else if inspect.State == nil || !inspect.State.Running {
+			log.Errorf("Container is not running: %s %s", inspect.Name, inspect.ID)
+			result.Fail(inspect.Name, "", "container is not running")
+		} else {
+			inspects = append(inspects, inspect)
+			continue
+		}
+
+		if failFast {
+			result.Aborted = true
+			return result
+		}
+	}
+
+	result.Inspected = len(inspects)
+	for _, inspect := range inspects {
+		result.Add(renameContainerLinks(ctx, rt, inspect, false))
+
+		if failFast && result.Failed > 0 {
+			result.Aborted = true
+			break
+		}
+	}
+
+	result.Containers = state.Mappings()
+	return result
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameSummary(t *testing.T) {
+	var summary RenameSummary
+	for _, outcome := range []RenameOutcome{RenameDone, RenameDone, RenameUnchanged, RenameSkipped, RenameFailed} {
+		summary.Count(outcome)
+	}
+	assert.Equal(t, RenameSummary{Renamed: 2, Unchanged: 1, Skipped: 1, Failed: 1}, summary)
+
+	result := OneshotResult{Inspected: 3, Aborted: true}
+	result.Add(summary)
+	result.Add(RenameSummary{Renamed: 1, ContainersSkipped: 1})
+
+	var other RenameSummary
+	other.Fail("/web", "eth0", "netlink.LinkSetName failed: file exists")
+	other.Fail("db", "", "sandbox is not defined")
+	result.Add(other)
+
+	var b strings.Builder
+	assert.NoError(t, result.printSummary(&b))
+	assert.Equal(t, `Containers inspected: 3, skipped: 1
+Links renamed: 3, unchanged: 1, skipped: 1, failed: 3 (stopped on the first failure)
+Failures:
+  web eth0: netlink.LinkSetName failed: file exists
+  db: sandbox is not defined
+`, b.String())
+}
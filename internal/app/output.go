@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+var outputFormats = []string{OutputTable, OutputJSON, OutputYAML}
+
+// Format of the command results.
+var outputFormat = OutputTable
+
+// Validates the output format.
+func parseOutputFormat(format string) (string, error) {
+	if !slices.Contains(outputFormats, format) {
+		return "", fmt.Errorf("unknown output format: %q, expected one of %v", format, outputFormats)
+	}
+	return format, nil
+}
+
+// Writes the command result in the selected output format. The table is written by the callback.
+// The field names in JSON and YAML are defined by the JSON tags.
+func printOutput(w io.Writer, v any, table func(w io.Writer) error) error {
+	switch outputFormat {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+
+	case OutputYAML:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		// JSON is valid YAML, the document is decoded preserving the field order.
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		resetYAMLStyle(&node)
+
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	default:
+		return table(w)
+	}
+}
+
+// Switches the node decoded from JSON to the block style.
+func resetYAMLStyle(node *yaml.Node) {
+	// The strings are quoted by the encoder when needed.
+	node.Style = 0
+
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
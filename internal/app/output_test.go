@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintOutput(t *testing.T) {
+	defer func() { outputFormat = OutputTable }()
+
+	value := struct {
+		Name  string   `json:"name"`
+		Index int      `json:"index"`
+		Links []string `json:"links"`
+	}{Name: "web", Index: 10, Links: []string{"vweb0", "true"}}
+
+	table := func(w io.Writer) error {
+		_, err := io.WriteString(w, "table\n")
+		return err
+	}
+
+	var b strings.Builder
+	assert.NoError(t, printOutput(&b, value, table))
+	assert.Equal(t, "table\n", b.String())
+
+	outputFormat = OutputJSON
+	b.Reset()
+	assert.NoError(t, printOutput(&b, value, table))
+	assert.Equal(t, "{\n  \"name\": \"web\",\n  \"index\": 10,\n  \"links\": [\n    \"vweb0\",\n    \"true\"\n  ]\n}\n", b.String())
+
+	outputFormat = OutputYAML
+	b.Reset()
+	assert.NoError(t, printOutput(&b, value, table))
+	assert.Equal(t, "name: web\nindex: 10\nlinks:\n  - vweb0\n  - \"true\"\n", b.String())
+
+	_, err := parseOutputFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Interval of checking whether the maintenance mode is toggled.
+const pauseCheckInterval = 2 * time.Second
+
+// Returns whether renaming is paused by the maintenance mode.
+// The mode is enabled while the pause file exists.
+func isPaused() bool {
+	if len(config.PauseFile) == 0 {
+		return false
+	}
+
+	_, err := os.Stat(config.PauseFile)
+	return err == nil
+}
+
+// Enables or disables the maintenance mode by creating or removing the pause file.
+func setPaused(paused bool) error {
+	if len(config.PauseFile) == 0 {
+		return errors.New("pause_file is not configured")
+	}
+
+	if !paused {
+		err := os.Remove(config.PauseFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.PauseFile), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(config.PauseFile, []byte(time.Now().Format(time.RFC3339)+"\n"), 0o644)
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Version of the plan file format.
+const planVersion = 1
+
+// Intended renames of host links, reviewed before being applied.
+type Plan struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Hostname  string    `json:"hostname"`
+	// Host links to be renamed. Links named as expected are not included.
+	Renames []LinkMapping `json:"renames"`
+}
+
+// Returns the plan renaming host links of the running containers, whose names differ from the expected ones.
+func makePlan(mappings []LinkMapping) Plan {
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Warnf("os.Hostname failed: %s", err)
+	}
+
+	plan := Plan{Version: planVersion, CreatedAt: time.Now().UTC(), Hostname: hostname, Renames: []LinkMapping{}}
+	for _, m := range mappings {
+		switch {
+		case len(m.Name) == 0:
+			log.Warnf("Host link is missing, not planned: %s %s", m.ContainerName, m.ContainerLink)
+		case len(m.TargetName) == 0:
+			log.Warnf("Cannot make host link name, not planned: %s %s", m.ContainerName, m.ContainerLink)
+		case m.Name != m.TargetName:
+			plan.Renames = append(plan.Renames, m)
+		}
+	}
+	return plan
+}
+
+// Writes the plan to the file, or to the writer when the path is empty.
+func writePlan(w io.Writer, path string, plan Plan, force bool) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeGeneratedConfig(w, path, string(data)+"\n", force)
+}
+
+// Reads the plan from the file, or from the standard input when the path is "-".
+func readPlan(path string) (Plan, error) {
+	var plan Plan
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return plan, err
+	}
+
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return plan, fmt.Errorf("invalid plan: %w", err)
+	}
+	if plan.Version != planVersion {
+		return plan, fmt.Errorf("unsupported plan version: %d", plan.Version)
+	}
+	return plan, nil
+}
+
+// Returns the differences between the plan and the current mappings, which make the plan not applicable.
+func planDrift(plan Plan, hostname string, mappings []LinkMapping) []string {
+	var drift []string
+	if plan.Hostname != hostname {
+		drift = append(drift, fmt.Sprintf("plan is made for host %q, not %q", plan.Hostname, hostname))
+	}
+
+	current := make(map[int]LinkMapping, len(mappings))
+	for _, m := range mappings {
+		current[m.Index] = m
+	}
+
+	for _, planned := range plan.Renames {
+		m, ok := current[planned.Index]
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("%s %s: host link %s (ifindex %d) is gone",
+				planned.ContainerName, planned.ContainerLink, planned.Name, planned.Index))
+		case m.ContainerID != planned.ContainerID || m.ContainerLink != planned.ContainerLink:
+			drift = append(drift, fmt.Sprintf("%s %s: host link %s (ifindex %d) belongs to %s %s now",
+				planned.ContainerName, planned.ContainerLink, planned.Name, planned.Index, m.ContainerName, m.ContainerLink))
+		case m.Name != planned.Name:
+			drift = append(drift, fmt.Sprintf("%s %s: host link %s is renamed to %s",
+				planned.ContainerName, planned.ContainerLink, planned.Name, m.Name))
+		case m.TargetName != planned.TargetName:
+			drift = append(drift, fmt.Sprintf("%s %s: target name %s is changed to %s",
+				planned.ContainerName, planned.ContainerLink, planned.TargetName, m.TargetName))
+		}
+	}
+	return drift
+}
+
+// Renames the host links exactly as planned. Refuses to rename anything if the environment drifted since the plan was made.
+func applyPlan(ctx context.Context, rt ContainerRuntime, plan Plan) (RenameSummary, error) {
+	var summary RenameSummary
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return summary, fmt.Errorf("os.Hostname failed: %w", err)
+	}
+
+	if drift := planDrift(plan, hostname, runningLinkMappings(ctx, rt)); len(drift) > 0 {
+		return summary, fmt.Errorf("environment drifted since the plan was made, nothing is renamed:\n  %s",
+			strings.Join(drift, "\n  "))
+	}
+
+	for _, planned := range plan.Renames {
+		link, err := nlLinkByIndex(planned.Index)
+		if err != nil {
+			log.Errorf("netlink.LinkByIndex failed: %s", err)
+			summary.Fail(planned.ContainerName, planned.ContainerLink, fmt.Sprintf("netlink.LinkByIndex failed: %s", err))
+			continue
+		}
+
+		outcome, err := updateLinkNameThrottled(ctx, link, planned.ContainerID, "/"+planned.ContainerName, planned.ContainerLink, LinkLabels{})
+		if err != nil {
+			summary.Fail(planned.ContainerName, planned.ContainerLink, err.Error())
+		} else {
+			summary.Count(outcome)
+		}
+	}
+	return summary, nil
+}
+
+// Prints the planned renames as a table.
+func (p Plan) print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tID\tLINK\tIFINDEX\tHOST LINK\tTARGET NAME")
+	for _, m := range p.Renames {
+		fmt.Fprintf(tw, "%s\t%.12s\t%s\t%d\t%s\t%s\n",
+			m.ContainerName, m.ContainerID, m.ContainerLink, m.Index, m.Name, m.TargetName)
+	}
+	return tw.Flush()
+}
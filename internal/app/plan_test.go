@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakePlan(t *testing.T) {
+	mappings := []LinkMapping{
+		{ContainerID: "0123", ContainerName: "web", ContainerLink: "eth0", Index: 10, Name: "veth1", TargetName: "vweb0"},
+		{ContainerID: "0123", ContainerName: "web", ContainerLink: "eth1", Index: 11, Name: "vweb1", TargetName: "vweb1"},
+		{ContainerID: "4567", ContainerName: "db", ContainerLink: "eth0", Index: 12, TargetName: "vdb0"},
+		{ContainerID: "4567", ContainerName: "db", ContainerLink: "eth1", Index: 13, Name: "veth2"},
+	}
+
+	plan := makePlan(mappings)
+	assert.Equal(t, planVersion, plan.Version)
+	assert.Equal(t, mappings[:1], plan.Renames)
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, writePlan(nil, path, plan, false))
+	require.Error(t, writePlan(nil, path, plan, false))
+
+	read, err := readPlan(path)
+	require.NoError(t, err)
+	assert.Equal(t, plan.Renames, read.Renames)
+	assert.Equal(t, plan.Hostname, read.Hostname)
+
+	var buf bytes.Buffer
+	require.NoError(t, writePlan(&buf, "", plan, false))
+	assert.Contains(t, buf.String(), `"target_name": "vweb0"`)
+}
+
+func TestPlanDrift(t *testing.T) {
+	planned := LinkMapping{ContainerID: "0123", ContainerName: "web", ContainerLink: "eth0", Index: 10, Name: "veth1", TargetName: "vweb0"}
+	plan := Plan{Version: planVersion, Hostname: "host", Renames: []LinkMapping{planned}}
+
+	assert.Empty(t, planDrift(plan, "host", []LinkMapping{planned}))
+	assert.Len(t, planDrift(plan, "other", []LinkMapping{planned}), 1)
+	assert.Len(t, planDrift(plan, "host", nil), 1)
+
+	changed := planned
+	changed.ContainerID = "4567"
+	assert.Contains(t, planDrift(plan, "host", []LinkMapping{changed})[0], "belongs to")
+
+	changed = planned
+	changed.Name = "vweb0"
+	assert.Contains(t, planDrift(plan, "host", []LinkMapping{changed})[0], "is renamed to")
+
+	changed = planned
+	changed.TargetName = "vw0"
+	assert.Contains(t, planDrift(plan, "host", []LinkMapping{changed})[0], "target name")
+}
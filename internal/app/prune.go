@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Interval of dropping the expired entries of the internal caches, and the mappings of the links which are gone.
+// Keeps the memory of a long-running daemon bounded on hosts with high container churn.
+const pruneInterval = 10 * time.Minute
+
+// Removes the oldest entries of the map beyond the maximum number. Returns the number of the removed entries.
+func evictOldest[K comparable, V any](m map[K]V, maxEntries int, since func(V) time.Time) int {
+	excess := len(m) - maxEntries
+	if excess <= 0 {
+		return 0
+	}
+
+	keys := slices.Collect(maps.Keys(m))
+	slices.SortFunc(keys, func(a, b K) int {
+		return since(m[a]).Compare(since(m[b]))
+	})
+	for _, key := range keys[:excess] {
+		delete(m, key)
+	}
+	return excess
+}
+
+// Drops the mappings of the host links which no longer exist, e.g. when the exit event of the container was missed.
+// The mappings are released like on the container exit. Returns the number of the dropped mappings.
+func dropGoneLinks() int {
+	dropped := 0
+	for _, cs := range state.Containers() {
+		for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+			_, err := nlLinkByIndex(index)
+			if !isLinkNotFound(err) {
+				continue
+			}
+
+			trackedLink := *cs.Links[index]
+			state.RemoveLink(cs.ID, index)
+			flapDetector.Forget(index)
+			recordRelease(trackedLink)
+			notify(NotificationMappingRemoved, cs.ID, cs.Name, trackedLink)
+			trackedLinkLogger(cs.ID, cs.Name, trackedLink).Infof("Link is gone, mapping removed: %s %s: %s", cs.Name, trackedLink.ContainerLink, trackedLink.Name)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Drops the expired entries of the internal caches, the mappings of the links which are gone,
+// and the assignments of the timeline database beyond the retention.
+func (l *EventLoop) prune() {
+	now := time.Now()
+
+	pruned := inspectCache.Prune(now, config.InspectCacheTTL, config.MaxCacheEntries)
+	pruned += flapDetector.Prune(now, config.FlapWindow)
+	pruned += alerter.Prune(now, config.Alerts)
+	pruned += l.linkWatcher.Prune(config.MaxCacheEntries)
+
+	// The connect events are looked up only within the start fallback delay.
+	for containerID, seen := range l.connectSeen {
+		if now.Sub(seen) >= pruneInterval {
+			delete(l.connectSeen, containerID)
+			pruned++
+		}
+	}
+
+	if dropped := dropGoneLinks(); dropped > 0 {
+		l.publishMappings()
+		pruned += dropped
+	}
+
+	pruned += pruneTimeline(now)
+
+	if pruned > 0 {
+		log.Debugf("Pruned %d internal entries", pruned)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictOldest(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	m := map[int]time.Time{1: base.Add(2 * time.Second), 2: base, 3: base.Add(time.Second)}
+	since := func(t time.Time) time.Time { return t }
+
+	assert.Zero(t, evictOldest(m, 3, since))
+	assert.Equal(t, 2, evictOldest(m, 1, since))
+	assert.Equal(t, map[int]time.Time{1: base.Add(2 * time.Second)}, m)
+}
+
+func TestDropGoneLinks(t *testing.T) {
+	f := useFakeNetlink(t)
+	prevState := state
+	t.Cleanup(func() { state = prevState })
+	state = newState()
+
+	f.addVeth(10, "vweb0", "/var/run/docker/netns/1", "eth0", "")
+	state.SetLink("1234", "/web", LinkState{Index: 10, ContainerLink: "eth0", Name: "vweb0"})
+	state.SetLink("1234", "/web", LinkState{Index: 11, ContainerLink: "eth1", Name: "vweb1"})
+
+	defer func(c Config) { config = c }(config)
+	config.TimelineDB = filepath.Join(t.TempDir(), "timeline.db")
+	t.Cleanup(closeTimeline)
+	recordAssignment("1234", "/web", LinkState{Index: 11, ContainerLink: "eth1", Name: "vweb1"})
+
+	events := eventBroker.Subscribe()
+	defer eventBroker.Unsubscribe(events)
+
+	assert.Equal(t, 1, dropGoneLinks())
+	assert.True(t, state.TracksLink(10))
+	assert.False(t, state.TracksLink(11))
+
+	e := <-events
+	assert.Equal(t, NotificationMappingRemoved, e.Type)
+	assert.Equal(t, "vweb1", e.NewName)
+
+	// The gone link is released in the timeline.
+	_, err := lookupLinkAt("vweb1", 0, time.Now())
+	assert.ErrorContains(t, err, " (release by web): vweb1")
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Timeout of fetching the remote configuration, and its signature.
+	remoteConfigTimeout = 30 * time.Second
+	// Maximal size of the remote configuration.
+	remoteConfigMaxSize = 1 << 20
+)
+
+var (
+	// File caching the last fetched remote configuration. Its URL and ETag are kept in the file with the ".etag" suffix,
+	// and its signature in the file with the ".sig" suffix.
+	remoteConfigCachePath = "/var/lib/docker-veth-namer/config-cache.yml"
+
+	// File with the PEM-encoded Ed25519 public key verifying the remote configuration. Not verified when empty.
+	remoteConfigPublicKeyPath string
+)
+
+// Returns whether the configuration is fetched from the HTTP(S) URL instead of the local file.
+func isRemoteConfig(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// Returns the remote configuration: fetched from the URL when changed since the cached copy, or the cached copy otherwise.
+// The cached copy is used when the URL is not reachable. Returns whether the configuration differs from the cached copy.
+// The signature of the cached copy is verified on every use, the copy failing the verification being ignored.
+func loadRemoteConfig(url string) ([]byte, bool, error) {
+	cached, etag, signature := readRemoteConfigCache(url)
+	if cached != nil && len(remoteConfigPublicKeyPath) > 0 {
+		if err := checkRemoteConfigSignature(cached, signature); err != nil {
+			log.Warnf("Ignoring cached configuration: signature verification failed: %s", err)
+			cached, etag = nil, ""
+		}
+	}
+
+	data, fetched, err := fetchRemoteConfig(url, etag)
+	if err != nil {
+		if cached == nil {
+			return nil, false, err
+		}
+		log.Warnf("Cannot fetch configuration, using the cached copy: %s: %s", url, err)
+		return cached, false, nil
+	}
+	if !fetched {
+		return cached, false, nil
+	}
+	return data, !bytes.Equal(bytes.TrimSpace(data), bytes.TrimSpace(cached)), nil
+}
+
+// Fetches the configuration from the URL unless its ETag matches, and verifies its signature.
+// The verified configuration is cached. Returns whether the configuration was fetched.
+func fetchRemoteConfig(url string, etag string) ([]byte, bool, error) {
+	client := &http.Client{Timeout: remoteConfigTimeout}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(etag) > 0 {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && len(etag) > 0 {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("server responded: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, remoteConfigMaxSize+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data) > remoteConfigMaxSize {
+		return nil, false, fmt.Errorf("configuration exceeds %d bytes", remoteConfigMaxSize)
+	}
+
+	var signature []byte
+	if len(remoteConfigPublicKeyPath) > 0 {
+		signature, err = fetchRemoteConfigSignature(client, url)
+		if err == nil {
+			err = checkRemoteConfigSignature(data, signature)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("signature verification failed: %w", err)
+		}
+	}
+
+	writeRemoteConfigCache(url, data, resp.Header.Get("ETag"), signature)
+	return data, true, nil
+}
+
+// Fetches the base64-encoded Ed25519 signature of the configuration from the URL with the ".sig" suffix of the path.
+func fetchRemoteConfigSignature(client *http.Client, rawURL string) ([]byte, error) {
+	sigURL, err := remoteConfigSignatureURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(sigURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch signature: %s", resp.Status)
+	}
+
+	encoded, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode signature: %w", err)
+	}
+	return signature, nil
+}
+
+// Returns the URL of the configuration signature: the ".sig" suffix is appended to the path, keeping the query.
+func remoteConfigSignatureURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u.Path += ".sig"
+	if len(u.RawPath) > 0 {
+		u.RawPath += ".sig"
+	}
+	return u.String(), nil
+}
+
+// Verifies the configuration by the Ed25519 signature and the configured public key.
+func checkRemoteConfigSignature(data []byte, signature []byte) error {
+	publicKey, err := readRemoteConfigPublicKey(remoteConfigPublicKeyPath)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, data, signature) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// Reads the PEM-encoded Ed25519 public key.
+func readRemoteConfigPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data: %s", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an Ed25519 public key: %s", path)
+	}
+	return publicKey, nil
+}
+
+// Returns the cached remote configuration of the URL, its ETag and signature. Nil configuration is returned when not cached.
+func readRemoteConfigCache(url string) ([]byte, string, []byte) {
+	if len(remoteConfigCachePath) == 0 {
+		return nil, "", nil
+	}
+
+	data, err := os.ReadFile(remoteConfigCachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warnf("Cannot read configuration cache: %s: %s", remoteConfigCachePath, err)
+		}
+		return nil, "", nil
+	}
+	// The newline appended by writing the file is dropped, for the signature to match.
+	data = bytes.TrimSuffix(data, []byte("\n"))
+
+	// The cache of another URL is ignored.
+	meta, _ := os.ReadFile(remoteConfigCachePath + ".etag")
+	cachedURL, etag, _ := strings.Cut(strings.TrimSpace(string(meta)), "\n")
+	if cachedURL != url {
+		return nil, "", nil
+	}
+
+	// Missing signature fails the verification of the cached copy.
+	encoded, _ := os.ReadFile(remoteConfigCachePath + ".sig")
+	signature, _ := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	return data, etag, signature
+}
+
+// Caches the remote configuration with its URL, ETag and signature.
+func writeRemoteConfigCache(url string, data []byte, etag string, signature []byte) {
+	if len(remoteConfigCachePath) == 0 {
+		return
+	}
+
+	if err := writeFileAtomic(remoteConfigCachePath, data, 0o600); err != nil {
+		log.Warnf("Cannot write configuration cache: %s: %s", remoteConfigCachePath, err)
+		return
+	}
+	if err := writeFileAtomic(remoteConfigCachePath+".etag", []byte(url+"\n"+etag), 0o600); err != nil {
+		log.Warnf("Cannot write configuration cache: %s: %s", remoteConfigCachePath, err)
+	}
+	if err := writeFileAtomic(remoteConfigCachePath+".sig", []byte(base64.StdEncoding.EncodeToString(signature)), 0o600); err != nil {
+		log.Warnf("Cannot write configuration cache: %s: %s", remoteConfigCachePath, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRemoteConfig(t *testing.T) {
+	dir := t.TempDir()
+	defer func(path string) { remoteConfigCachePath = path }(remoteConfigCachePath)
+	remoteConfigCachePath = filepath.Join(dir, "config-cache.yml")
+
+	body := "prefix: w\n"
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + base64.StdEncoding.EncodeToString([]byte(body)) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetches++
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+
+	data, changed, err := loadRemoteConfig(server.URL + "/config.yml")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, body, string(data))
+
+	// Not modified, the cached copy is used.
+	data, changed, err = loadRemoteConfig(server.URL + "/config.yml")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, body, string(data[:len(body)]))
+	assert.Equal(t, 1, fetches)
+
+	body = "prefix: x\n"
+	data, changed, err = loadRemoteConfig(server.URL + "/config.yml")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, body, string(data))
+
+	// The cached copy is used when the server is not reachable, but not for another URL.
+	server.Close()
+	data, changed, err = loadRemoteConfig(server.URL + "/config.yml")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, body, string(data[:len(body)]))
+
+	_, _, err = loadRemoteConfig(server.URL + "/other.yml")
+	assert.Error(t, err)
+}
+
+func TestLoadRemoteConfigSignature(t *testing.T) {
+	dir := t.TempDir()
+	defer func(path string) { remoteConfigCachePath = path }(remoteConfigCachePath)
+	remoteConfigCachePath = filepath.Join(dir, "config-cache.yml")
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	remoteConfigPublicKeyPath = filepath.Join(dir, "key.pem")
+	defer func() { remoteConfigPublicKeyPath = "" }()
+	require.NoError(t, os.WriteFile(remoteConfigPublicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	body := []byte("prefix: w\n")
+	signature := ed25519.Sign(privateKey, body)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yml":
+			w.Write(body)
+		case "/config.yml.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString(signature) + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, _, err := loadRemoteConfig(server.URL + "/config.yml")
+	require.NoError(t, err)
+	assert.Equal(t, body, data)
+
+	// The query is kept in the signature URL.
+	data, _, err = loadRemoteConfig(server.URL + "/config.yml?host=web")
+	require.NoError(t, err)
+	assert.Equal(t, body, data)
+
+	// The cached copy is verified when the server is not reachable.
+	server.Close()
+	data, _, err = loadRemoteConfig(server.URL + "/config.yml?host=web")
+	require.NoError(t, err)
+	assert.Equal(t, body, data)
+
+	require.NoError(t, os.WriteFile(remoteConfigCachePath, []byte("prefix: x\n"), 0o600))
+	_, _, err = loadRemoteConfig(server.URL + "/config.yml?host=web")
+	assert.Error(t, err)
+}
+
+func TestFetchRemoteConfigSignature(t *testing.T) {
+	dir := t.TempDir()
+	defer func(path string) { remoteConfigCachePath = path }(remoteConfigCachePath)
+	remoteConfigCachePath = filepath.Join(dir, "config-cache.yml")
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	remoteConfigPublicKeyPath = filepath.Join(dir, "key.pem")
+	defer func() { remoteConfigPublicKeyPath = "" }()
+	require.NoError(t, os.WriteFile(remoteConfigPublicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	signature := ed25519.Sign(privateKey, []byte("prefix: w\n"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yml":
+			w.Write([]byte("prefix: x\n"))
+		case "/config.yml.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString(signature)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	_, _, err = fetchRemoteConfig(server.URL+"/config.yml", "")
+	assert.ErrorContains(t, err, "signature does not match")
+
+	_, _, err = fetchRemoteConfig(server.URL+"/unsigned.yml", "")
+	assert.Error(t, err)
+}
+
+func TestRemoteConfigSignatureURL(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"https://config.example.com/config.yml":               "https://config.example.com/config.yml.sig",
+		"https://config.example.com/config.yml?host=web&v=2":  "https://config.example.com/config.yml.sig?host=web&v=2",
+		"https://config.example.com/a%2Fb.yml?token=x#anchor": "https://config.example.com/a%2Fb.yml.sig?token=x#anchor",
+	} {
+		sigURL, err := remoteConfigSignatureURL(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sigURL)
+	}
+}
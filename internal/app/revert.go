@@ -0,0 +1,190 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// Preserves the name assigned by Docker as the alternative name of the renamed link,
+// so it can be restored even when the state is lost.
+func preserveLinkName(link netlink.Link) {
+	originalName := link.Attrs().Name
+	if !strings.HasPrefix(originalName, linkops.DockerLinkPrefix) || slices.Contains(link.Attrs().AltNames, originalName) {
+		return
+	}
+
+	// Alternative names are supported since Linux 5.5.
+	if err := nlLinkAddAltName(link, originalName); err != nil {
+		log.WithFields(log.Fields{logFieldIndex: link.Attrs().Index, logFieldLink: originalName}).
+			Debugf("netlink.LinkAddAltName failed: %s : %s", originalName, err)
+	}
+}
+
+// Returns whether the tracked container is referred by the name or the ID (or its prefix).
+func matchContainer(cs ContainerState, nameOrID string) bool {
+	if strings.TrimPrefix(cs.Name, "/") == strings.TrimPrefix(nameOrID, "/") {
+		return true
+	}
+	return len(nameOrID) > 0 && strings.HasPrefix(cs.ID, nameOrID)
+}
+
+// Returns the links having the name preserved, which are not tracked in the state.
+func untrackedPreservedLinks(indexes []int) []LinkState {
+	var links []LinkState
+	for _, index := range indexes {
+		if state.TracksLink(index) {
+			continue
+		}
+
+		link, err := nlLinkByIndex(index)
+		if err != nil {
+			log.Errorf("netlink.LinkByIndex failed: %s", err)
+			continue
+		}
+
+		if preservedName := linkops.PreservedName(link); len(preservedName) > 0 {
+			links = append(links, LinkState{
+				Index:        index,
+				OriginalName: preservedName,
+				Name:         link.Attrs().Name,
+			})
+		}
+	}
+	return links
+}
+
+// Renames host links back to the original names, recorded in the state file or preserved as the alternative names.
+// When containers are specified by names or IDs, only their links are reverted.
+func revertLinks(ctx context.Context, rt ContainerRuntime, containers []string) error {
+	ps, err := loadPersistentState(config.StateFile)
+	if err != nil {
+		return fmt.Errorf("cannot read state file: %w", err)
+	}
+	state.Restore(ps.Containers)
+
+	// Links to be reverted, grouped by container. Untracked links of unknown containers are grouped with empty ID.
+	var targets []ContainerMapping
+	// Tracked containers among the targets, released once reverted.
+	var tracked []ContainerMapping
+	var failed []string
+
+	if len(containers) == 0 {
+		for _, cs := range state.Containers() {
+			targets = append(targets, ContainerMapping{ID: cs.ID, Name: cs.Name, Links: state.Links(cs.ID)})
+			state.RemoveContainer(cs.ID)
+		}
+		tracked = slices.Clone(targets)
+
+		hostLinks, err := nlLinkList()
+		if err != nil {
+			return fmt.Errorf("netlink.LinkList failed: %w", err)
+		}
+
+		var indexes []int
+		for _, link := range hostLinks {
+			if link.Type() == "veth" {
+				indexes = append(indexes, link.Attrs().Index)
+			}
+		}
+		targets = append(targets, ContainerMapping{Links: untrackedPreservedLinks(indexes)})
+	}
+
+	for _, nameOrID := range containers {
+		index := slices.IndexFunc(state.Containers(), func(cs ContainerState) bool {
+			return matchContainer(cs, nameOrID)
+		})
+		if index != -1 {
+			cs := state.Containers()[index]
+			targets = append(targets, ContainerMapping{ID: cs.ID, Name: cs.Name, Links: state.Links(cs.ID)})
+			tracked = append(tracked, targets[len(targets)-1])
+			state.RemoveContainer(cs.ID)
+			continue
+		}
+
+		// Not tracked, the original names may be preserved on the links of the running container.
+		inspect, err := inspectContainer(ctx, rt, nameOrID)
+		if err != nil {
+			log.Errorf("Container is not tracked, and cannot be inspected: %s: %s", nameOrID, err)
+			failed = append(failed, nameOrID)
+			continue
+		}
+
+		mappings, err := containerLinkMappings(rt, inspect)
+		if err != nil {
+			containerLogger(inspect.ID, inspect.Name).Errorf("Cannot list links for container: %s %s: %s", inspect.Name, inspect.ID, err)
+			failed = append(failed, nameOrID)
+			continue
+		}
+
+		var indexes []int
+		for _, mapping := range mappings {
+			indexes = append(indexes, mapping.Index)
+		}
+		targets = append(targets, ContainerMapping{ID: inspect.ID, Name: inspect.Name, Links: untrackedPreservedLinks(indexes)})
+	}
+
+	for _, target := range targets {
+		for _, link := range target.Links {
+			restoreLinkName(target.ID, target.Name, link)
+		}
+	}
+	for _, target := range tracked {
+		for _, link := range target.Links {
+			recordRelease(link)
+		}
+	}
+
+	if !dryRun {
+		ps.Containers = state.Mappings()
+		if err := savePersistentState(config.StateFile, ps); err != nil {
+			log.Errorf("Cannot write state file: %s", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot revert containers: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Renames the host links of the tracked container back to the original names, and stops tracking the container.
+func revertContainerLinks(containerID string) {
+	cs, ok := state.RemoveContainer(containerID)
+	if !ok {
+		return
+	}
+
+	for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+		trackedLink := *cs.Links[index]
+		restoreLinkName(cs.ID, cs.Name, trackedLink)
+		recordRelease(trackedLink)
+		notify(NotificationMappingRemoved, cs.ID, cs.Name, trackedLink)
+	}
+}
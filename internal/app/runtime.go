@@ -0,0 +1,152 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/dockerwatch"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// Container runtime running the containers whose links are renamed.
+// The Docker API types are the common vocabulary: other runtimes translate their containers and events into them.
+// Implementations must be safe for concurrent use.
+type ContainerRuntime interface {
+	// Returns the running containers matching the filters.
+	ListContainers(ctx context.Context, filterArgs filters.Args) ([]container.Summary, error)
+	// Returns the inspect record of the container by its ID or name.
+	Inspect(ctx context.Context, containerID string) (container.InspectResponse, error)
+	// Streams the events matching the filters, replaying the past ones since the time unless it is zero.
+	// The error channel receives the failure of the stream, which ends it.
+	WatchEvents(ctx context.Context, filterArgs filters.Args, since time.Time) (<-chan events.Message, <-chan error)
+	// Returns the path of the network namespace of the inspected container.
+	// Fails with the dockerwatch errors for the containers without a network namespace of their own.
+	ResolveNetns(inspect container.InspectResponse) (string, error)
+	// Checks that the runtime API responds.
+	Ping(ctx context.Context) error
+	// Returns the versions of the runtime and its API, for diagnostics.
+	Version(ctx context.Context) (string, error)
+	// Returns whether the error means that the container does not exist.
+	IsNotFound(err error) bool
+	Close() error
+}
+
+// Limits the rate of listing and inspecting the containers, shared by all workers.
+// Replaced when the limit changes, starting with the full burst. No limit when nil.
+var dockerRateLimiter atomic.Pointer[rate.Limiter]
+
+// Applies the rate limit of the Docker API requests of the configuration.
+func applyDockerRateLimit() {
+	limit := rate.Inf
+	if config.DockerRateLimit > 0 {
+		limit = rate.Limit(config.DockerRateLimit)
+	}
+
+	if limiter := dockerRateLimiter.Load(); limiter != nil && limiter.Limit() == limit && limiter.Burst() == config.DockerRateBurst {
+		return
+	}
+	dockerRateLimiter.Store(rate.NewLimiter(limit, config.DockerRateBurst))
+}
+
+// Waits until the Docker API request is allowed by the rate limit, or the context is done.
+func waitDockerRateLimit(ctx context.Context) error {
+	limiter := dockerRateLimiter.Load()
+	if limiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait failed: %w", err)
+	}
+	if waited := time.Since(start); waited > time.Millisecond {
+		log.Debugf("Docker API request delayed by the rate limit: %s", waited)
+	}
+	return nil
+}
+
+// Docker Engine API client configured from the environment, e.g. DOCKER_HOST.
+type DockerRuntime struct {
+	cli *client.Client
+}
+
+// Makes the container runtime client: of the CRI endpoint of the configuration when set, or of Docker.
+func newContainerRuntime() (ContainerRuntime, error) {
+	if len(config.CRIEndpoint) > 0 {
+		return newCRIRuntime(config.CRIEndpoint)
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+	return &DockerRuntime{cli: cli}, nil
+}
+
+func (r *DockerRuntime) ListContainers(ctx context.Context, filterArgs filters.Args) ([]container.Summary, error) {
+	return r.cli.ContainerList(ctx, container.ListOptions{Filters: filterArgs})
+}
+
+func (r *DockerRuntime) Inspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	return r.cli.ContainerInspect(ctx, containerID)
+}
+
+func (r *DockerRuntime) WatchEvents(ctx context.Context, filterArgs filters.Args, since time.Time) (<-chan events.Message, <-chan error) {
+	options := events.ListOptions{Filters: filterArgs}
+	if !since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+	return r.cli.Events(ctx, options)
+}
+
+func (r *DockerRuntime) ResolveNetns(inspect container.InspectResponse) (string, error) {
+	return dockerwatch.NetworkNamespace(inspect)
+}
+
+func (r *DockerRuntime) Ping(ctx context.Context) error {
+	_, err := r.cli.Ping(ctx)
+	return err
+}
+
+func (r *DockerRuntime) Version(ctx context.Context) (string, error) {
+	version, err := r.cli.ServerVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Docker %s, API %s (minimum %s), client API %s",
+		version.Version, version.APIVersion, version.MinAPIVersion, r.cli.ClientVersion()), nil
+}
+
+func (r *DockerRuntime) IsNotFound(err error) bool {
+	return client.IsErrNotFound(err)
+}
+
+func (r *DockerRuntime) Close() error {
+	return r.cli.Close()
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errFakeNotFound = errors.New("no such container")
+
+// Container runtime serving the containers and the events from memory.
+type fakeRuntime struct {
+	containers map[string]container.InspectResponse
+	events     chan events.Message
+	errs       chan error
+	pingErr    error
+	// Guards inspects, the containers are inspected concurrently.
+	mu       sync.Mutex
+	inspects int
+	// Replay start of the last events subscription.
+	since time.Time
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{
+		containers: map[string]container.InspectResponse{},
+		events:     make(chan events.Message, 10),
+		errs:       make(chan error, 1),
+	}
+}
+
+func (r *fakeRuntime) ListContainers(ctx context.Context, filterArgs filters.Args) ([]container.Summary, error) {
+	var summaries []container.Summary
+	for id := range r.containers {
+		summaries = append(summaries, container.Summary{ID: id})
+	}
+	return summaries, nil
+}
+
+func (r *fakeRuntime) Inspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	r.mu.Lock()
+	r.inspects++
+	r.mu.Unlock()
+
+	inspect, ok := r.containers[containerID]
+	if !ok {
+		return inspect, errFakeNotFound
+	}
+	return inspect, nil
+}
+
+func (r *fakeRuntime) WatchEvents(ctx context.Context, filterArgs filters.Args, since time.Time) (<-chan events.Message, <-chan error) {
+	r.since = since
+	return r.events, r.errs
+}
+
+func (r *fakeRuntime) ResolveNetns(inspect container.InspectResponse) (string, error) {
+	return (&DockerRuntime{}).ResolveNetns(inspect)
+}
+
+func (r *fakeRuntime) Ping(ctx context.Context) error {
+	return r.pingErr
+}
+
+func (r *fakeRuntime) Version(ctx context.Context) (string, error) {
+	return "fake", nil
+}
+
+func (r *fakeRuntime) IsNotFound(err error) bool {
+	return errors.Is(err, errFakeNotFound)
+}
+
+func (r *fakeRuntime) Close() error {
+	return nil
+}
+
+func TestInspectContainer(t *testing.T) {
+	rt := newFakeRuntime()
+	rt.containers["1234"] = container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", Name: "/web"}}
+
+	inspect, err := inspectContainer(context.Background(), rt, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, "/web", inspect.Name)
+
+	// Missing container is not retried.
+	rt.inspects = 0
+	_, err = inspectContainer(context.Background(), rt, "4567")
+	assert.ErrorIs(t, err, errFakeNotFound)
+	assert.Equal(t, 1, rt.inspects)
+}
+
+func TestInspectRunningContainers(t *testing.T) {
+	rt := newFakeRuntime()
+	rt.containers["1234"] = container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", Name: "/web"}}
+	rt.containers["4567"] = container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: "4567", Name: "/db"}}
+
+	inspects := inspectRunningContainers(context.Background(), rt, filters.NewArgs())
+	require.Len(t, inspects, 2)
+	assert.Equal(t, "/db", inspects[0].Name)
+	assert.Equal(t, "/web", inspects[1].Name)
+}
+
+func TestInspectRunningContainersConcurrently(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.StartupWorkers = 3
+
+	rt := newFakeRuntime()
+	for i := range 50 {
+		id := fmt.Sprintf("%04d", i)
+		rt.containers[id] = container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: id, Name: "/c" + id}}
+	}
+
+	inspects := inspectRunningContainers(context.Background(), rt, filters.NewArgs())
+	require.Len(t, inspects, 50)
+	assert.Equal(t, 50, rt.inspects)
+	for i, inspect := range inspects {
+		assert.Equal(t, fmt.Sprintf("/c%04d", i), inspect.Name)
+	}
+}
+
+func TestForEachRunningContainer(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.StartupWorkers = 3
+
+	rt := newFakeRuntime()
+	for i := range 50 {
+		id := fmt.Sprintf("%04d", i)
+		rt.containers[id] = container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: id, Name: "/c" + id}}
+	}
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	forEachRunningContainer(context.Background(), rt, filters.NewArgs(), func(inspect container.InspectResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.False(t, seen[inspect.ID], inspect.ID)
+		seen[inspect.ID] = true
+	})
+	assert.Len(t, seen, 50)
+}
+
+func TestEventLoopSubscribe(t *testing.T) {
+	rt := newFakeRuntime()
+	l := &EventLoop{ctx: context.Background(), runtime: rt, reconnectDelay: reconnectInitialDelay, eventQueue: newEventQueue(1, EventQueuePolicyDefer)}
+
+	since := time.Unix(1700000000, 0)
+	l.subscribe(since)
+	assert.Equal(t, since, rt.since)
+	require.NotNil(t, l.errs)
+
+	rt.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: events.Actor{ID: "1234"}}
+	assert.Equal(t, "1234", (<-l.eventQueue.Events()).Actor.ID)
+
+	// Unresponsive runtime is not subscribed to, the subscription is retried later.
+	rt.pingErr = errors.New("timeout")
+	l.subscribe(since)
+	assert.Nil(t, l.errs)
+	require.NotNil(t, l.reconnectTimer)
+	l.reconnectTimer.Stop()
+}
+
+func TestApplyDockerRateLimit(t *testing.T) {
+	defer func(c Config) {
+		config = c
+		applyDockerRateLimit()
+	}(config)
+	config = defaultConfig()
+
+	applyDockerRateLimit()
+	for range 100 {
+		assert.True(t, dockerRateLimiter.Load().Allow())
+	}
+
+	config.DockerRateLimit = 0.001
+	config.DockerRateBurst = 2
+	applyDockerRateLimit()
+	assert.True(t, dockerRateLimiter.Load().Allow())
+	assert.True(t, dockerRateLimiter.Load().Allow())
+	assert.False(t, dockerRateLimiter.Load().Allow())
+
+	// The wait is abandoned when the context is done first.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, waitDockerRateLimit(ctx))
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/linkops"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// ID and name of the container the throwaway veth pair of the self-test is named after.
+const (
+	selftestContainerID   = "selftest"
+	selftestContainerName = "/selftest"
+)
+
+// Hint on the failures caused by insufficient privileges.
+const selftestPrivilegesHint = "Run the program as root, or grant CAP_NET_ADMIN and CAP_SYS_ADMIN"
+
+// Throwaway network namespace holding the container end of the veth pair, with its host end in the namespace of the program.
+type selftestSandbox struct {
+	ns netns.NsHandle
+	// Path of the namespace, valid while the handle is open.
+	path      string
+	hostIndex int
+	hostName  string
+}
+
+// Creates the anonymous network namespace with the container end of a new veth pair named eth0.
+func newSelftestSandbox() (*selftestSandbox, error) {
+	s := &selftestSandbox{hostName: fmt.Sprintf("vethdvn%x", os.Getpid())}
+
+	// Creating the namespace switches the thread into it.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	origin, err := netns.Get()
+	if err != nil {
+		return nil, fmt.Errorf("netns.Get failed: %w", err)
+	}
+	defer origin.Close()
+
+	s.ns, err = netns.New()
+	if err != nil {
+		return nil, fmt.Errorf("netns.New failed: %w", err)
+	}
+	if err := netns.Set(origin); err != nil {
+		s.ns.Close()
+		return nil, fmt.Errorf("netns.Set failed: %w", err)
+	}
+	s.path = fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(s.ns))
+
+	peerName := fmt.Sprintf("dvnst%x", os.Getpid())
+	err = netlink.LinkAdd(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: s.hostName}, PeerName: peerName})
+	if err != nil {
+		s.ns.Close()
+		return nil, fmt.Errorf("netlink.LinkAdd failed: %w", err)
+	}
+
+	err = func() error {
+		host, err := netlink.LinkByName(s.hostName)
+		if err != nil {
+			return fmt.Errorf("netlink.LinkByName failed: %w", err)
+		}
+		s.hostIndex = host.Attrs().Index
+
+		peer, err := netlink.LinkByName(peerName)
+		if err != nil {
+			return fmt.Errorf("netlink.LinkByName failed: %w", err)
+		}
+		if err := netlink.LinkSetNsFd(peer, int(s.ns)); err != nil {
+			return fmt.Errorf("netlink.LinkSetNsFd failed: %w", err)
+		}
+
+		handle, err := netlink.NewHandleAt(s.ns)
+		if err != nil {
+			return fmt.Errorf("netlink.NewHandleAt failed: %w", err)
+		}
+		defer handle.Close()
+
+		if peer, err = handle.LinkByName(peerName); err != nil {
+			return fmt.Errorf("netlink.LinkByName failed: %w", err)
+		}
+		if err := handle.LinkSetName(peer, "eth0"); err != nil {
+			return fmt.Errorf("netlink.LinkSetName failed: %w", err)
+		}
+		return nil
+	}()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Removes the veth pair, and the namespace along with it.
+func (s *selftestSandbox) Close() error {
+	defer s.ns.Close()
+
+	link, err := netlink.LinkByName(s.hostName)
+	if err != nil && s.hostIndex > 0 {
+		link, err = netlink.LinkByIndex(s.hostIndex)
+	}
+	if err != nil {
+		return fmt.Errorf("netlink.LinkByName failed: %w", err)
+	}
+	if err := netlink.LinkDel(link); err != nil {
+		return fmt.Errorf("netlink.LinkDel failed: %w", err)
+	}
+	return nil
+}
+
+// Swaps the configuration, the tracked links and the notification sinks for the duration of the self-test,
+// so that renaming the throwaway veth pair is neither paused, nor run through the hooks, nor notified,
+// nor written to the audit log and the timeline database. Returns the function restoring them.
+func isolateSelftest() func() {
+	prevConfig, prevState, prevSinks, prevDryRun := config, state, notificationSinks, dryRun
+	config.PauseFile = ""
+	config.AuditLogFile = ""
+	config.TimelineDB = ""
+	config.PreRenameHook = ""
+	config.PostRenameHook = ""
+	state = newState()
+	notificationSinks = nil
+	dryRun = false
+
+	return func() {
+		config, state, notificationSinks, dryRun = prevConfig, prevState, prevSinks, prevDryRun
+	}
+}
+
+// Runs the renaming pipeline against the throwaway veth pair: entering the namespace, renaming the host link,
+// and restoring its original name, as done for the containers. Neither Docker nor the tracked links are involved.
+func (d *Doctor) selftest() {
+	defer isolateSelftest()()
+
+	sandbox, err := newSelftestSandbox()
+	if err != nil {
+		d.fail(selftestPrivilegesHint, "Throwaway veth pair cannot be created: %s", err)
+		return
+	}
+	defer func() {
+		if err := sandbox.Close(); err != nil {
+			d.fail("Remove the link manually with ip link del", "Throwaway veth pair cannot be removed: %s: %s", sandbox.hostName, err)
+		} else {
+			d.ok("Throwaway veth pair is removed")
+		}
+	}()
+	d.ok("Throwaway veth pair is created: %s (ifindex %d)", sandbox.hostName, sandbox.hostIndex)
+
+	containerLinks, err := nlNamespaceVEths(sandbox.path)
+	if err != nil {
+		d.fail(selftestPrivilegesHint, "Container links cannot be listed within the network namespace: %s", err)
+		return
+	}
+	index := slices.IndexFunc(containerLinks, func(l linkops.VEth) bool { return l.ParentIndex == sandbox.hostIndex })
+	if index < 0 {
+		d.fail("", "Container link is not found within the network namespace: %v", containerLinks)
+		return
+	}
+	containerLink := containerLinks[index]
+	d.ok("Container link is found within the network namespace: %s, the peer of ifindex %d", containerLink.Name, containerLink.ParentIndex)
+
+	link, err := nlLinkByIndex(sandbox.hostIndex)
+	if err != nil {
+		d.fail("", "Host link cannot be found: %s", err)
+		return
+	}
+	outcome, err := updateLinkNameThrottled(context.Background(), link, selftestContainerID, selftestContainerName, containerLink.Name, LinkLabels{})
+	if outcome != RenameDone {
+		if err == nil {
+			err = errors.New("rename is skipped")
+		}
+		d.fail(selftestPrivilegesHint, "Host link cannot be renamed: %s %s: %s", selftestContainerName, containerLink.Name, err)
+		return
+	}
+	_, tracked, ok := state.FindLink(sandbox.hostIndex)
+	if !ok {
+		d.fail("", "Host link is not tracked after renaming: %s", sandbox.hostName)
+		return
+	}
+	d.ok("Host link is renamed: %s => %s", sandbox.hostName, tracked.Name)
+
+	if link, err = nlLinkByIndex(sandbox.hostIndex); err != nil {
+		d.fail("", "Host link cannot be found: %s", err)
+		return
+	}
+	if slices.Contains(link.Attrs().AltNames, sandbox.hostName) {
+		d.ok("Original name is preserved as the alternative name: %s", sandbox.hostName)
+	} else {
+		d.warn("Alternative names are supported since Linux 5.5",
+			"Original name cannot be preserved, it is not restored after the daemon restarts: %s", sandbox.hostName)
+	}
+
+	restoreLinkName(selftestContainerID, selftestContainerName, tracked)
+	if link, err = nlLinkByIndex(sandbox.hostIndex); err != nil {
+		d.fail("", "Host link cannot be found: %s", err)
+		return
+	}
+	if link.Attrs().Name != sandbox.hostName {
+		d.fail("", "Original name of the host link is not restored: %s => %s", tracked.Name, sandbox.hostName)
+		return
+	}
+	d.ok("Original name of the host link is restored: %s => %s", tracked.Name, sandbox.hostName)
+}
+
+// Runs the self-test, and prints the results.
+func runSelftest(w io.Writer) error {
+	d := &Doctor{}
+	d.checkCapabilities()
+	d.selftest()
+	return d.report(w)
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Host link renamed by the program.
+type LinkState struct {
+	// Index of the link at the host.
+	Index int `json:"ifindex"`
+	// Name of the peer link within the container.
+	ContainerLink string `json:"container_link"`
+	// Name of the host link before renaming.
+	OriginalName string `json:"original_name"`
+	// Name assigned to the host link.
+	Name string `json:"name"`
+	LinkLabels
+}
+
+// Identity of the container link, exposed e.g. in metrics.
+type LinkLabels struct {
+	// Image of the container.
+	Image string `json:"image,omitempty"`
+	// Network the container link is connected to.
+	Network string `json:"network,omitempty"`
+	// Kubernetes pod of the container, and its namespace.
+	Pod          string `json:"pod,omitempty"`
+	PodNamespace string `json:"pod_namespace,omitempty"`
+}
+
+// Container owning the renamed host links.
+type ContainerState struct {
+	ID   string
+	Name string
+	// Renamed host links by index.
+	Links map[int]*LinkState
+}
+
+// Container and its renamed host links, sorted by index.
+type ContainerMapping struct {
+	ID    string      `json:"id"`
+	Name  string      `json:"name"`
+	Links []LinkState `json:"links"`
+}
+
+// Mapping of containers to the renamed host links.
+type State struct {
+	mu         sync.Mutex
+	containers map[string]*ContainerState
+	// Incremented on each change.
+	version uint64
+}
+
+var state = newState()
+
+func newState() *State {
+	return &State{containers: make(map[string]*ContainerState)}
+}
+
+// Records the host link mapping for the container.
+// The original name of a link which is already tracked is preserved.
+func (s *State) SetLink(containerID string, containerName string, link LinkState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.containers[containerID]
+	if !ok {
+		cs = &ContainerState{ID: containerID, Links: make(map[int]*LinkState)}
+		s.containers[containerID] = cs
+	}
+	cs.Name = containerName
+
+	if prev, ok := cs.Links[link.Index]; ok {
+		if len(prev.OriginalName) > 0 {
+			link.OriginalName = prev.OriginalName
+		}
+		if *prev == link {
+			return
+		}
+	}
+	cs.Links[link.Index] = &link
+	s.version++
+}
+
+// Returns copies of the host links tracked for the container, sorted by index.
+func (s *State) Links(containerID string) []LinkState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.containers[containerID]
+	if !ok {
+		return nil
+	}
+
+	links := make([]LinkState, 0, len(cs.Links))
+	for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+		links = append(links, *cs.Links[index])
+	}
+	return links
+}
+
+// Stops tracking the host link of the container.
+// The container is dropped once it has no tracked links left.
+func (s *State) RemoveLink(containerID string, index int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.containers[containerID]
+	if !ok {
+		return
+	}
+
+	if _, ok := cs.Links[index]; !ok {
+		return
+	}
+
+	delete(cs.Links, index)
+	if len(cs.Links) == 0 {
+		delete(s.containers, containerID)
+	}
+	s.version++
+}
+
+// Stops tracking the container, and returns its last known state.
+func (s *State) RemoveContainer(containerID string) (ContainerState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.containers[containerID]
+	if !ok {
+		return ContainerState{}, false
+	}
+
+	delete(s.containers, containerID)
+	s.version++
+	return *cs, true
+}
+
+// Returns whether the host link is tracked for any container.
+func (s *State) TracksLink(index int) bool {
+	_, _, ok := s.FindLink(index)
+	return ok
+}
+
+// Returns the container tracking the host link, and a copy of the link.
+func (s *State) FindLink(index int) (ContainerState, LinkState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, cs := range s.containers {
+		if link, ok := cs.Links[index]; ok {
+			return ContainerState{ID: cs.ID, Name: cs.Name}, *link, true
+		}
+	}
+	return ContainerState{}, LinkState{}, false
+}
+
+// Returns copies of all tracked containers, sorted by name.
+func (s *State) Containers() []ContainerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	containers := make([]ContainerState, 0, len(s.containers))
+	for _, cs := range s.containers {
+		c := *cs
+		c.Links = make(map[int]*LinkState, len(cs.Links))
+		for index, link := range cs.Links {
+			l := *link
+			c.Links[index] = &l
+		}
+		containers = append(containers, c)
+	}
+
+	slices.SortFunc(containers, func(a, b ContainerState) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return containers
+}
+
+// Returns the number of changes made to the state.
+func (s *State) Version() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.version
+}
+
+// Returns all tracked containers with their links, sorted by name.
+func (s *State) Mappings() []ContainerMapping {
+	containers := s.Containers()
+	mappings := make([]ContainerMapping, 0, len(containers))
+	for _, cs := range containers {
+		mapping := ContainerMapping{ID: cs.ID, Name: cs.Name}
+		for _, index := range slices.Sorted(maps.Keys(cs.Links)) {
+			mapping.Links = append(mapping.Links, *cs.Links[index])
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+// Tracks the mappings, e.g. preserved by a previous program run.
+func (s *State) Restore(mappings []ContainerMapping) {
+	for _, mapping := range mappings {
+		for _, link := range mapping.Links {
+			s.SetLink(mapping.ID, mapping.Name, link)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateLinks(t *testing.T) {
+	s := newState()
+
+	s.SetLink("c1", "/web", LinkState{Index: 12, ContainerLink: "eth1", OriginalName: "veth1", Name: "vweb1"})
+	s.SetLink("c1", "/web", LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "veth0", Name: "vweb0"})
+	// Original name of the tracked link is preserved.
+	s.SetLink("c1", "/web", LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "vweb0", Name: "vweb0"})
+
+	assert.Equal(t, []LinkState{
+		{Index: 10, ContainerLink: "eth0", OriginalName: "veth0", Name: "vweb0"},
+		{Index: 12, ContainerLink: "eth1", OriginalName: "veth1", Name: "vweb1"},
+	}, s.Links("c1"))
+
+	version := s.Version()
+	s.SetLink("c1", "/web", LinkState{Index: 10, ContainerLink: "eth0", OriginalName: "vweb0", Name: "vweb0"})
+	assert.Equal(t, version, s.Version())
+
+	restored := newState()
+	restored.Restore(s.Mappings())
+	assert.Equal(t, s.Links("c1"), restored.Links("c1"))
+
+	s.RemoveLink("c1", 10)
+	assert.Len(t, s.Links("c1"), 1)
+	assert.Greater(t, s.Version(), version)
+
+	s.RemoveLink("c1", 12)
+	assert.Empty(t, s.Links("c1"))
+	assert.Empty(t, s.containers)
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Types of the stream events, in addition to the notification types.
+const (
+	StreamEventRename        = "rename"
+	StreamEventRenameFailed  = "rename_failed"
+	StreamEventRestore       = "restore"
+	StreamEventRestoreFailed = "restore_failed"
+	StreamEventSkip          = "skip"
+)
+
+const (
+	// Number of events queued for a stream subscriber before new ones are dropped.
+	streamQueueSize = 256
+	// Interval of the comments keeping idle streams open through proxies.
+	streamKeepAliveInterval = 15 * time.Second
+)
+
+// Event published to the stream subscribers in real time.
+type StreamEvent struct {
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	ContainerLink string    `json:"container_link,omitempty"`
+	Index         int       `json:"ifindex,omitempty"`
+	OldName       string    `json:"old_name,omitempty"`
+	NewName       string    `json:"new_name,omitempty"`
+	// Reason of skipping.
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// Distributes the events to the subscribers.
+type EventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan StreamEvent]struct{}
+}
+
+var eventBroker = newEventBroker()
+
+func newEventBroker() *EventBroker {
+	return &EventBroker{subscribers: make(map[chan StreamEvent]struct{})}
+}
+
+// Returns the channel receiving the events published from now on. The channel is released by Unsubscribe.
+func (b *EventBroker) Subscribe() chan StreamEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan StreamEvent, streamQueueSize)
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (b *EventBroker) Unsubscribe(ch chan StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, ch)
+}
+
+// Sends the event to all subscribers. The event is dropped for the subscribers not keeping up.
+func (b *EventBroker) Publish(e StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Counts the skipped container or link by reason, and publishes the skip event.
+func countSkip(reason string, containerID string, containerName string, containerLink string) {
+	skipCounter.Inc(reason)
+	eventBroker.Publish(StreamEvent{
+		Type:          StreamEventSkip,
+		Time:          time.Now(),
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		ContainerLink: containerLink,
+		Reason:        reason,
+	})
+}
+
+// Returns the stream event of the rename operation.
+func renameStreamEvent(record RenameRecord) StreamEvent {
+	eventType := StreamEventRename
+	if record.Operation == RenameRestore {
+		eventType = StreamEventRestore
+	}
+	if len(record.Error) > 0 {
+		eventType += "_failed"
+	}
+
+	return StreamEvent{
+		Type:          eventType,
+		Time:          record.Time,
+		ContainerID:   record.ContainerID,
+		ContainerName: record.ContainerName,
+		ContainerLink: record.ContainerLink,
+		Index:         record.Index,
+		OldName:       record.OldName,
+		NewName:       record.NewName,
+		Error:         record.Error,
+		DryRun:        record.DryRun,
+	}
+}
+
+// Returns the stream event of the mapping notification.
+func notificationStreamEvent(n Notification) StreamEvent {
+	return StreamEvent{
+		Type:          n.Type,
+		Time:          n.Time,
+		ContainerID:   n.ContainerID,
+		ContainerName: n.ContainerName,
+		ContainerLink: n.ContainerLink,
+		Index:         n.Index,
+		OldName:       n.OriginalName,
+		NewName:       n.Name,
+	}
+}
+
+// Streams the events as server-sent events, until the client disconnects or the daemon stops.
+func eventStreamHandler(l *EventLoop) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events := eventBroker.Subscribe()
+		defer eventBroker.Unsubscribe(events)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(streamKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case e := <-events:
+				data, err := json.Marshal(e)
+				if err != nil {
+					log.Errorf("json.Marshal to bytes failed: %s", err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+					return
+				}
+
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+
+			case <-r.Context().Done():
+				return
+
+			case <-l.ctx.Done():
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
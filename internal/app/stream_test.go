@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(eventStreamHandler(&EventLoop{ctx: ctx}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The subscription is made before the response headers are sent.
+	countSkip(SkipHostNetwork, "0123", "/web", "")
+	eventBroker.Publish(renameStreamEvent(RenameRecord{
+		Time: time.Now(), Operation: RenameApply, ContainerName: "/db", ContainerLink: "eth0", Index: 12,
+		OldName: "veth2", NewName: "vdb0", Error: errors.New("file exists").Error(),
+	}))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, StreamEvent) {
+		var eventType string
+		var e StreamEvent
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				return eventType, e
+			}
+			if value, ok := strings.CutPrefix(line, "event: "); ok {
+				eventType = value
+			} else if value, ok := strings.CutPrefix(line, "data: "); ok {
+				require.NoError(t, json.Unmarshal([]byte(value), &e))
+			}
+		}
+	}
+
+	eventType, e := readEvent()
+	assert.Equal(t, StreamEventSkip, eventType)
+	assert.Equal(t, SkipHostNetwork, e.Reason)
+	assert.Equal(t, "/web", e.ContainerName)
+
+	eventType, e = readEvent()
+	assert.Equal(t, StreamEventRenameFailed, eventType)
+	assert.Equal(t, "vdb0", e.NewName)
+	assert.Equal(t, "file exists", e.Error)
+}
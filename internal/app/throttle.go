@@ -0,0 +1,95 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// Interval of checking whether renaming is paused, while waiting for the rename rate limit.
+const renameThrottleCheckInterval = 100 * time.Millisecond
+
+// Limits the rate of renames, shared by all workers. Replaced when the limit changes. No limit when nil.
+var renameRateLimiter atomic.Pointer[rate.Limiter]
+
+// Applies max_renames_per_second of the configuration.
+func applyRenameRateLimit() {
+	if config.MaxRenamesPerSecond <= 0 {
+		renameRateLimiter.Store(nil)
+		return
+	}
+
+	limit := rate.Limit(config.MaxRenamesPerSecond)
+	if limiter := renameRateLimiter.Load(); limiter != nil && limiter.Limit() == limit {
+		return
+	}
+	renameRateLimiter.Store(rate.NewLimiter(limit, 1))
+}
+
+// Takes the rename from the rate limit when it is allowed now. Otherwise the caller is expected to wait
+// for the rate limit by waitRenameRateLimit, and to try the rename again.
+func allowRename() bool {
+	limiter := renameRateLimiter.Load()
+	return limiter == nil || limiter.Allow()
+}
+
+// Waits until a rename is allowed by the rate limit, so that a misconfiguration applied to a large host
+// rolls out gradually. The rename is not taken from the limit, see allowRename. Pausing renaming meanwhile
+// stops the wait; the caller is expected to skip the rename then. Fails when the context is done.
+// Must be called without holding the configuration lock, not to block the reload.
+func waitRenameRateLimit(ctx context.Context) error {
+	limiter := renameRateLimiter.Load()
+	if limiter == nil || isPaused() {
+		return nil
+	}
+
+	tokens := limiter.Tokens()
+	if tokens >= 1 {
+		return nil
+	}
+	delay := time.Duration((1 - tokens) / float64(limiter.Limit()) * float64(time.Second))
+	log.Debugf("Rename delayed by the rate limit: %s", delay.Round(time.Millisecond))
+
+	deadline := time.Now().Add(delay)
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(min(wait, renameThrottleCheckInterval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("rename rate limit wait failed: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		if isPaused() {
+			return nil
+		}
+	}
+}
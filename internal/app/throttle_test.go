@@ -0,0 +1,98 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitRenameRateLimit(t *testing.T) {
+	prev := config
+	t.Cleanup(func() {
+		config = prev
+		applyRenameRateLimit()
+	})
+	config.PauseFile = filepath.Join(t.TempDir(), "paused")
+
+	// No limit by default.
+	applyRenameRateLimit()
+	assert.Nil(t, renameRateLimiter.Load())
+	require.NoError(t, waitRenameRateLimit(context.Background()))
+
+	config.MaxRenamesPerSecond = 1
+	applyRenameRateLimit()
+	start := time.Now()
+	require.NoError(t, waitRenameRateLimit(context.Background()))
+	assert.Less(t, time.Since(start), renameThrottleCheckInterval)
+
+	// The wait does not take the rename.
+	assert.True(t, allowRename())
+	assert.False(t, allowRename())
+
+	// The wait is aborted by the pause.
+	require.NoError(t, os.WriteFile(config.PauseFile, nil, 0o644))
+	start = time.Now()
+	require.NoError(t, waitRenameRateLimit(context.Background()))
+	assert.Less(t, time.Since(start), time.Second/2)
+	require.NoError(t, os.Remove(config.PauseFile))
+
+	// And by the context.
+	ctx, cancel := context.WithTimeout(context.Background(), renameThrottleCheckInterval/2)
+	defer cancel()
+	assert.ErrorIs(t, waitRenameRateLimit(ctx), context.DeadlineExceeded)
+}
+
+func TestThrottledRenameUsesReloadedConfig(t *testing.T) {
+	defer func(c Config) { config = c }(config)
+	t.Cleanup(applyRenameRateLimit)
+	config = defaultConfig()
+	config.PauseFile = ""
+	config.ContainerLinkPrefixes = []string{"eth"}
+	config.MaxRenamesPerSecond = 2
+	applyRenameRateLimit()
+	require.True(t, allowRename())
+	defer func(s *State) { state = s }(state)
+	state = newState()
+
+	f := useFakeNetlink(t)
+	f.addVeth(10, "veth1a2b3c4", "/var/run/docker/netns/web", "eth0", "02:42:ac:11:00:02")
+
+	done := make(chan RenameSummary, 1)
+	go func() {
+		done <- renameContainerLinks(context.Background(), newFakeRuntime(), bridgeContainer("1234", "/web", "/var/run/docker/netns/web", "02:42:ac:11:00:02"), false)
+	}()
+
+	// The reload is not blocked by the throttled rename, which makes the name by the reloaded configuration.
+	time.Sleep(2 * renameThrottleCheckInterval)
+	configMu.Lock()
+	config.LinkNamePrefix = "x"
+	configMu.Unlock()
+
+	summary := <-done
+	assert.Equal(t, 1, summary.Renamed)
+	assert.Equal(t, "xweb0", f.links[10].Name)
+}
@@ -0,0 +1,295 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	log "github.com/sirupsen/logrus"
+)
+
+// Time to wait for the database locked by another process, e.g. the lookup command reading it.
+const timelineBusyTimeout = 5 * time.Second
+
+// Assignments of the host link names to the container links. The times are in Unix nanoseconds,
+// and the end is null while the name is assigned.
+const timelineSchema = `
+CREATE TABLE IF NOT EXISTS assignments (
+	id INTEGER PRIMARY KEY,
+	ifindex INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	original_name TEXT NOT NULL,
+	container_id TEXT NOT NULL,
+	container_name TEXT NOT NULL,
+	container_link TEXT NOT NULL,
+	assigned_at INTEGER NOT NULL,
+	ended_at INTEGER,
+	ended_by TEXT
+);
+CREATE INDEX IF NOT EXISTS assignments_ifindex ON assignments (ifindex, assigned_at);
+CREATE INDEX IF NOT EXISTS assignments_name ON assignments (name, assigned_at);
+CREATE INDEX IF NOT EXISTS assignments_ended ON assignments (ended_at);
+`
+
+// Assignment of the host link name to the container link, recorded in the timeline.
+type TimelineAssignment struct {
+	Index         int
+	Name          string
+	OriginalName  string
+	ContainerID   string
+	ContainerName string
+	ContainerLink string
+	AssignedAt    time.Time
+	// Zero while the name is assigned.
+	EndedAt time.Time
+	// Operation ending the assignment: rename, restore, or release.
+	EndedBy string
+}
+
+// Timeline of the host link name assignments in the SQLite database. Kept for a long time,
+// so packet captures and flow logs taken long ago are attributed to the containers gone since.
+type Timeline struct {
+	db *sql.DB
+}
+
+// Opens the timeline database, creating it unless read-only.
+func openTimeline(path string, readOnly bool) (*Timeline, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", timelineBusyTimeout.Milliseconds()))
+	if readOnly {
+		query.Set("mode", "ro")
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, err
+		}
+		// The database is created by SQLite with the permissive mode otherwise.
+		dbFile, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		dbFile.Close()
+	}
+
+	dsn := (&url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}).String()
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if readOnly {
+		err = db.Ping()
+	} else {
+		_, err = db.Exec(timelineSchema)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Timeline{db: db}, nil
+}
+
+func (t *Timeline) Close() error {
+	return t.db.Close()
+}
+
+// Records the assignment of the name to the host link, ending the previous assignment of the interface index.
+func (t *Timeline) Assign(at time.Time, a TimelineAssignment) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE assignments SET ended_at = ?, ended_by = ? WHERE ifindex = ? AND ended_at IS NULL`,
+		at.UnixNano(), RenameApply, a.Index); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO assignments (ifindex, name, original_name, container_id, container_name, container_link, assigned_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.Index, a.Name, a.OriginalName, a.ContainerID, a.ContainerName, a.ContainerLink, at.UnixNano()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Ends the assignment of the interface index by the operation, e.g. restoring the original name.
+func (t *Timeline) End(at time.Time, index int, operation string) error {
+	_, err := t.db.Exec(`UPDATE assignments SET ended_at = ?, ended_by = ? WHERE ifindex = ? AND ended_at IS NULL`,
+		at.UnixNano(), operation, index)
+	return err
+}
+
+// Returns the latest assignment of the host link name, or of the interface index when it is positive,
+// started before the time. The assignment may have ended before the time too.
+func (t *Timeline) AssignmentAt(name string, index int, at time.Time) (TimelineAssignment, bool, error) {
+	column, key := "name", any(name)
+	if index > 0 {
+		column, key = "ifindex", index
+	}
+
+	row := t.db.QueryRow(`SELECT ifindex, name, original_name, container_id, container_name, container_link, assigned_at, ended_at, ended_by
+		FROM assignments WHERE `+column+` = ? AND assigned_at <= ? ORDER BY assigned_at DESC, id DESC LIMIT 1`, key, at.UnixNano())
+
+	var a TimelineAssignment
+	var assignedAt int64
+	var endedAt sql.NullInt64
+	var endedBy sql.NullString
+	err := row.Scan(&a.Index, &a.Name, &a.OriginalName, &a.ContainerID, &a.ContainerName, &a.ContainerLink, &assignedAt, &endedAt, &endedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, false, nil
+	} else if err != nil {
+		return a, false, err
+	}
+
+	a.AssignedAt = time.Unix(0, assignedAt)
+	if endedAt.Valid {
+		a.EndedAt = time.Unix(0, endedAt.Int64)
+		a.EndedBy = endedBy.String
+	}
+	return a, true, nil
+}
+
+// Deletes the assignments ended before the time. Returns the number of the deleted assignments.
+func (t *Timeline) Prune(before time.Time) (int64, error) {
+	result, err := t.db.Exec(`DELETE FROM assignments WHERE ended_at < ?`, before.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Timeline database of the configuration, opened on the first record, and reopened when the path changes.
+var (
+	timelineMu   sync.Mutex
+	timelinePath string
+	timeline     *Timeline
+)
+
+// Runs the function with the timeline database of the configuration. Does nothing when the timeline is disabled.
+func withTimeline(fn func(t *Timeline) error) error {
+	timelineMu.Lock()
+	defer timelineMu.Unlock()
+
+	if timelinePath != config.TimelineDB {
+		closeTimelineLocked()
+		timelinePath = config.TimelineDB
+	}
+	if len(timelinePath) == 0 {
+		return nil
+	}
+
+	if timeline == nil {
+		var err error
+		timeline, err = openTimeline(timelinePath, false)
+		if err != nil {
+			return err
+		}
+	}
+	return fn(timeline)
+}
+
+// Closes the timeline database, when opened.
+func closeTimeline() {
+	timelineMu.Lock()
+	defer timelineMu.Unlock()
+	closeTimelineLocked()
+}
+
+func closeTimelineLocked() {
+	if timeline == nil {
+		return
+	}
+	if err := timeline.Close(); err != nil {
+		log.Errorf("Cannot close timeline database: %s: %s", timelinePath, err)
+	}
+	timeline = nil
+}
+
+// Records the assignment, or its end, in the timeline database when configured. The failure is logged.
+func recordTimeline(fn func(t *Timeline) error) {
+	if dryRun {
+		return
+	}
+	if err := withTimeline(fn); err != nil {
+		log.Errorf("Cannot write timeline database: %s: %s", config.TimelineDB, err)
+	}
+}
+
+// Deletes the assignments ended before timeline_retention from the timeline database.
+// Returns the number of the deleted assignments.
+func pruneTimeline(now time.Time) int {
+	if config.TimelineRetention <= 0 {
+		return 0
+	}
+
+	var pruned int64
+	err := withTimeline(func(t *Timeline) error {
+		var err error
+		pruned, err = t.Prune(now.Add(-config.TimelineRetention))
+		return err
+	})
+	if err != nil {
+		log.Errorf("Cannot prune timeline database: %s: %s", config.TimelineDB, err)
+	}
+	return int(pruned)
+}
+
+// Returns the container owning the host link at the time, from the timeline database.
+func timelineOwner(path string, name string, index int, at time.Time) (TimelineAssignment, error) {
+	linkDesc := name
+	if index > 0 {
+		linkDesc = fmt.Sprintf("ifindex %d", index)
+	}
+
+	t, err := openTimeline(path, true)
+	if err != nil {
+		return TimelineAssignment{}, fmt.Errorf("cannot open timeline database: %w", err)
+	}
+	defer t.Close()
+
+	a, ok, err := t.AssignmentAt(name, index, at)
+	if err != nil {
+		return a, fmt.Errorf("cannot read timeline database: %w", err)
+	}
+	if !ok {
+		return a, fmt.Errorf("host link is not found in timeline: %s", linkDesc)
+	}
+	if !a.EndedAt.IsZero() && !a.EndedAt.After(at) {
+		return a, fmt.Errorf("host link is not owned by any container since %s (%s by %s): %s",
+			a.EndedAt.UTC().Format(time.RFC3339), a.EndedBy, strings.TrimPrefix(a.ContainerName, "/"), linkDesc)
+	}
+	return a, nil
+}
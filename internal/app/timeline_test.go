@@ -0,0 +1,106 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline", "timeline.db")
+	tl, err := openTimeline(path, false)
+	require.NoError(t, err)
+	defer tl.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, tl.Assign(base, TimelineAssignment{Index: 10, Name: "vweb0", OriginalName: "veth1", ContainerName: "/web"}))
+	require.NoError(t, tl.Assign(base.Add(time.Hour), TimelineAssignment{Index: 12, Name: "vdb0", OriginalName: "veth2", ContainerName: "/db"}))
+	require.NoError(t, tl.Assign(base.Add(2*time.Hour), TimelineAssignment{Index: 14, Name: "vweb0", OriginalName: "veth3", ContainerName: "/web"}))
+	// Index reused by the kernel for the link of another container.
+	require.NoError(t, tl.Assign(base.Add(3*time.Hour), TimelineAssignment{Index: 10, Name: "vapi0", OriginalName: "veth4", ContainerName: "/api"}))
+	require.NoError(t, tl.End(base.Add(5*time.Hour), 12, RenameRelease))
+	require.NoError(t, tl.End(base.Add(6*time.Hour), 14, RenameRestore))
+
+	a, ok, err := tl.AssignmentAt("vweb0", 0, base.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10, a.Index)
+	assert.Equal(t, base.Add(3*time.Hour), a.EndedAt.UTC())
+	assert.Equal(t, RenameApply, a.EndedBy)
+
+	a, ok, err = tl.AssignmentAt("vweb0", 0, base.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 14, a.Index)
+	assert.True(t, a.EndedAt.After(base.Add(5*time.Hour)))
+
+	a, ok, err = tl.AssignmentAt("", 10, base.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "/api", a.ContainerName)
+	assert.True(t, a.EndedAt.IsZero())
+
+	_, ok, err = tl.AssignmentAt("vdb0", 0, base)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	a, ok, err = tl.AssignmentAt("", 12, base.Add(8*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, RenameRelease, a.EndedBy)
+
+	// Only the assignments ended before the time are deleted.
+	pruned, err := tl.Prune(base.Add(5*time.Hour + time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pruned)
+	_, ok, err = tl.AssignmentAt("", 12, base.Add(8*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = tl.AssignmentAt("", 14, base.Add(8*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTimelineReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	_, err := openTimeline(path, true)
+	assert.Error(t, err)
+	assert.NoFileExists(t, path)
+
+	tl, err := openTimeline(path, false)
+	require.NoError(t, err)
+	defer tl.Close()
+
+	ro, err := openTimeline(path, true)
+	require.NoError(t, err)
+	defer ro.Close()
+	assert.Error(t, ro.End(time.Now(), 10, RenameRelease))
+}
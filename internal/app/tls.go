@@ -0,0 +1,127 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Loads the server certificate, reloading it when the files change, so renewed certificates are picked up without restart.
+type CertificateLoader struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertificateLoader(certFile string, keyFile string) (*CertificateLoader, error) {
+	loader := &CertificateLoader{certFile: certFile, keyFile: keyFile}
+	if _, err := loader.GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	return loader, nil
+}
+
+// Returns the certificate, reloading it when the files were modified since the last load.
+// The last loaded certificate is kept when the files cannot be loaded, e.g. when they are being replaced.
+func (c *CertificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	certInfo, certErr := os.Stat(c.certFile)
+	keyInfo, keyErr := os.Stat(c.keyFile)
+	if certErr == nil && keyErr == nil && certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime) {
+		return c.certificate, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		if c.certificate != nil {
+			return c.certificate, nil
+		}
+		return nil, err
+	}
+
+	c.certificate = &certificate
+	if certErr == nil && keyErr == nil {
+		c.certModTime, c.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+	}
+	return c.certificate, nil
+}
+
+// Returns the TLS configuration of the TCP listeners, or nil when TLS is disabled.
+// Client certificates are required and verified when the client CA file is configured.
+func serverTLSConfig() (*tls.Config, error) {
+	if len(config.TLSCertFile) == 0 {
+		return nil, nil
+	}
+
+	loader, err := newCertificateLoader(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: loader.GetCertificate,
+	}
+
+	if len(config.TLSClientCAFile) > 0 {
+		data, err := os.ReadFile(config.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read TLS client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in TLS client CA file: %s", config.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// Listens on the TCP address, over TLS when configured.
+func listenTCP(address string) (net.Listener, error) {
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return listener, nil
+}
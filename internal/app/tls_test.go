@@ -0,0 +1,146 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Issues the certificate signed by the parent, or self-signed when the parent is nil.
+// Returns the certificate and its key pair.
+func issueTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// Writes the certificate and its key to the PEM files.
+func writeTestCertificate(t *testing.T, certificate *x509.Certificate, key *ecdsa.PrivateKey, certFile string, keyFile string) {
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}), 0o600))
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+}
+
+func TestListenTCPMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, _ := issueTestCertificate(t, "ca", nil, nil)
+	server, serverKey, _ := issueTestCertificate(t, "server", ca, caKey)
+	_, _, client := issueTestCertificate(t, "client", ca, caKey)
+
+	certFile, keyFile, caFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "ca.pem")
+	writeTestCertificate(t, server, serverKey, certFile, keyFile)
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600))
+
+	defer func(c Config) { config = c }(config)
+	config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile = certFile, keyFile, caFile
+
+	listener, err := listenTCP("127.0.0.1:0")
+	require.NoError(t, err)
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	})}
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	url := "https://" + listener.Addr().String()
+
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{client}}}}
+	resp, err := authenticated.Get(url)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "client", string(body))
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	_, err = anonymous.Get(url)
+	assert.Error(t, err)
+}
+
+func TestCertificateLoaderReload(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, _ := issueTestCertificate(t, "ca", nil, nil)
+	first, firstKey, _ := issueTestCertificate(t, "first", ca, caKey)
+	second, secondKey, _ := issueTestCertificate(t, "second", ca, caKey)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCertificate(t, first, firstKey, certFile, keyFile)
+
+	loader, err := newCertificateLoader(certFile, keyFile)
+	require.NoError(t, err)
+
+	certificate, err := loader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.Raw, certificate.Certificate[0])
+
+	writeTestCertificate(t, second, secondKey, certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	require.NoError(t, os.Chtimes(keyFile, later, later))
+
+	certificate, err = loader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.Raw, certificate.Certificate[0])
+
+	// The last certificate is kept while the files are missing.
+	require.NoError(t, os.Remove(keyFile))
+	certificate, err = loader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.Raw, certificate.Certificate[0])
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-ilin/docker-veth-namer/pkg/naming"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Number of the WebAssembly memory pages of 64 KiB per megabyte.
+const wasmPagesPerMegabyte = 16
+
+// Identity of the compiled naming module. The module is compiled again when the file or the memory limit changes.
+type wasmModuleKey struct {
+	path        string
+	modTime     time.Time
+	size        int64
+	memoryLimit int
+}
+
+// Runs the WASI module with the container link as JSON on stdin, and takes the host link name from its stdout,
+// alike the naming command. The module is compiled once, and instantiated afresh for each name,
+// within the memory and the time limits of the configuration.
+type WasmStrategy struct {
+	mu       sync.Mutex
+	key      wasmModuleKey
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// Returns the runtime and the naming module of the configuration, compiling the module when changed.
+// The runtime of the previous module is closed, failing the names being made by it.
+func (s *WasmStrategy) module() (wazero.Runtime, wazero.CompiledModule, error) {
+	info, err := os.Stat(config.NamingModule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := wasmModuleKey{config.NamingModule, info.ModTime(), info.Size(), config.NamingModuleMemoryLimit}
+	if s.runtime != nil && s.key == key {
+		return s.runtime, s.compiled, nil
+	}
+
+	data, err := os.ReadFile(config.NamingModule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(config.NamingModuleMemoryLimit * wasmPagesPerMegabyte)).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, nil, err
+	}
+
+	compiled, err := runtime.CompileModule(ctx, data)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, nil, fmt.Errorf("cannot compile naming module: %w", err)
+	}
+
+	if s.runtime != nil {
+		s.runtime.Close(ctx)
+	}
+	s.key, s.runtime, s.compiled = key, runtime, compiled
+	return runtime, compiled, nil
+}
+
+func (s *WasmStrategy) LinkName(req NameRequest, trace *naming.Trace) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	runtime, compiled, err := s.module()
+	if err != nil {
+		trace.Step(fmt.Sprintf("Naming module %q failed: %s", config.NamingModule, err), "")
+		return "", fmt.Errorf("naming module failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.NamingModuleTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	moduleConfig := wazero.NewModuleConfig().
+		WithName("").
+		WithStdin(bytes.NewReader(body)).
+		WithStdout(&stdout).
+		WithStderr(&stderr)
+	module, err := runtime.InstantiateModule(ctx, compiled, moduleConfig)
+	if module != nil {
+		module.Close(context.Background())
+	}
+	if err != nil {
+		trace.Step(fmt.Sprintf("Naming module %q failed: %s", config.NamingModule, err), "")
+		if message := bytes.TrimSpace(stderr.Bytes()); len(message) > 0 {
+			return "", fmt.Errorf("naming module failed: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("naming module failed: %w", err)
+	}
+
+	linkName := strings.TrimSpace(stdout.String())
+	trace.Step(fmt.Sprintf("Run naming module %q with the container link as JSON on stdin", config.NamingModule), linkName)
+
+	if !isValidMadeName(linkName, req.MaxLength) {
+		return "", fmt.Errorf("naming module returned invalid host link name: %q", linkName)
+	}
+	return linkName, nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns the unsigned LEB128 encoding of the number.
+func wasmUint(n uint32) []byte {
+	var b []byte
+	for {
+		c := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+// Returns the vector of the items, prefixed by their count.
+func wasmVector(items ...[]byte) []byte {
+	b := wasmUint(uint32(len(items)))
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+// Returns the bytes prefixed by their length, e.g. a name.
+func wasmBytes(data []byte) []byte {
+	return append(wasmUint(uint32(len(data))), data...)
+}
+
+func wasmSection(id byte, contents []byte) []byte {
+	return append([]byte{id}, wasmBytes(contents)...)
+}
+
+// Returns the WASI command module running the code of _start, with the memory of the pages holding the data at zero offset.
+// The functions fd_read and fd_write of WASI are imported as the functions 0 and 1.
+func wasiModule(memoryPages uint32, data []byte, code ...byte) []byte {
+	const i32 = 0x7f
+	fdFunction := []byte{0x60, 4, i32, i32, i32, i32, 1, i32}
+	startFunction := []byte{0x60, 0, 0}
+	importFunction := func(name string) []byte {
+		b := append(wasmBytes([]byte("wasi_snapshot_preview1")), wasmBytes([]byte(name))...)
+		return append(b, 0x00, 0)
+	}
+
+	module := []byte{0x00, 'a', 's', 'm', 1, 0, 0, 0}
+	module = append(module, wasmSection(1, wasmVector(fdFunction, startFunction))...)
+	module = append(module, wasmSection(2, wasmVector(importFunction("fd_read"), importFunction("fd_write")))...)
+	module = append(module, wasmSection(3, wasmVector([]byte{1}))...)
+	module = append(module, wasmSection(5, wasmVector(append([]byte{0x00}, wasmUint(memoryPages)...)))...)
+	module = append(module, wasmSection(7, wasmVector(
+		append(wasmBytes([]byte("_start")), 0x00, 2),
+		append(wasmBytes([]byte("memory")), 0x02, 0),
+	))...)
+	body := append([]byte{0}, code...)
+	module = append(module, wasmSection(10, wasmVector(wasmBytes(append(body, 0x0b))))...)
+	segment := append([]byte{0x00, 0x41, 0, 0x0b}, wasmBytes(data)...)
+	return append(module, wasmSection(11, wasmVector(segment))...)
+}
+
+// Returns the I/O vector of WASI pointing at the buffer.
+func wasiIOVector(offset uint32, length uint32) []byte {
+	return binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, offset), length)
+}
+
+var (
+	// Calls fd_write of stdout with the I/O vector at zero offset, the count written at offset 8.
+	wasmWriteStdout = []byte{0x41, 1, 0x41, 0, 0x41, 1, 0x41, 8, 0x10, 1, 0x1a}
+	// Calls fd_read of stdin with the I/O vector at zero offset, the count read at offset 8.
+	wasmReadStdin = []byte{0x41, 0, 0x41, 0, 0x41, 1, 0x41, 8, 0x10, 0, 0x1a}
+	// Sets the length of the I/O vector to the count read.
+	wasmSetReadLength = []byte{0x41, 4, 0x41, 8, 0x28, 2, 0, 0x36, 2, 0}
+	// Loops forever.
+	wasmLoop = []byte{0x03, 0x40, 0x0c, 0, 0x0b}
+)
+
+// Writes the module into the temporary directory, and returns its path.
+func writeWasmModule(t *testing.T, name string, module []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, module, 0o644))
+	return path
+}
+
+func TestWasmNamingStrategy(t *testing.T) {
+	prevConfig := config
+	defer func() { config = prevConfig }()
+	config = defaultConfig()
+	config.NamingStrategy = NamingStrategyWasm
+
+	name := append(wasiIOVector(16, 11), make([]byte, 8)...)
+	config.NamingModule = writeWasmModule(t, "name.wasm", wasiModule(1, append(name, " web-nginx "...), wasmWriteStdout...))
+	linkName, err := makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth1", LinkLabels: LinkLabels{Image: "nginx"}})
+	require.NoError(t, err)
+	assert.Equal(t, "web-nginx", linkName)
+
+	trace := explainLinkName("/web", "eth1")
+	assert.Equal(t, "web-nginx", trace.LinkName)
+	assert.Len(t, trace.Steps, 1)
+
+	// The module echoing the request receives it as JSON on stdin.
+	echo := append(append(append([]byte{}, wasmReadStdin...), wasmSetReadLength...), wasmWriteStdout...)
+	config.NamingModule = writeWasmModule(t, "echo.wasm", wasiModule(1, wasiIOVector(64, 1024), echo...))
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth1"})
+	assert.EqualError(t, err, `naming module returned invalid host link name: "{\"container_name\":\"/web\",\"container_link\":\"eth1\",\"max_length\":15}"`)
+
+	config.NamingModule = writeWasmModule(t, "loop.wasm", wasiModule(1, nil, wasmLoop...))
+	config.NamingModuleTimeout = 100 * time.Millisecond
+	start := time.Now()
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	assert.ErrorContains(t, err, "deadline exceeded")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// Memory of 128 MiB exceeds the limit.
+	config.NamingModule = writeWasmModule(t, "memory.wasm", wasiModule(2048, nil))
+	config.NamingModuleMemoryLimit = 64
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	assert.ErrorContains(t, err, "cannot compile naming module")
+	config.NamingModuleMemoryLimit = 256
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	assert.ErrorContains(t, err, "invalid host link name")
+
+	config.NamingModule = filepath.Join(t.TempDir(), "missing.wasm")
+	_, err = makeLinkNameFor(NameRequest{ContainerName: "/web", ContainerLink: "eth0"})
+	assert.ErrorContains(t, err, "no such file or directory")
+}
@@ -0,0 +1,273 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Interval of the daemon state polling by the watch command.
+	watchRefreshInterval = time.Second
+	// Number of the recent notifications shown by the watch command.
+	watchNotificationLines = 10
+)
+
+// Keys of the watch command.
+const (
+	keyUp     = "up"
+	keyDown   = "down"
+	keyResync = "r"
+	keyRevert = "x"
+	keyQuit   = "q"
+)
+
+// State of the watch screen.
+type WatchModel struct {
+	status        Status
+	mappings      []ContainerMapping
+	notifications []Notification
+	// Index of the selected container.
+	selected int
+	// Result of the last action, or the polling error.
+	message string
+}
+
+// Fetches the daemon state via the control socket.
+func (m *WatchModel) refresh(path string) {
+	var status Status
+	var mappings []ContainerMapping
+	var notifications []Notification
+	err := errors.Join(
+		controlRequest(path, http.MethodGet, "/status", &status),
+		controlRequest(path, http.MethodGet, "/mappings", &mappings),
+		controlRequest(path, http.MethodGet, "/notifications", &notifications),
+	)
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+
+	m.status, m.mappings, m.notifications = status, mappings, notifications
+	m.selected = max(0, min(m.selected, len(m.mappings)-1))
+}
+
+// Handles the key. Returns false when the watch is to be stopped.
+func (m *WatchModel) handleKey(path string, key string) bool {
+	switch key {
+	case keyQuit:
+		return false
+
+	case keyUp:
+		m.selected = max(0, m.selected-1)
+
+	case keyDown:
+		m.selected = max(0, min(m.selected+1, len(m.mappings)-1))
+
+	case keyResync:
+		m.message = "Resync requested"
+		if err := controlRequest(path, http.MethodPost, "/resync", &struct{}{}); err != nil {
+			m.message = err.Error()
+		}
+
+	case keyRevert:
+		if m.selected >= len(m.mappings) {
+			return true
+		}
+		container := m.mappings[m.selected]
+		m.message = fmt.Sprintf("Revert requested: %s", strings.TrimPrefix(container.Name, "/"))
+		if err := controlRequest(path, http.MethodPost, "/revert/"+url.PathEscape(container.ID), &struct{}{}); err != nil {
+			m.message = err.Error()
+		}
+	}
+	return true
+}
+
+// Renders the screen of the size.
+func (m *WatchModel) render(width int, height int) string {
+	var lines []string
+
+	connected := "connected"
+	if !m.status.DockerConnected {
+		connected = "disconnected"
+	}
+	paused := ""
+	if m.status.Paused {
+		paused = ", PAUSED"
+	}
+	lines = append(lines,
+		fmt.Sprintf("docker-veth-namer %s, uptime %s, Docker %s%s", m.status.Version, m.status.Uptime, connected, paused),
+		fmt.Sprintf("Containers: %d, links: %d, pending tasks: %d, pending retries: %d",
+			m.status.TrackedContainers, m.status.TrackedLinks, m.status.PendingTasks, m.status.PendingRetries),
+		"",
+		fmt.Sprintf("  %-24s %-12s %-8s %-16s %s", "CONTAINER", "LINK", "IFINDEX", "HOST LINK", "ORIGINAL NAME"),
+	)
+
+	for i, mapping := range m.mappings {
+		marker := " "
+		if i == m.selected {
+			marker = ">"
+		}
+		for j, link := range mapping.Links {
+			name := ""
+			if j == 0 {
+				name = strings.TrimPrefix(mapping.Name, "/")
+			}
+			lines = append(lines, fmt.Sprintf("%s %-24s %-12s %-8d %-16s %s", marker, name, link.ContainerLink, link.Index, link.Name, link.OriginalName))
+			marker = " "
+		}
+	}
+
+	lines = append(lines, "", "Recent events:")
+	notifications := m.notifications[max(0, len(m.notifications)-watchNotificationLines):]
+	for i := len(notifications) - 1; i >= 0; i-- {
+		n := notifications[i]
+		lines = append(lines, fmt.Sprintf("  %s %-16s %s %s: %s => %s",
+			n.Time.Local().Format(time.TimeOnly), n.Type, strings.TrimPrefix(n.ContainerName, "/"), n.ContainerLink, n.OriginalName, n.Name))
+	}
+
+	// Keep the footer visible.
+	footer := []string{"", m.message, "[up/down] select  [r] resync  [x] revert selected container  [q] quit"}
+	if height > len(footer) && len(lines) > height-len(footer) {
+		lines = lines[:height-len(footer)]
+	}
+	lines = append(lines, footer...)
+
+	for i, line := range lines {
+		if width > 0 && len(line) > width {
+			lines[i] = line[:width]
+		}
+	}
+
+	return strings.Join(lines, "\r\n")
+}
+
+// Parses the key from the terminal input.
+func parseKey(input []byte) string {
+	switch string(input) {
+	case "\x1b[A", "k":
+		return keyUp
+	case "\x1b[B", "j":
+		return keyDown
+	case "r", "R":
+		return keyResync
+	case "x", "X":
+		return keyRevert
+	case "q", "Q", "\x03", "\x1b":
+		return keyQuit
+	}
+	return ""
+}
+
+// Switches the terminal to the raw mode. Returns the function restoring the terminal.
+func makeRawTerminal(fd int) (func(), error) {
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+	saved := *termios
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, termios); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		unix.IoctlSetTermios(fd, unix.TCSETS, &saved)
+	}, nil
+}
+
+// Shows the containers, their link mappings, and the recent events of the running daemon, until quit.
+func runWatch(path string) error {
+	if len(path) == 0 {
+		return errors.New("control socket is disabled in the configuration")
+	}
+
+	fd := int(os.Stdin.Fd())
+	restore, err := makeRawTerminal(fd)
+	if err != nil {
+		return fmt.Errorf("terminal is required: %w", err)
+	}
+	defer restore()
+
+	// Hide the cursor, and use the alternate screen.
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go func() {
+		buf := make([]byte, 8)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			if key := parseKey(buf[:n]); len(key) > 0 {
+				keys <- key
+			}
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+	defer signal.Stop(resize)
+
+	ticker := time.NewTicker(watchRefreshInterval)
+	defer ticker.Stop()
+
+	m := &WatchModel{}
+	m.refresh(path)
+	for {
+		width, height := 0, 0
+		if ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ); err == nil {
+			width, height = int(ws.Col), int(ws.Row)
+		}
+		fmt.Print("\x1b[H\x1b[2J" + m.render(width, height))
+
+		select {
+		case key, ok := <-keys:
+			if !ok || !m.handleKey(path, key) {
+				return nil
+			}
+			m.refresh(path)
+		case <-ticker.C:
+			m.refresh(path)
+		case <-resize:
+		}
+	}
+}
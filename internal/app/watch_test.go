@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchRender(t *testing.T) {
+	m := &WatchModel{
+		status: Status{Version: "1.0", Uptime: "1m0s", DockerConnected: true, TrackedContainers: 2, TrackedLinks: 3},
+		mappings: []ContainerMapping{
+			{ID: "db1", Name: "/db", Links: []LinkState{{Index: 10, ContainerLink: "eth0", OriginalName: "veth1", Name: "vdb0"}}},
+			{ID: "web1", Name: "/web", Links: []LinkState{
+				{Index: 11, ContainerLink: "eth0", OriginalName: "veth2", Name: "vwb0"},
+				{Index: 12, ContainerLink: "eth1", OriginalName: "veth3", Name: "vwb1"},
+			}},
+		},
+		notifications: []Notification{
+			{Type: NotificationMappingAdded, Time: time.Now(), ContainerName: "/web", ContainerLink: "eth1", OriginalName: "veth3", Name: "vwb1"},
+		},
+	}
+
+	m.handleKey("", keyDown)
+	m.handleKey("", keyDown)
+	assert.Equal(t, 1, m.selected)
+
+	lines := strings.Split(m.render(200, 0), "\r\n")
+	assert.Contains(t, lines[0], "Docker connected")
+	assert.True(t, strings.HasPrefix(lines[4], "  db "))
+	assert.True(t, strings.HasPrefix(lines[5], "> web "))
+	assert.Contains(t, lines[6], "vwb1")
+	assert.Contains(t, lines[9], "mapping_added")
+
+	// The footer is kept visible.
+	lines = strings.Split(m.render(20, 5), "\r\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "[up/down] select  [r", lines[4])
+
+	assert.Equal(t, keyUp, parseKey([]byte("\x1b[A")))
+	assert.Equal(t, keyQuit, parseKey([]byte("q")))
+	assert.Equal(t, "", parseKey([]byte("z")))
+	assert.False(t, m.handleKey("", keyQuit))
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package dockerwatch selects the Docker events which may change the links of the containers,
+// and correlates the inspected containers with their network namespaces and networks.
+package dockerwatch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+)
+
+var (
+	ErrHostNetwork      = errors.New("container is running in host network mode")
+	ErrNoneNetwork      = errors.New("container is running in none network mode")
+	ErrNoSandbox        = errors.New("sandbox is not defined")
+	ErrDefaultNamespace = errors.New("default namespace is not supported")
+)
+
+// Docker event type and action, e.g. "network connect".
+type Trigger struct {
+	Type   events.Type
+	Action events.Action
+}
+
+func (t Trigger) String() string {
+	return fmt.Sprintf("%s %s", t.Type, t.Action)
+}
+
+var (
+	TriggerNetworkConnect = Trigger{events.NetworkEventType, events.ActionConnect}
+	TriggerContainerStart = Trigger{events.ContainerEventType, events.ActionStart}
+)
+
+// Returns the trigger matching the event.
+func EventTrigger(event events.Message) Trigger {
+	return Trigger{Type: event.Type, Action: event.Action}
+}
+
+// Parses the event trigger in form "<type> <action>". Only container and network events are supported,
+// since those refer to the container.
+func ParseTrigger(s string) (Trigger, error) {
+	eventType, action, ok := strings.Cut(strings.TrimSpace(s), " ")
+	action = strings.TrimSpace(action)
+	if !ok || len(action) == 0 {
+		return Trigger{}, fmt.Errorf("event trigger must be in form '<type> <action>': %q", s)
+	}
+
+	switch events.Type(eventType) {
+	case events.ContainerEventType, events.NetworkEventType:
+	default:
+		return Trigger{}, fmt.Errorf("event trigger type must be either container or network: %q", s)
+	}
+
+	return Trigger{Type: events.Type(eventType), Action: events.Action(action)}, nil
+}
+
+// Parses the event triggers, skipping the invalid ones.
+func ParseTriggers(triggers []string) map[Trigger]bool {
+	parsed := make(map[Trigger]bool, len(triggers))
+	for _, s := range triggers {
+		if trigger, err := ParseTrigger(s); err == nil {
+			parsed[trigger] = true
+		}
+	}
+	return parsed
+}
+
+// Returns the path of the network namespace of the container sandbox, where the container ends of its veth links are.
+// The containers sharing the host network, or having no network, have no links of their own.
+func NetworkNamespace(inspect container.InspectResponse) (string, error) {
+	if inspect.ContainerJSONBase != nil && inspect.HostConfig != nil {
+		switch inspect.HostConfig.NetworkMode {
+		case "host":
+			return "", ErrHostNetwork
+		case "none":
+			return "", ErrNoneNetwork
+		}
+	}
+
+	if inspect.NetworkSettings == nil || len(inspect.NetworkSettings.SandboxKey) == 0 {
+		return "", ErrNoSandbox
+	}
+	sandboxKey := inspect.NetworkSettings.SandboxKey
+	if strings.HasSuffix(sandboxKey, "/default") {
+		return "", ErrDefaultNamespace
+	}
+	return sandboxKey, nil
+}
+
+// Returns the name of the network the container link with the MAC address is connected to, or empty string if not known.
+// The link not matching any endpoint is attributed to the only endpoint with unknown MAC address, if there is one.
+func LinkNetwork(inspect container.InspectResponse, hardwareAddr string) string {
+	if inspect.NetworkSettings == nil || len(hardwareAddr) == 0 {
+		return ""
+	}
+
+	var unknown []string
+	for name, endpoint := range inspect.NetworkSettings.Networks {
+		if endpoint == nil {
+			continue
+		}
+		if strings.EqualFold(endpoint.MacAddress, hardwareAddr) {
+			return name
+		}
+		if len(endpoint.MacAddress) == 0 {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 1 {
+		return unknown[0]
+	}
+	return ""
+}
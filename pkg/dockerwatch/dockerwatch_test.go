@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package dockerwatch
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrigger(t *testing.T) {
+	trigger, err := ParseTrigger("network connect")
+	assert.NoError(t, err)
+	assert.Equal(t, TriggerNetworkConnect, trigger)
+
+	trigger, err = ParseTrigger(" container  rename ")
+	assert.NoError(t, err)
+	assert.Equal(t, Trigger{events.ContainerEventType, events.ActionRename}, trigger)
+
+	_, err = ParseTrigger("container")
+	assert.Error(t, err)
+
+	_, err = ParseTrigger("image pull")
+	assert.Error(t, err)
+}
+
+func TestNetworkNamespace(t *testing.T) {
+	inspect := func(mode container.NetworkMode, sandboxKey string) container.InspectResponse {
+		return container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{HostConfig: &container.HostConfig{NetworkMode: mode}},
+			NetworkSettings:   &container.NetworkSettings{NetworkSettingsBase: container.NetworkSettingsBase{SandboxKey: sandboxKey}},
+		}
+	}
+
+	path, err := NetworkNamespace(inspect("bridge", "/var/run/docker/netns/0123456789ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/run/docker/netns/0123456789ab", path)
+
+	_, err = NetworkNamespace(inspect("host", "/var/run/docker/netns/default"))
+	assert.ErrorIs(t, err, ErrHostNetwork)
+	_, err = NetworkNamespace(inspect("none", ""))
+	assert.ErrorIs(t, err, ErrNoneNetwork)
+	_, err = NetworkNamespace(inspect("bridge", ""))
+	assert.ErrorIs(t, err, ErrNoSandbox)
+	_, err = NetworkNamespace(inspect("container:web", "/var/run/docker/netns/default"))
+	assert.ErrorIs(t, err, ErrDefaultNamespace)
+	_, err = NetworkNamespace(container.InspectResponse{})
+	assert.ErrorIs(t, err, ErrNoSandbox)
+}
+
+func TestLinkNetwork(t *testing.T) {
+	inspect := container.InspectResponse{NetworkSettings: &container.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+		"frontend": {MacAddress: "02:42:ac:11:00:02"},
+		"backend":  {MacAddress: "02:42:AC:12:00:02"},
+	}}}
+
+	assert.Equal(t, "backend", LinkNetwork(inspect, "02:42:ac:12:00:02"))
+	assert.Equal(t, "frontend", LinkNetwork(inspect, "02:42:ac:11:00:02"))
+	assert.Empty(t, LinkNetwork(inspect, "02:42:ac:13:00:02"))
+	assert.Empty(t, LinkNetwork(inspect, ""))
+	assert.Empty(t, LinkNetwork(container.InspectResponse{}, "02:42:ac:12:00:02"))
+
+	// Unmatched link belongs to the only endpoint with unknown MAC address.
+	inspect.NetworkSettings.Networks["monitoring"] = &network.EndpointSettings{}
+	assert.Equal(t, "monitoring", LinkNetwork(inspect, "02:42:ac:13:00:02"))
+	assert.Equal(t, "backend", LinkNetwork(inspect, "02:42:ac:12:00:02"))
+
+	inspect.NetworkSettings.Networks["storage"] = &network.EndpointSettings{}
+	assert.Empty(t, LinkNetwork(inspect, "02:42:ac:13:00:02"))
+}
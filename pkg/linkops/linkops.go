@@ -0,0 +1,70 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package linkops lists the container ends of the veth links with the indexes of their host peers,
+// and recognizes the host link names assigned by Docker.
+package linkops
+
+import (
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Prefix of the host link names assigned by Docker.
+const DockerLinkPrefix = "veth"
+
+// Container end of the veth link.
+type VEth struct {
+	// Name of the link within the container.
+	Name string
+	// Index of the peer link at the host.
+	ParentIndex int
+	// MAC address of the link within the container.
+	HardwareAddr string
+}
+
+// Returns the veth links of the list, as listed within the network namespace of the container.
+func VEths(links []netlink.Link) []VEth {
+	var veths []VEth
+	for _, link := range links {
+		if link.Type() != "veth" {
+			continue
+		}
+
+		attrs := link.Attrs()
+		veths = append(veths, VEth{
+			Name:         attrs.Name,
+			ParentIndex:  attrs.ParentIndex,
+			HardwareAddr: attrs.HardwareAddr.String(),
+		})
+	}
+	return veths
+}
+
+// Returns the name assigned by Docker, if preserved as the alternative name of the renamed link.
+func PreservedName(link netlink.Link) string {
+	for _, altName := range link.Attrs().AltNames {
+		if strings.HasPrefix(altName, DockerLinkPrefix) && altName != link.Attrs().Name {
+			return altName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package linkops
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestVEths(t *testing.T) {
+	mac, err := net.ParseMAC("02:42:ac:11:00:02")
+	require.NoError(t, err)
+
+	links := []netlink.Link{
+		&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "lo", Index: 1}},
+		&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Index: 2, ParentIndex: 10, HardwareAddr: mac}},
+	}
+	assert.Equal(t, []VEth{{Name: "eth0", ParentIndex: 10, HardwareAddr: "02:42:ac:11:00:02"}}, VEths(links))
+	assert.Empty(t, VEths(links[:1]))
+}
+
+func TestPreservedName(t *testing.T) {
+	link := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "vweb0", AltNames: []string{"web-uplink", "veth1a2b3c4"}}}
+	assert.Equal(t, "veth1a2b3c4", PreservedName(link))
+
+	link = &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "veth1a2b3c4"}}
+	assert.Equal(t, "", PreservedName(link))
+}
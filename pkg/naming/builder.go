@@ -0,0 +1,86 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Computes the host link names the same way the program does.
+// A NameBuilder is immutable and safe for concurrent use.
+type NameBuilder struct {
+	opts Options
+}
+
+// Returns a builder of the given rules, or an error if the rules are invalid.
+func NewNameBuilder(opts Options) (*NameBuilder, error) {
+	if errs := opts.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid naming options: %w", errors.Join(errs...))
+	}
+
+	// Detach the slices from the caller.
+	opts.ContainerLinkPrefixes = slices.Clone(opts.ContainerLinkPrefixes)
+	replacements := make([]map[string]string, 0, len(opts.Replacements))
+	for _, pair := range opts.Replacements {
+		replacements = append(replacements, maps.Clone(pair))
+	}
+	opts.Replacements = replacements
+
+	return &NameBuilder{opts: opts}, nil
+}
+
+// Returns a builder configured from the configuration file of the program.
+// Only the naming keys are read, the other keys are ignored. The keys missing
+// in the file take the defaults of the program.
+func NameBuilderFromYAML(data []byte) (*NameBuilder, error) {
+	file := struct {
+		Options        `yaml:",inline"`
+		NamingStrategy string `yaml:"naming_strategy"`
+	}{Options: DefaultOptions()}
+
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	// The names of the external naming command cannot be predicted.
+	if file.NamingStrategy != "" && file.NamingStrategy != "morph" {
+		return nil, fmt.Errorf("naming_strategy %q is not supported, only morph names can be computed", file.NamingStrategy)
+	}
+
+	return NewNameBuilder(file.Options)
+}
+
+// Returns the host link name of the given container link.
+func (b *NameBuilder) HostLinkName(containerName, linkName string) (string, error) {
+	return LinkName(containerName, linkName, b.opts, nil)
+}
+
+// Returns the host link name of the given container link along with
+// the steps taken to produce it.
+func (b *NameBuilder) Explain(containerName, linkName string) (string, *Trace, error) {
+	trace := &Trace{}
+	name, err := LinkName(containerName, linkName, b.opts, trace)
+	return name, trace, err
+}
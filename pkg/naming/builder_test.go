@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameBuilderFromYAML(t *testing.T) {
+	b, err := NameBuilderFromYAML([]byte(`
+log_level: debug
+link_index_separator: "-"
+container_link_prefixes: [eth]
+replacements:
+  - server: srv
+`))
+	require.NoError(t, err)
+
+	name, err := b.HostLinkName("/postgres-server", "eth1")
+	require.NoError(t, err)
+	assert.Equal(t, "vpostgres-srv-1", name)
+
+	name, trace, err := b.Explain("/postgres-server", "eth1")
+	require.NoError(t, err)
+	assert.Equal(t, "vpostgres-srv-1", name)
+	assert.NotEmpty(t, trace.Steps)
+}
+
+func TestNameBuilderFromYAMLDefaults(t *testing.T) {
+	b, err := NameBuilderFromYAML(nil)
+	require.NoError(t, err)
+
+	name, err := b.HostLinkName("web", "eth0")
+	require.NoError(t, err)
+	assert.Equal(t, "vwebeth0", name)
+}
+
+func TestNameBuilderFromYAMLErrors(t *testing.T) {
+	_, err := NameBuilderFromYAML([]byte("naming_strategy: exec\n"))
+	assert.ErrorContains(t, err, "naming_strategy")
+
+	_, err = NameBuilderFromYAML([]byte("link_name_prefix: \"a/b\"\n"))
+	assert.ErrorContains(t, err, "link_name_prefix")
+
+	_, err = NameBuilderFromYAML([]byte("replacements: [1, 2\n"))
+	assert.Error(t, err)
+}
+
+func TestNewNameBuilderDetachesOptions(t *testing.T) {
+	opts := Options{LinkNamePrefix: "v", Replacements: []map[string]string{{"web": "w"}}}
+	b, err := NewNameBuilder(opts)
+	require.NoError(t, err)
+
+	opts.Replacements[0]["web"] = "x"
+
+	name, err := b.HostLinkName("web", "eth0")
+	require.NoError(t, err)
+	assert.Equal(t, "vweth0", name)
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package naming makes human-readable host link names of the container links, by morphing the container name
+// to fit into the link name length limit of the kernel.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/sys/unix"
+)
+
+// Maximum length of the link name in bytes, excluding the terminating '\0' of IFNAMSIZ.
+const MaxLength = unix.IFNAMSIZ - 1
+
+var (
+	ErrEmptyName         = errors.New("container name and container link name must not be empty")
+	ErrLinkSuffixTooLong = errors.New("container link suffix is too long")
+)
+
+// Rules of morphing the container name into the host link name.
+// The YAML keys are the same as in the configuration file of the program.
+type Options struct {
+	// Prefix of the host link names.
+	LinkNamePrefix string `yaml:"link_name_prefix"`
+	// Separator between the morphed container name and the container link suffix.
+	LinkIndexSeparator string `yaml:"link_index_separator"`
+	// Prefixes stripped from the container link name, the first matching one is stripped.
+	ContainerLinkPrefixes []string `yaml:"container_link_prefixes"`
+	// Replacement rules applied in order, each one is a single-entry map of the substring to its replacement.
+	// The replaced text is not matched by the following rules.
+	Replacements []map[string]string `yaml:"replacements"`
+	// Collapse the runs of the same symbol after the replacements.
+	RemoveDuplicatedSymbols bool `yaml:"remove_duplicated_symbols"`
+	// Maximum length of the host link name in bytes. MaxLength is used when zero.
+	MaxLength int `yaml:"-"`
+}
+
+// Returns the default rules of the program.
+func DefaultOptions() Options {
+	return Options{LinkNamePrefix: "v"}
+}
+
+// Checks the rules, returning an error per invalid one.
+func (o Options) Validate() []error {
+	var errs []error
+
+	maxLength := o.MaxLength
+	if maxLength == 0 {
+		maxLength = MaxLength
+	}
+
+	// At least one symbol of the container name, and at least one symbol of the link index.
+	if len(o.LinkNamePrefix)+len(o.LinkIndexSeparator) > maxLength-2 {
+		errs = append(errs, fmt.Errorf("link_name_prefix and link_index_separator are too long: %q %q", o.LinkNamePrefix, o.LinkIndexSeparator))
+	}
+
+	if !IsValidText(o.LinkIndexSeparator) {
+		errs = append(errs, fmt.Errorf("link_index_separator contains symbols not allowed in link names: %q", o.LinkIndexSeparator))
+	}
+
+	if !IsValidText(o.LinkNamePrefix) {
+		errs = append(errs, fmt.Errorf("link_name_prefix contains symbols not allowed in link names: %q", o.LinkNamePrefix))
+	}
+
+	for i, pair := range o.Replacements {
+		if len(pair) > 1 {
+			errs = append(errs, fmt.Errorf("replacements[%d] must contain a single needle", i))
+		}
+
+		for _, replacement := range pair {
+			if !IsValidText(replacement) {
+				errs = append(errs, fmt.Errorf("replacements[%d] contains symbols not allowed in link names: %q", i, replacement))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Step of making the host link name.
+type TraceStep struct {
+	Description string `json:"description"`
+	// Intermediate string after the step.
+	Result string `json:"result"`
+}
+
+// Steps of making the host link name, explaining why the name came out the way it did.
+type Trace struct {
+	ContainerName string      `json:"container_name"`
+	ContainerLink string      `json:"container_link"`
+	Steps         []TraceStep `json:"steps"`
+	// Empty when the name cannot be made.
+	LinkName string `json:"link_name"`
+}
+
+// Records the step. Does nothing when the trace is nil.
+func (t *Trace) Step(description string, result string) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, TraceStep{Description: description, Result: result})
+}
+
+// Records the replacement rule applied. Does nothing when the trace is nil.
+func (t *Trace) Replacement(needle string, replacement string, matches int, result string) {
+	switch matches {
+	case 0:
+		t.Step(fmt.Sprintf("Rule %q => %q: not matched", needle, replacement), result)
+	case 1:
+		t.Step(fmt.Sprintf("Rule %q => %q: matched once", needle, replacement), result)
+	default:
+		t.Step(fmt.Sprintf("Rule %q => %q: matched %d times", needle, replacement, matches), result)
+	}
+}
+
+// Returns whether the text may be a part of a link name: the kernel rejects slashes, colons and whitespace.
+func IsValidText(text string) bool {
+	return !strings.ContainsFunc(text, func(r rune) bool {
+		return r == '/' || r == ':' || unicode.IsSpace(r) || r > unicode.MaxASCII
+	})
+}
+
+// Returns any key/value from map.
+func mapKeyVal[K comparable, V any](m map[K]V) (k K, v V) {
+	for k, v := range m {
+		return k, v
+	}
+	return k, v
+}
+
+// Replaces substrings in the container name, recording the rules applied into the trace (which can be nil).
+func Replace(containerName string, replacements []map[string]string, trace *Trace) string {
+	type Substring struct {
+		text string
+		// Whether the current substring was already matched.
+		processed bool
+	}
+
+	substrings := make([]Substring, 0, len(containerName))
+	substrings = append(substrings, Substring{text: containerName})
+
+	// Assembles substrings into a string.
+	assemble := func() string {
+		var sb strings.Builder
+		for _, m := range substrings {
+			sb.WriteString(m.text)
+		}
+		return sb.String()
+	}
+
+	for _, pair := range replacements {
+		if len(pair) == 0 {
+			continue
+		}
+
+		needle, replacement := mapKeyVal(pair)
+		if len(needle) == 0 {
+			continue
+		}
+
+		matches := 0
+		var substringsUpdated []Substring
+		for _, m := range substrings {
+			if m.processed {
+				substringsUpdated = append(substringsUpdated, m)
+				continue
+			}
+
+			for {
+				i := strings.Index(m.text, needle)
+				if i == -1 {
+					substringsUpdated = append(substringsUpdated, m)
+					break
+				}
+
+				matches++
+				if i > 0 {
+					// Add unprocessed prefix.
+					substringsUpdated = append(substringsUpdated, Substring{text: m.text[:i]})
+				}
+
+				if len(replacement) > 0 {
+					substringsUpdated = append(substringsUpdated, Substring{text: replacement, processed: true})
+				}
+
+				if i+len(needle) < len(m.text) {
+					// Add unprocessed suffix. Process it on next iteration.
+					m = Substring{text: m.text[i+len(needle):]}
+				} else {
+					// No suffix.
+					break
+				}
+			}
+		}
+
+		substrings = substringsUpdated
+
+		if trace != nil {
+			trace.Replacement(needle, replacement, matches, assemble())
+		}
+	}
+
+	return assemble()
+}
+
+// Makes the human-readable link name, recording the steps into the trace (which can be nil).
+// Name format: [PREFIX][NAME][SEP][NUM]
+// Where [PREFIX] is a link name prefix, [NAME] is a morphed container name, [SEP] is a separator,
+// and [NUM] is the link number within the container.
+// Linux has limitation to the link name set to 15 symbols, see IFNAMSIZ,
+// therefore [NAME] is morphed container name according to the options.
+func LinkName(containerName string, containerLinkName string, opts Options, trace *Trace) (string, error) {
+	if len(containerName) == 0 || len(containerLinkName) == 0 {
+		trace.Step("Container name and container link name must not be empty", "")
+		return "", ErrEmptyName
+	}
+
+	maxLength := opts.MaxLength
+	if maxLength == 0 {
+		maxLength = MaxLength
+	}
+
+	// Remove everything before the last slash (including).
+	slashIndex := strings.LastIndex(containerName, "/")
+	if slashIndex != -1 {
+		containerName = containerName[slashIndex+1:]
+	}
+	trace.Step("Strip everything up to the last slash", containerName)
+
+	// Apply replacements.
+	morphedName := Replace(containerName, opts.Replacements, trace)
+
+	// Keep at least one symbol.
+	if len(morphedName) == 0 {
+		morphedName = string(containerName[0])
+		trace.Step("Replacements removed all symbols, keep the first symbol of the container name", morphedName)
+	}
+
+	// Remove duplicated symbols.
+	if opts.RemoveDuplicatedSymbols {
+		dedupName := make([]byte, 0, len(morphedName))
+		for i := range morphedName {
+			if i > 0 {
+				if dedupName[len(dedupName)-1] == morphedName[i] {
+					continue
+				}
+			}
+			dedupName = append(dedupName, morphedName[i])
+		}
+		if string(dedupName) != morphedName {
+			trace.Step("Remove duplicated symbols", string(dedupName))
+		}
+		morphedName = string(dedupName)
+	}
+
+	// Remove link prefix.
+	linkSuffix := containerLinkName
+	for _, prefix := range opts.ContainerLinkPrefixes {
+		if strings.HasPrefix(containerLinkName, prefix) {
+			linkSuffix = strings.TrimPrefix(linkSuffix, prefix)
+			trace.Step(fmt.Sprintf("Strip container link prefix %q from %q", prefix, containerLinkName), linkSuffix)
+			break
+		}
+	}
+	if linkSuffix == containerLinkName {
+		trace.Step(fmt.Sprintf("No container link prefix matches %q, use it as is", containerLinkName), linkSuffix)
+	}
+
+	// Cut the morphed name to fit the maximum length.
+	contNameMaxLen := maxLength - len(linkSuffix) - len(opts.LinkIndexSeparator) - len(opts.LinkNamePrefix)
+	budget := fmt.Sprintf("%d bytes = %d (IFNAMSIZ-1) - %d (link prefix %q) - %d (separator %q) - %d (link suffix %q)",
+		contNameMaxLen, maxLength, len(opts.LinkNamePrefix), opts.LinkNamePrefix,
+		len(opts.LinkIndexSeparator), opts.LinkIndexSeparator, len(linkSuffix), linkSuffix)
+	if contNameMaxLen < 1 {
+		trace.Step("No room left for the container name: "+budget, "")
+		return "", ErrLinkSuffixTooLong
+	}
+	if len(morphedName) > contNameMaxLen {
+		trace.Step(fmt.Sprintf("Truncate %q (%d bytes) to %s", morphedName, len(morphedName), budget), morphedName[:contNameMaxLen])
+		morphedName = morphedName[:contNameMaxLen]
+	} else {
+		trace.Step(fmt.Sprintf("No truncation, %d bytes fit into %s", len(morphedName), budget), morphedName)
+	}
+
+	linkName := fmt.Sprintf("%s%s%s%s", opts.LinkNamePrefix, morphedName, opts.LinkIndexSeparator, linkSuffix)
+	trace.Step("Assemble link prefix, container name, separator, and link suffix", linkName)
+	return linkName, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Aleksei Ilin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidText(t *testing.T) {
+	assert.True(t, IsValidText(""))
+	assert.True(t, IsValidText("a-b_c.d"))
+	assert.False(t, IsValidText("a/b"))
+	assert.False(t, IsValidText("a:b"))
+	assert.False(t, IsValidText("a b"))
+	assert.False(t, IsValidText("ä"))
+}
+
+func TestReplace(t *testing.T) {
+	replacements := []map[string]string{{"server": "srv"}, {"-": ""}, {"srv": "x"}}
+	assert.Equal(t, "postgressrvprimary", Replace("postgres-server-primary", replacements, nil))
+
+	trace := &Trace{}
+	Replace("web", replacements, trace)
+	require.Len(t, trace.Steps, 3)
+	assert.Equal(t, `Rule "server" => "srv": not matched`, trace.Steps[0].Description)
+}
+
+func TestLinkName(t *testing.T) {
+	opts := Options{
+		LinkNamePrefix:          "v",
+		ContainerLinkPrefixes:   []string{"eth"},
+		Replacements:            []map[string]string{{"-": ""}},
+		RemoveDuplicatedSymbols: true,
+	}
+
+	name, err := LinkName("/project/postgres-server-primary", "eth0", opts, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "vpostgreserver0", name)
+	assert.Len(t, name, MaxLength)
+
+	opts.LinkIndexSeparator = "_"
+	opts.MaxLength = 8
+	name, err = LinkName("web", "eth10", opts, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "vweb_10", name)
+
+	_, err = LinkName("web", "very-long-link-name", opts, nil)
+	assert.ErrorIs(t, err, ErrLinkSuffixTooLong)
+
+	_, err = LinkName("", "eth0", opts, nil)
+	assert.ErrorIs(t, err, ErrEmptyName)
+}